@@ -0,0 +1,118 @@
+package kit
+
+/* =============================================================================
+   AGGREGATIONS
+   Array reductions that skip elements they cannot use: Sum and Avg consider
+   Number or Duration elements, Min and Max Number, Time, Duration or String
+   elements ordered by Less. Elements of more than one usable kind make the
+   result Invalid, as they have no common order or unit. The By variants
+   aggregate the Value at a dot-separated path of each element.
+   ============================================================================= */
+
+// Count returns the number of elements that are not Nil or Invalid.
+func (v Value) Count() int {
+	a, _ := v.elements()
+	n := 0
+	for _, e := range a {
+		if !e.Force().IsBlank() {
+			n++
+		}
+	}
+	return n
+}
+
+// Sum adds the Number or Duration elements: the result is a Number (0 for
+// none) or a Duration of their total. An Array holding both gives Invalid,
+// as nanoseconds and plain numbers do not add up.
+func (v Value) Sum() Value { return v.SumBy("") }
+
+// Avg is the arithmetic mean of the elements Sum considers, or Nil for
+// none. Like Sum it is Invalid when Numbers and Durations mix.
+func (v Value) Avg() Value { return v.AvgBy("") }
+
+// Min returns the smallest Number, Time, Duration or String element, or Nil
+// when there is none. Elements of more than one of those kinds give
+// Invalid.
+func (v Value) Min() Value { return v.MinBy("") }
+
+// Max returns the largest element as Min picks the smallest.
+func (v Value) Max() Value { return v.MaxBy("") }
+
+// SumBy is Sum over the Value at path in each element.
+func (v Value) SumBy(path string) Value {
+	sum, n, kind := v.total(path)
+	switch {
+	case n == 0:
+		return Value{K: Number}
+	case kind == Invalid:
+		return Value{K: Invalid}
+	}
+	return Value{K: kind, N: sum}
+}
+
+// AvgBy is Avg over the Value at path in each element.
+func (v Value) AvgBy(path string) Value {
+	sum, n, kind := v.total(path)
+	switch {
+	case n == 0:
+		return Value{K: Nil}
+	case kind == Invalid:
+		return Value{K: Invalid}
+	}
+	return Value{K: kind, N: sum / float64(n)}
+}
+
+// MinBy is Min over the Value at path in each element.
+func (v Value) MinBy(path string) Value {
+	return v.extreme(path, func(x, best Value) bool { return x.Less(best) })
+}
+
+// MaxBy is Max over the Value at path in each element.
+func (v Value) MaxBy(path string) Value {
+	return v.extreme(path, func(x, best Value) bool { return best.Less(x) })
+}
+
+// total adds the Numbers or Durations at path; kind is Invalid when both
+// occur.
+func (v Value) total(path string) (sum float64, n int, kind Kind) {
+	a, _ := v.elements()
+	for _, e := range a {
+		x := e.Path(path)
+		if x.K != Number && x.K != Duration {
+			continue
+		}
+		switch {
+		case n == 0:
+			kind = x.K
+		case x.K != kind:
+			kind = Invalid
+		}
+		sum += x.N
+		n++
+	}
+	return sum, n, kind
+}
+
+// extreme returns the best of the orderable Values at path, or Invalid when
+// their kinds differ.
+func (v Value) extreme(path string, better func(x, best Value) bool) Value {
+	a, _ := v.elements()
+	best := Value{K: Nil}
+	for _, e := range a {
+		x := e.Path(path)
+		switch x.K {
+		case Number, Time, Duration, String:
+		default:
+			continue
+		}
+		switch {
+		case best.IsNil():
+			best = x
+		case x.K != best.K:
+			return Value{K: Invalid}
+		case better(x, best):
+			best = x
+		}
+	}
+	return best
+}
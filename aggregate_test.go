@@ -0,0 +1,46 @@
+package kit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValue_Aggregations(t *testing.T) {
+	nums := New([]any{3, nil, 1.5, "x", 4})
+	if got := nums.Sum(); got.K != Number || got.N != 8.5 {
+		t.Errorf("Sum = %+v", got)
+	}
+	if got := nums.Avg(); got.N != 8.5/3 {
+		t.Errorf("Avg = %+v", got)
+	}
+	if ordered := New([]any{3, nil, 1.5, true, 4}); ordered.Min().N != 1.5 || ordered.Max().N != 4 || nums.Count() != 4 {
+		t.Errorf("Min/Max/Count = %v/%v/%v", ordered.Min(), ordered.Max(), nums.Count())
+	}
+	// Strings and Numbers have no common order, whichever comes first.
+	for _, mixed := range []Value{nums, New([]any{"a", 1}), New([]any{1, "a"}), New([]any{time.Second, 2})} {
+		if mixed.Min().K != Invalid || mixed.Max().K != Invalid {
+			t.Errorf("Min/Max of %v = %v/%v, want Invalid", mixed, mixed.Min(), mixed.Max())
+		}
+	}
+
+	items := New([]map[string]any{
+		{"price": 10, "took": time.Second},
+		{"price": 5, "took": 3 * time.Second},
+		{"name": "free"},
+	})
+	if items.SumBy("price").Int() != 15 || items.MaxBy("price").Int() != 10 || items.MinBy("price").Int() != 5 {
+		t.Errorf("By-path aggregates = %v %v %v", items.SumBy("price"), items.MaxBy("price"), items.MinBy("price"))
+	}
+	if got := items.AvgBy("took"); got.K != Duration || got.N != float64(2*time.Second) {
+		t.Errorf("AvgBy(took) = %+v", got)
+	}
+
+	if mixed := New([]any{1, time.Second}); mixed.Sum().K != Invalid || mixed.Avg().K != Invalid {
+		t.Errorf("Sum/Avg of Numbers and Durations = %v/%v, want Invalid", mixed.Sum(), mixed.Avg())
+	}
+
+	empty := New([]int{})
+	if !empty.Avg().IsNil() || !empty.Max().IsNil() || empty.Sum().N != 0 {
+		t.Error("aggregates of an empty Array")
+	}
+}
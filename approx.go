@@ -0,0 +1,118 @@
+package kit
+
+import "math"
+
+/* =============================================================================
+   APPROXIMATE EQUALITY
+   ============================================================================= */
+
+type approxOptions struct {
+	ignoreOrder bool
+	allowExtra  bool
+}
+
+// ApproxOption relaxes EqualApprox.
+type ApproxOption func(*approxOptions)
+
+// IgnoreOrder compares Arrays as multisets, matching each element of one
+// Array with a distinct approximately equal element of the other.
+func IgnoreOrder() ApproxOption {
+	return func(o *approxOptions) { o.ignoreOrder = true }
+}
+
+// AllowExtra lets Maps in the other Value carry keys the receiver lacks, so
+// an expected result can list only the fields it cares about.
+func AllowExtra() ApproxOption {
+	return func(o *approxOptions) { o.allowExtra = true }
+}
+
+// EqualApprox is a deep Equal in which Numbers match when they differ by at
+// most epsilon. NaN matches NaN and infinities match only themselves; other
+// kinds compare exactly.
+func (a Value) EqualApprox(b Value, epsilon float64, opts ...ApproxOption) bool {
+	var o approxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return a.equalApprox(b, epsilon, &o)
+}
+
+func (a Value) equalApprox(b Value, epsilon float64, o *approxOptions) bool {
+	a, b = a.Force(), b.Force()
+	if a.K != b.K {
+		return false
+	}
+	switch a.K {
+	case Number:
+		if a.N == b.N || math.IsNaN(a.N) && math.IsNaN(b.N) {
+			return true
+		}
+		return math.Abs(a.N-b.N) <= epsilon
+	case Array:
+		x, y := a.V.([]Value), b.V.([]Value)
+		if len(x) != len(y) {
+			return false
+		}
+		if !o.ignoreOrder {
+			for i := range x {
+				if !x[i].equalApprox(y[i], epsilon, o) {
+					return false
+				}
+			}
+			return true
+		}
+		return matchAll(x, y, func(e, f Value) bool { return e.equalApprox(f, epsilon, o) })
+	case Map:
+		x, y := a.mapping(), b.mapping()
+		if len(x) != len(y) && !(o.allowExtra && len(x) < len(y)) {
+			return false
+		}
+		for k, xv := range x {
+			yv, ok := y[k]
+			if !ok || !xv.equalApprox(yv, epsilon, o) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equal(b)
+	}
+}
+
+// matchAll reports whether every element of x can be paired with a distinct
+// element of y under eq. Approximate equality is not transitive, so a
+// greedy pairing can miss a perfect one; augmenting paths find it.
+func matchAll(x, y []Value, eq func(e, f Value) bool) bool {
+	adj := make([][]int, len(x))
+	for i, e := range x {
+		for j, f := range y {
+			if eq(e, f) {
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+	owner := make([]int, len(y)) // index in x matched to each y, or -1
+	for j := range owner {
+		owner[j] = -1
+	}
+	var augment func(i int, seen []bool) bool
+	augment = func(i int, seen []bool) bool {
+		for _, j := range adj[i] {
+			if seen[j] {
+				continue
+			}
+			seen[j] = true
+			if owner[j] < 0 || augment(owner[j], seen) {
+				owner[j] = i
+				return true
+			}
+		}
+		return false
+	}
+	for i := range x {
+		if !augment(i, make([]bool, len(y))) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,41 @@
+package kit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestValue_EqualApprox(t *testing.T) {
+	got := New(map[string]any{"total": 0.1 + 0.2, "items": []any{1.0000001, "a"}, "id": 7})
+	want := New(map[string]any{"total": 0.3, "items": []any{1, "a"}, "id": 7})
+	if got.Equal(want) || !got.EqualApprox(want, 1e-6) {
+		t.Error("EqualApprox should absorb float error")
+	}
+	if got.EqualApprox(want, 1e-9) {
+		t.Error("EqualApprox should respect epsilon")
+	}
+
+	tests := []struct {
+		a, b Value
+		opts []ApproxOption
+		want bool
+	}{
+		{New([]any{1, 2}), New([]any{2, 1}), nil, false},
+		{New([]any{1, 2, 2}), New([]any{2, 1.0001, 2}), []ApproxOption{IgnoreOrder()}, true},
+		{New([]any{1, 1}), New([]any{1, 2}), []ApproxOption{IgnoreOrder()}, false},
+		// 1.0006 could claim 1.0, which only 1.0 itself can match.
+		{New([]any{1.0006, 1.0}), New([]any{1.0, 1.0012}), []ApproxOption{IgnoreOrder()}, true},
+		{New(map[string]any{"a": 1}), New(map[string]any{"a": 1, "b": 2}), nil, false},
+		{New(map[string]any{"a": 1}), New(map[string]any{"a": 1, "b": 2}), []ApproxOption{AllowExtra()}, true},
+		{New(map[string]any{"a": 1, "b": 2}), New(map[string]any{"a": 1}), []ApproxOption{AllowExtra()}, false},
+		{New(math.NaN()), New(math.NaN()), nil, true},
+		{New(math.Inf(1)), New(math.Inf(1)), nil, true},
+		{New(math.Inf(1)), New(math.MaxFloat64), nil, false},
+		{New("1"), New(1), nil, false},
+	}
+	for i, tt := range tests {
+		if got := tt.a.EqualApprox(tt.b, 1e-3, tt.opts...); got != tt.want {
+			t.Errorf("%d: EqualApprox(%v, %v) = %v, want %v", i, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+/* =============================================================================
+   ARITHMETIC POLICY
+   Value.Add, Sub, Mul, Div and the integer operations are lenient:
+   unsupported operands give Invalid and division by zero gives Nil. Arith
+   performs the same operations under an explicit policy for code that
+   must fail loudly.
+   ============================================================================= */
+
+var (
+	ErrDivByZero = errors.New("kit: division by zero")
+	ErrInvalidOp = errors.New("kit: invalid operation")
+	ErrOverflow  = errors.New("kit: numeric overflow")
+)
+
+// NewError wraps err as a Value of the Error kind; a nil err gives Nil.
+func NewError(err error) Value {
+	if err == nil {
+		return Value{K: Nil}
+	}
+	return Value{K: Error, V: err}
+}
+
+// IsError reports whether v is of the Error kind.
+func (v Value) IsError() bool { return v.K == Error }
+
+// Err returns the error held by an Error Value, or nil for other kinds.
+func (v Value) Err() error {
+	if v.K != Error {
+		return nil
+	}
+	err, _ := v.V.(error)
+	return err
+}
+
+// DivZero selects what dividing by zero produces.
+type DivZero uint8
+
+const (
+	DivZeroNil   DivZero = iota // Nil, as Value.Div does
+	DivZeroIEEE                 // ±Inf, or NaN for 0/0, as float64 does
+	DivZeroError                // an Error wrapping ErrDivByZero
+)
+
+// Arith is an arithmetic policy. The zero Arith behaves exactly like the
+// Value methods.
+type Arith struct {
+	DivZero DivZero
+
+	// Errors makes operations on unsupported kinds return an Error wrapping
+	// ErrInvalidOp instead of Invalid.
+	Errors bool
+
+	// Overflow makes a Number result an Error wrapping ErrOverflow when it
+	// is infinite from finite operands, or when integral operands produce a
+	// result outside ±(2^53-1), beyond which float64 cannot represent every
+	// integer and sums silently round.
+	Overflow bool
+}
+
+// Add is Value.Add under the policy. Error operands are returned as is, so
+// the first failure in a chain of operations is the one reported.
+func (p Arith) Add(a, b Value) Value {
+	return p.apply("+", a, b, Value.Add)
+}
+
+// Sub is Value.Sub under the policy.
+func (p Arith) Sub(a, b Value) Value {
+	return p.apply("-", a, b, Value.Sub)
+}
+
+// Mul is Value.Mul under the policy.
+func (p Arith) Mul(a, b Value) Value {
+	return p.apply("*", a, b, Value.Mul)
+}
+
+// Div is Value.Div under the policy.
+func (p Arith) Div(a, b Value) Value {
+	return p.apply("/", a, b, p.divide("/", Value.Div, func(a, b float64) float64 { return a / b }))
+}
+
+// IntDiv is Value.IntDiv under the policy; DivZeroIEEE gives ±Inf.
+func (p Arith) IntDiv(a, b Value) Value {
+	return p.apply("div", a, b, p.divide("div", Value.IntDiv, func(a, b float64) float64 { return a / b }))
+}
+
+// Mod is Value.Mod under the policy; DivZeroIEEE gives NaN.
+func (p Arith) Mod(a, b Value) Value {
+	return p.apply("%", a, b, p.divide("%", Value.Mod, math.Mod))
+}
+
+// divide applies the DivZero policy around a lenient division fn, using
+// ieee for DivZeroIEEE.
+func (p Arith) divide(op string, fn func(a, b Value) Value, ieee func(a, b float64) float64) func(a, b Value) Value {
+	return func(a, b Value) Value {
+		if a.K != Number || b.K != Number || b.N != 0 || p.DivZero == DivZeroNil || fn(a, b).K == Invalid {
+			return fn(a, b)
+		}
+		if p.DivZero == DivZeroError {
+			return NewError(fmt.Errorf("%w: %s %s 0", ErrDivByZero, a.Text(), op))
+		}
+		return Value{K: Number, N: ieee(a.N, b.N)}
+	}
+}
+
+// And is Value.And under the policy.
+func (p Arith) And(a, b Value) Value { return p.apply("&", a, b, Value.And) }
+
+// Or is Value.Or under the policy.
+func (p Arith) Or(a, b Value) Value { return p.apply("|", a, b, Value.Or) }
+
+// Xor is Value.Xor under the policy.
+func (p Arith) Xor(a, b Value) Value { return p.apply("^", a, b, Value.Xor) }
+
+// Shl is Value.Shl under the policy.
+func (p Arith) Shl(a, b Value) Value { return p.apply("<<", a, b, Value.Shl) }
+
+// Shr is Value.Shr under the policy.
+func (p Arith) Shr(a, b Value) Value { return p.apply(">>", a, b, Value.Shr) }
+
+func (p Arith) apply(op string, a, b Value, fn func(a, b Value) Value) Value {
+	a, b = a.Force(), b.Force()
+	if a.K == Error {
+		return a
+	}
+	if b.K == Error {
+		return b
+	}
+	r := fn(a, b)
+	switch {
+	case r.K == Invalid && p.Errors:
+		return NewError(fmt.Errorf("%w: %s %s %s", ErrInvalidOp, a.K, op, b.K))
+	case r.K == Number && p.Overflow && overflowed(a, b, r.N):
+		return NewError(fmt.Errorf("%w: %s %s %s", ErrOverflow, a.Text(), op, b.Text()))
+	}
+	return r
+}
+
+const maxSafeInt = 1<<53 - 1
+
+func overflowed(a, b Value, r float64) bool {
+	finite := func(n float64) bool { return !math.IsInf(n, 0) && !math.IsNaN(n) }
+	if math.IsInf(r, 0) {
+		return finite(a.N) && finite(b.N)
+	}
+	integral := func(n float64) bool { return n == math.Trunc(n) && math.Abs(n) <= maxSafeInt }
+	return integral(a.N) && integral(b.N) && math.Abs(r) > maxSafeInt
+}
@@ -0,0 +1,64 @@
+package kit
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestArith(t *testing.T) {
+	var lenient Arith
+	if !lenient.Div(New(1), New(0)).IsNil() || !lenient.Mul(New("a"), New(2)).IsInvalid() {
+		t.Error("zero Arith should match the Value methods")
+	}
+
+	ieee := Arith{DivZero: DivZeroIEEE}
+	if r := ieee.Div(New(-1), New(0)); !math.IsInf(r.N, -1) {
+		t.Errorf("IEEE -1/0 = %v", r)
+	}
+	if r := ieee.Mod(New(1), New(0)); !math.IsNaN(r.N) {
+		t.Errorf("IEEE 1%%0 = %v", r)
+	}
+	if r := ieee.IntDiv(New(1.5), New(0)); !r.IsInvalid() {
+		t.Errorf("IEEE 1.5 div 0 = %v, want Invalid", r)
+	}
+
+	strict := Arith{DivZero: DivZeroError, Errors: true, Overflow: true}
+	tests := []struct {
+		got  Value
+		want error
+	}{
+		{strict.Div(New(10), New(0)), ErrDivByZero},
+		{strict.Mul(New("a"), New(2)), ErrInvalidOp},
+		{strict.Mul(New(math.MaxFloat64), New(2)), ErrOverflow},
+		{strict.Add(New(1<<53-1), New(1)), ErrOverflow},
+		{strict.Add(strict.Div(New(1), New(0)), New(1)), ErrDivByZero},
+		{strict.IntDiv(New(1), New(0)), ErrDivByZero},
+		{strict.Mod(New(1), New(0)), ErrDivByZero},
+		{strict.And(New(1.5), New(1)), ErrInvalidOp},
+		{strict.Shl(New(1), New(60)), ErrOverflow},
+	}
+	for i, tt := range tests {
+		if !tt.got.IsError() || !errors.Is(tt.got.Err(), tt.want) {
+			t.Errorf("%d: got %v, want %v", i, tt.got, tt.want)
+		}
+	}
+	if r := strict.Add(New(0.5), New(1<<53)); r.IsError() {
+		t.Errorf("non-integral operands should not overflow: %v", r)
+	}
+	if r := strict.Div(New(10), New(4)); r.N != 2.5 {
+		t.Errorf("10/4 = %v", r)
+	}
+
+	e := strict.Div(New(1), New(0))
+	if e.Truthy() || e.Text() != "kit: division by zero: 1 / 0" || New(1).Err() != nil ||
+		(Value{K: Error}).Text() != "error" {
+		t.Errorf("Error value: %q", e.Text())
+	}
+	if _, err := e.MarshalJSON(); !errors.Is(err, ErrDivByZero) {
+		t.Errorf("MarshalJSON err = %v", err)
+	}
+	if !NewError(nil).IsNil() {
+		t.Error("NewError(nil) should be Nil")
+	}
+}
@@ -0,0 +1,99 @@
+package kit
+
+import "sync"
+
+/* =============================================================================
+   EVENT BUS
+   Topics are dot-separated paths ("orders.created"). Subscription patterns
+   use "*" for exactly one segment and "**" for any number of segments.
+   ============================================================================= */
+
+// Handler receives a published Value together with its concrete topic.
+type Handler func(topic string, v Value)
+
+type subscription struct {
+	id      uint64
+	pattern []string
+	fn      Handler
+}
+
+// Bus is a synchronous, path-keyed publish/subscribe hub.
+// The zero value is ready to use and safe for concurrent use.
+type Bus struct {
+	mu   sync.RWMutex
+	next uint64
+	subs []subscription
+}
+
+// Subscribe registers fn for every topic matching pattern and returns a
+// function that removes the subscription.
+func (b *Bus) Subscribe(pattern string, fn Handler) (cancel func()) {
+	b.mu.Lock()
+	b.next++
+	id := b.next
+	b.subs = append(b.subs, subscription{id: id, pattern: splitPath(pattern), fn: fn})
+	b.mu.Unlock()
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for i, s := range b.subs {
+			if s.id == id {
+				b.subs = append(b.subs[:i:i], b.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Publish delivers v to every matching subscriber, in subscription order,
+// on the calling goroutine. It returns the number of handlers invoked.
+func (b *Bus) Publish(topic string, v Value) int {
+	path := splitPath(topic)
+
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	n := 0
+	for _, s := range subs {
+		if matchSegments(s.pattern, path) {
+			s.fn(topic, v)
+			n++
+		}
+	}
+	return n
+}
+
+// MatchPath reports whether a dot-separated path matches a glob pattern
+// where "*" matches one segment and "**" matches zero or more.
+func MatchPath(pattern, path string) bool {
+	return matchSegments(splitPath(pattern), splitPath(path))
+}
+
+// matchSegments is the classic wildcard match with "**" as the star and
+// "*" as a single-segment wildcard. Backtracking only to the latest "**"
+// keeps it O(len(pattern) * len(path)) even for hostile patterns.
+func matchSegments(pattern, path []string) bool {
+	p, s := 0, 0
+	star, mark := -1, 0
+	for s < len(path) {
+		switch {
+		case p < len(pattern) && pattern[p] == "**":
+			star, mark = p, s
+			p++
+		case p < len(pattern) && (pattern[p] == "*" || pattern[p] == path[s]):
+			p++
+			s++
+		case star >= 0:
+			mark++
+			p, s = star+1, mark
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == "**" {
+		p++
+	}
+	return p == len(pattern)
+}
@@ -0,0 +1,58 @@
+package kit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"orders.created", "orders.created", true},
+		{"orders.*", "orders.created", true},
+		{"orders.*", "orders.created.eu", false},
+		{"orders.**", "orders.created.eu", true},
+		{"orders.**", "orders", true},
+		{"**.token", "auth.session.token", true},
+		{"*.created", "users.deleted", false},
+		{"a.**.b.*", "a.x.b.y.b.z", true},
+		{"**.*.c", "c", false},
+		{strings.Repeat("**.", 40) + "x", strings.Repeat("a.", 40) + "b", false},
+	}
+	for _, tt := range tests {
+		if got := MatchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("MatchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestBus_PublishSubscribe(t *testing.T) {
+	var bus Bus
+	var got []string
+
+	cancel := bus.Subscribe("orders.*", func(topic string, v Value) {
+		got = append(got, topic+"="+v.Text())
+	})
+	bus.Subscribe("users.**", func(topic string, v Value) {
+		got = append(got, "user:"+topic)
+	})
+
+	if n := bus.Publish("orders.created", New(7)); n != 1 {
+		t.Errorf("Publish delivered to %d handlers, want 1", n)
+	}
+	bus.Publish("users.login", New(nil))
+	cancel()
+	bus.Publish("orders.created", New(8))
+
+	want := []string{"orders.created=7", "user:users.login"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("event %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
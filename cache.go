@@ -0,0 +1,220 @@
+package kit
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+/* =============================================================================
+   CACHE
+   An in-process LRU cache of Values bounded by age, entry count and
+   approximate memory, with loads of a missing key shared between the
+   callers asking for it at the same time.
+   ============================================================================= */
+
+// SizeOf estimates the memory held by v in bytes: the Value itself plus
+// String and Bytes content and, recursively, container elements and Map
+// keys. Struct, Func and Any contents are not counted.
+func SizeOf(v Value) int64 {
+	const header = int64(unsafe.Sizeof(Value{}))
+	v = v.Force()
+	switch v.K {
+	case String:
+		return header + int64(len(v.String()))
+	case Bytes:
+		return header + int64(cap(v.Bytes()))
+	case Array:
+		n := header
+		for _, e := range v.V.([]Value) {
+			n += SizeOf(e)
+		}
+		return n
+	case Map:
+		const entry = int64(unsafe.Sizeof("")) + 8 // key header and bucket slot
+		n := header
+		for k, e := range v.mapping() {
+			n += entry + int64(len(k)) + SizeOf(e)
+		}
+		return n
+	}
+	return header
+}
+
+type cacheOptions struct {
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int64
+}
+
+// CacheOption configures NewCache.
+type CacheOption func(*cacheOptions)
+
+// CacheTTL expires entries d after they are stored; without it entries
+// never expire.
+func CacheTTL(d time.Duration) CacheOption {
+	return func(o *cacheOptions) { o.ttl = d }
+}
+
+// CacheMaxEntries evicts the least recently used entries beyond n.
+func CacheMaxEntries(n int) CacheOption {
+	return func(o *cacheOptions) { o.maxEntries = n }
+}
+
+// CacheMaxBytes evicts the least recently used entries once their SizeOf
+// total exceeds n. A single Value larger than n is not stored.
+func CacheMaxBytes(n int64) CacheOption {
+	return func(o *cacheOptions) { o.maxBytes = n }
+}
+
+// Cache is a concurrency-safe LRU cache of Values keyed by string. Values
+// can serve as keys through their CanonicalJSON.
+type Cache struct {
+	o     cacheOptions
+	mu    sync.Mutex
+	lru   list.List // of *cacheEntry, most recently used first
+	m     map[string]*list.Element
+	bytes int64
+	loads map[string]*cacheLoad
+}
+
+type cacheEntry struct {
+	key     string
+	v       Value
+	size    int64
+	expires time.Time
+}
+
+// errCacheLoadPanic is what callers waiting on a load see when it panics;
+// the panic itself propagates in the goroutine that ran it.
+var errCacheLoadPanic = errors.New("kit: cache load panicked")
+
+type cacheLoad struct {
+	done chan struct{}
+	v    Value
+	err  error
+}
+
+// NewCache returns an empty Cache; without options it is unbounded.
+func NewCache(opts ...CacheOption) *Cache {
+	c := &Cache{m: make(map[string]*list.Element), loads: make(map[string]*cacheLoad)}
+	for _, opt := range opts {
+		opt(&c.o)
+	}
+	return c
+}
+
+// Get returns the Value stored under key and whether it was found and
+// had not expired.
+func (c *Cache) Get(key string) (Value, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.m[key]
+	if !ok {
+		return Value{K: Invalid}, false
+	}
+	e := el.Value.(*cacheEntry)
+	if !e.expires.IsZero() && !now().Before(e.expires) {
+		c.remove(el)
+		return Value{K: Invalid}, false
+	}
+	c.lru.MoveToFront(el)
+	return e.v, true
+}
+
+// Set stores v under key, replacing any previous Value, and evicts least
+// recently used entries until the Cache is within its bounds.
+func (c *Cache) Set(key string, v Value) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, v)
+}
+
+func (c *Cache) set(key string, v Value) {
+	if el, ok := c.m[key]; ok {
+		c.remove(el)
+	}
+	e := &cacheEntry{key: key, v: v, size: SizeOf(v)}
+	if c.o.maxBytes > 0 && e.size > c.o.maxBytes {
+		return
+	}
+	if c.o.ttl > 0 {
+		e.expires = now().Add(c.o.ttl)
+	}
+	c.m[key] = c.lru.PushFront(e)
+	c.bytes += e.size
+	for c.o.maxEntries > 0 && c.lru.Len() > c.o.maxEntries || c.o.maxBytes > 0 && c.bytes > c.o.maxBytes {
+		c.remove(c.lru.Back())
+	}
+}
+
+func (c *Cache) remove(el *list.Element) {
+	e := c.lru.Remove(el).(*cacheEntry)
+	delete(c.m, e.key)
+	c.bytes -= e.size
+}
+
+// Delete removes key. Missing keys are not an error.
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.m[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Len returns the number of stored entries, including expired ones not
+// yet removed.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Bytes returns the SizeOf total of the stored entries.
+func (c *Cache) Bytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.bytes
+}
+
+// GetOrLoad returns the Value stored under key, calling load to produce
+// and store it when it is missing. Concurrent callers for the same key
+// share a single call of load and its result. Errors are returned to
+// every waiting caller and are not cached.
+func (c *Cache) GetOrLoad(key string, load func() (Value, error)) (Value, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+	c.mu.Lock()
+	if l, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		<-l.done
+		return l.v, l.err
+	}
+	l := &cacheLoad{done: make(chan struct{})}
+	c.loads[key] = l
+	c.mu.Unlock()
+
+	finished := false
+	defer func() {
+		if !finished {
+			l.v, l.err = Value{K: Invalid}, errCacheLoadPanic
+		}
+		c.mu.Lock()
+		delete(c.loads, key)
+		if l.err == nil {
+			c.set(key, l.v)
+		}
+		c.mu.Unlock()
+		close(l.done)
+	}()
+	l.v, l.err = load()
+	if l.err != nil {
+		l.v = Value{K: Invalid}
+	}
+	finished = true
+	return l.v, l.err
+}
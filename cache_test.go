@@ -0,0 +1,114 @@
+package kit
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSizeOf(t *testing.T) {
+	small := SizeOf(New("ab"))
+	if SizeOf(New("abcd")) != small+2 {
+		t.Error("String content should count byte for byte")
+	}
+	arr := SizeOf(New([]string{"ab", "ab"}))
+	if arr <= 2*small {
+		t.Errorf("Array of two = %d, want more than %d", arr, 2*small)
+	}
+	if SizeOf(New(map[string]any{"k": "ab"})) <= small+1 {
+		t.Error("Map entries should count their keys")
+	}
+}
+
+func TestCache(t *testing.T) {
+	ref := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return ref }
+	defer func() { now = time.Now }()
+
+	c := NewCache(CacheTTL(time.Minute), CacheMaxEntries(2))
+	c.Set("a", New(1))
+	c.Set("b", New(2))
+	c.Get("a") // b is now least recently used
+	c.Set("c", New(3))
+	if _, ok := c.Get("b"); ok || c.Len() != 2 {
+		t.Errorf("b should be evicted, Len = %d", c.Len())
+	}
+	if v, ok := c.Get("a"); !ok || v.Int() != 1 {
+		t.Errorf("Get(a) = %v, %v", v, ok)
+	}
+
+	ref = ref.Add(time.Minute)
+	if _, ok := c.Get("a"); ok {
+		t.Error("a should have expired")
+	}
+	c.Delete("c")
+	if c.Len() != 0 || c.Bytes() != 0 {
+		t.Errorf("Len = %d, Bytes = %d after expiry and Delete", c.Len(), c.Bytes())
+	}
+
+	big := New([]string{"0123456789", "0123456789"})
+	c = NewCache(CacheMaxBytes(SizeOf(big) + SizeOf(New(1))))
+	c.Set("big", big)
+	c.Set("n", New(1))
+	c.Set("m", New(2))
+	if _, ok := c.Get("big"); ok || c.Len() != 2 {
+		t.Errorf("big should be evicted by size, Len = %d", c.Len())
+	}
+	c.Set("huge", New(make([]int, 100)))
+	if _, ok := c.Get("huge"); ok {
+		t.Error("a Value over the byte limit should not be stored")
+	}
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	c := NewCache()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func() (Value, error) {
+		calls.Add(1)
+		<-release
+		return New("v"), nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]Value, 8)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = c.GetOrLoad("k", load)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if n := calls.Load(); n != 1 {
+		t.Errorf("load ran %d times, want 1", n)
+	}
+	for _, r := range results {
+		if r.String() != "v" {
+			t.Errorf("result = %v", r)
+		}
+	}
+
+	boom := errors.New("boom")
+	if _, err := c.GetOrLoad("e", func() (Value, error) { return Value{}, boom }); err != boom {
+		t.Errorf("err = %v", err)
+	}
+	if _, ok := c.Get("e"); ok {
+		t.Error("errors should not be cached")
+	}
+
+	func() {
+		defer func() { recover() }()
+		c.GetOrLoad("p", func() (Value, error) { panic("load") })
+	}()
+	if _, ok := c.Get("p"); ok {
+		t.Error("a panicking load should not be cached")
+	}
+	if v, err := c.GetOrLoad("p", func() (Value, error) { return New(1), nil }); err != nil || v.Int() != 1 {
+		t.Errorf("load after a panic = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,87 @@
+package kit
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+/* =============================================================================
+   CANONICAL FORM
+   ============================================================================= */
+
+// Canonicalize returns a copy of v in canonical form, so that Values which
+// mean the same thing encode to the same bytes in every process:
+//
+//   - Strings and Map keys are NFC-normalized; keys that collide after
+//     normalization keep the value of the last one in sorted order
+//   - -0 becomes 0 and Times are moved to UTC
+//   - lazy Values are forced and sharded Maps flattened
+//
+// Struct and Any kinds are left as they are.
+func (v Value) Canonicalize() Value {
+	v = v.Force()
+	switch v.K {
+	case Number:
+		if v.N == 0 {
+			return Value{K: Number}
+		}
+	case Time:
+		return Value{K: Time, N: v.N, V: time.UTC}
+	case String:
+		return Value{K: String, V: NFC(v.String())}
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return v
+		}
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = e.Canonicalize()
+		}
+		return Value{K: Array, V: out}
+	case Map:
+		m := v.mapping()
+		if m == nil {
+			return Value{K: Map, V: m}
+		}
+		out := make(map[string]Value, len(m))
+		for _, k := range sortedKeys(m) {
+			out[NFC(k)] = m[k].Canonicalize()
+		}
+		return Value{K: Map, V: out}
+	}
+	return v
+}
+
+// CanonicalJSON returns the JSON encoding of v.Canonicalize(), suitable for
+// hashing, signing and diffing. NaN and ±Inf are an error, as in JSON.
+func (v Value) CanonicalJSON() ([]byte, error) {
+	c := v.Canonicalize()
+	if err := checkFinite(c); err != nil {
+		return nil, err
+	}
+	return c.AppendJSON(nil)
+}
+
+func checkFinite(v Value) error {
+	switch v.K {
+	case Number:
+		if math.IsNaN(v.N) || math.IsInf(v.N, 0) {
+			return fmt.Errorf("kit: cannot encode %v as canonical JSON", v.N)
+		}
+	case Array:
+		for _, e := range v.V.([]Value) {
+			if err := checkFinite(e); err != nil {
+				return err
+			}
+		}
+	case Map:
+		for _, e := range v.mapping() {
+			if err := checkFinite(e); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
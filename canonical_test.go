@@ -0,0 +1,38 @@
+package kit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValue_CanonicalJSON(t *testing.T) {
+	hcm := time.FixedZone("ICT", 7*3600)
+	a := New(map[string]any{
+		"name": "Vie\u0323\u0302t",
+		"zero": math.Copysign(0, -1),
+		"at":   time.Date(2024, 3, 5, 21, 30, 0, 0, hcm),
+		"tiny": 1e-7,
+		"lazy": Lazy(func() Value { return New([]any{1, 2}) }),
+	})
+	b := New(map[string]any{
+		"name": "Việt",
+		"zero": 0,
+		"at":   time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC),
+		"tiny": 1e-7,
+		"lazy": []any{1, 2},
+	})
+	ja, err := a.CanonicalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	jb, _ := b.CanonicalJSON()
+	want := `{"at":"2024-03-05T14:30:00Z","lazy":[1,2],"name":"Việt","tiny":1e-7,"zero":0}`
+	if string(ja) != want || string(jb) != want {
+		t.Errorf("CanonicalJSON:\n got %s\n and %s\nwant %s", ja, jb, want)
+	}
+
+	if _, err := New([]any{math.Inf(1)}).CanonicalJSON(); err == nil {
+		t.Error("CanonicalJSON should reject Inf")
+	}
+}
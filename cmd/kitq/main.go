@@ -0,0 +1,163 @@
+// Command kitq runs jq-like queries over JSON, YAML, TOML or CSV documents
+// using the kit Value model, so results match what services embedding kit
+// compute.
+//
+// Usage:
+//
+//	kitq [flags] [filter] [file ...]
+//
+// The filter defaults to "." and input is read from the files, or from
+// standard input when there are none. Each output is written on its own
+// line. For example:
+//
+//	kitq '.items[] | select(.ok) | .id' < orders.json
+//	kitq -i csv -c 'map(select(.age >= 18)) | length' people.csv
+//
+// Flags:
+//
+//	-i format   input format: auto, json, yaml, toml or csv (default auto,
+//	            which tells JSON, TOML and MessagePack from YAML as
+//	            kit.Decode does)
+//	-o format   output format: json or yaml (default json)
+//	-c          compact JSON output
+//	-r          write Strings without quotes
+//	-seed n     seed for shuffle and pick (default: the current time)
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/kitwork/kit"
+)
+
+func main() {
+	flags := flag.NewFlagSet("kitq", flag.ContinueOnError)
+	in := flags.String("i", "auto", "input format: auto, json, yaml, toml or csv")
+	out := flags.String("o", "json", "output format: json or yaml")
+	compact := flags.Bool("c", false, "compact JSON output")
+	raw := flags.Bool("r", false, "write Strings without quotes")
+	seed := flags.Int64("seed", time.Now().UnixNano(), "seed for shuffle and pick")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: kitq [flags] [filter] [file ...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+
+	rng = kit.NewRand(*seed)
+	opts := options{in: *in, out: *out, compact: *compact, raw: *raw}
+	if err := run(flags.Args(), os.Stdin, os.Stdout, opts); err != nil {
+		fmt.Fprintln(os.Stderr, "kitq:", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	in, out      string
+	compact, raw bool
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, o options) error {
+	src := "."
+	if len(args) > 0 {
+		src, args = args[0], args[1:]
+	}
+	f, err := compile(src)
+	if err != nil {
+		return fmt.Errorf("filter: %w", err)
+	}
+
+	w := bufio.NewWriter(stdout)
+	defer w.Flush()
+	query := func(name string, r io.Reader) error {
+		v, err := decode(r, o.in)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		results, err := f(v)
+		if err != nil {
+			return err
+		}
+		for _, x := range results {
+			if err := write(w, x, o); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return query("stdin", stdin)
+	}
+	for _, name := range args {
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		err = query(name, file)
+		file.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func decode(r io.Reader, format string) (kit.Value, error) {
+	if format == "csv" {
+		return kit.FromCSV(r, kit.CSVTypes())
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return kit.Value{}, err
+	}
+	switch format {
+	case "auto":
+		return kit.Decode(data, kit.FormatAuto)
+	case "json":
+		return kit.FromJSON(data)
+	case "yaml":
+		return kit.FromYAML(data)
+	case "toml":
+		return kit.FromTOML(data)
+	}
+	return kit.Value{}, fmt.Errorf("unknown input format %q", format)
+}
+
+func write(w io.Writer, v kit.Value, o options) error {
+	if o.raw && v.K == kit.String {
+		_, err := fmt.Fprintln(w, v.Text())
+		return err
+	}
+	switch o.out {
+	case "yaml":
+		b, err := v.AppendYAML([]byte("---\n"))
+		if err == nil {
+			_, err = w.Write(b)
+		}
+		return err
+	case "json":
+		b, err := v.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if !o.compact {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, b, "", "  "); err != nil {
+				return err
+			}
+			b = buf.Bytes()
+		}
+		_, err = w.Write(append(b, '\n'))
+		return err
+	}
+	return fmt.Errorf("unknown output format %q", o.out)
+}
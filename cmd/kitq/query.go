@@ -0,0 +1,694 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kitwork/kit"
+)
+
+/* =============================================================================
+   QUERIES
+   A jq-like filter language. A filter maps one input Value to a stream of
+   outputs; filters are compiled to closures once and then run per input.
+
+	.              identity
+	.a.b  ."x y"   fields of Maps
+	.[0]  .[-1]    Array elements, negative from the end
+	.[]            every element of an Array or value of a Map
+	f | g          run g on every output of f
+	f, g           the outputs of f, then those of g
+	[f]            collect the outputs of f into an Array
+	== != < <= > >=, and, or, not
+	select(f) map(f) sort_by(f) length keys values sort unique reverse
+	first last sum min max tostring tonumber type empty
+	floor ceil round sqrt fabs shuffle pick
+
+   As in jq, only false and null are false; 0 and "" are true.
+   ============================================================================= */
+
+type filter func(in kit.Value) ([]kit.Value, error)
+
+// compile parses src into a filter.
+func compile(src string) (filter, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+	f, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+	}
+	return f, nil
+}
+
+type tokKind uint8
+
+const (
+	tokEOF tokKind = iota
+	tokPunct
+	tokIdent
+	tokNumber
+	tokString
+)
+
+type token struct {
+	kind  tokKind
+	text  string
+	pos   int
+	glued bool // no space before the token
+}
+
+func lex(src string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		glued := i > 0 && !unicode.IsSpace(rune(src[i-1]))
+		start := i
+		switch {
+		case unicode.IsSpace(rune(c)):
+			i++
+			continue
+		case c == '"':
+			i++
+			for i < len(src) && src[i] != '"' {
+				if src[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", start)
+			}
+			i++
+			s, err := strconv.Unquote(src[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d", start)
+			}
+			toks = append(toks, token{tokString, s, start, glued})
+			continue
+		case c >= '0' && c <= '9':
+			for i < len(src) && (src[i] >= '0' && src[i] <= '9' || src[i] == '.' || src[i] == 'e' || src[i] == 'E') {
+				i++
+			}
+			toks = append(toks, token{tokNumber, src[start:i], start, glued})
+			continue
+		case c == '_' || unicode.IsLetter(rune(c)):
+			for i < len(src) && (src[i] == '_' || unicode.IsLetter(rune(src[i])) || unicode.IsDigit(rune(src[i]))) {
+				i++
+			}
+			toks = append(toks, token{tokIdent, src[start:i], start, glued})
+			continue
+		}
+		for _, op := range []string{"==", "!=", "<=", ">=", ".", "[", "]", "(", ")", "|", ",", ";", "<", ">", "-"} {
+			if strings.HasPrefix(src[i:], op) {
+				toks = append(toks, token{tokPunct, op, start, glued})
+				i += len(op)
+				break
+			}
+		}
+		if i == start {
+			return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src)}), nil
+}
+
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) peek() token { return p.toks[p.pos] }
+
+func (p *parser) next() token {
+	t := p.toks[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) accept(punct string) bool {
+	if t := p.peek(); t.kind == tokPunct && t.text == punct {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) error {
+	if !p.accept(punct) {
+		t := p.peek()
+		return fmt.Errorf("expected %q at offset %d", punct, t.pos)
+	}
+	return nil
+}
+
+func (p *parser) keyword(word string) bool {
+	if t := p.peek(); t.kind == tokIdent && t.text == word {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) pipe() (filter, error) {
+	f, err := p.comma()
+	for err == nil && p.accept("|") {
+		var g filter
+		if g, err = p.comma(); err == nil {
+			f = pipeOf(f, g)
+		}
+	}
+	return f, err
+}
+
+func pipeOf(f, g filter) filter {
+	return func(in kit.Value) ([]kit.Value, error) {
+		xs, err := f(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []kit.Value
+		for _, x := range xs {
+			ys, err := g(x)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, ys...)
+		}
+		return out, nil
+	}
+}
+
+func (p *parser) comma() (filter, error) {
+	f, err := p.or()
+	for err == nil && p.accept(",") {
+		var g filter
+		if g, err = p.or(); err == nil {
+			f = concat(f, g)
+		}
+	}
+	return f, err
+}
+
+func concat(f, g filter) filter {
+	return func(in kit.Value) ([]kit.Value, error) {
+		xs, err := f(in)
+		if err != nil {
+			return nil, err
+		}
+		ys, err := g(in)
+		return append(xs, ys...), err
+	}
+}
+
+func (p *parser) or() (filter, error) {
+	f, err := p.and()
+	for err == nil && p.keyword("or") {
+		var g filter
+		if g, err = p.and(); err == nil {
+			f = binary(f, g, func(a, b kit.Value) (kit.Value, error) {
+				return kit.New(truthy(a) || truthy(b)), nil
+			})
+		}
+	}
+	return f, err
+}
+
+func (p *parser) and() (filter, error) {
+	f, err := p.compare()
+	for err == nil && p.keyword("and") {
+		var g filter
+		if g, err = p.compare(); err == nil {
+			f = binary(f, g, func(a, b kit.Value) (kit.Value, error) {
+				return kit.New(truthy(a) && truthy(b)), nil
+			})
+		}
+	}
+	return f, err
+}
+
+var comparisons = map[string]func(c int) bool{
+	"<":  func(c int) bool { return c < 0 },
+	"<=": func(c int) bool { return c <= 0 },
+	">":  func(c int) bool { return c > 0 },
+	">=": func(c int) bool { return c >= 0 },
+}
+
+func (p *parser) compare() (filter, error) {
+	f, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokPunct {
+		return f, nil
+	}
+	var op func(a, b kit.Value) (kit.Value, error)
+	switch t.text {
+	case "==", "!=":
+		neq := t.text == "!="
+		op = func(a, b kit.Value) (kit.Value, error) { return kit.New(a.Equal(b) != neq), nil }
+	case "<", "<=", ">", ">=":
+		test := comparisons[t.text]
+		op = func(a, b kit.Value) (kit.Value, error) { return kit.New(test(a.Compare(b))), nil }
+	default:
+		return f, nil
+	}
+	p.pos++
+	g, err := p.postfix()
+	if err != nil {
+		return nil, err
+	}
+	return binary(f, g, op), nil
+}
+
+// binary applies op to every pair of outputs of f and g.
+func binary(f, g filter, op func(a, b kit.Value) (kit.Value, error)) filter {
+	return func(in kit.Value) ([]kit.Value, error) {
+		xs, err := f(in)
+		if err != nil {
+			return nil, err
+		}
+		ys, err := g(in)
+		if err != nil {
+			return nil, err
+		}
+		var out []kit.Value
+		for _, x := range xs {
+			for _, y := range ys {
+				z, err := op(x, y)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, z)
+			}
+		}
+		return out, nil
+	}
+}
+
+func truthy(v kit.Value) bool {
+	switch v.K {
+	case kit.Nil, kit.Invalid:
+		return false
+	case kit.Bool:
+		return v.Truthy()
+	}
+	return true
+}
+
+func (p *parser) postfix() (filter, error) {
+	f, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch t := p.peek(); {
+		case t.kind == tokPunct && t.text == "." && p.toks[p.pos+1].glued:
+			p.pos++
+			k := p.next()
+			if k.kind != tokIdent && k.kind != tokString {
+				return nil, fmt.Errorf("expected field name at offset %d", k.pos)
+			}
+			f = pipeOf(f, field(k.text))
+		case t.kind == tokPunct && t.text == "[" && t.glued:
+			p.pos++
+			g, err := p.subscript()
+			if err != nil {
+				return nil, err
+			}
+			f = pipeOf(f, g)
+		default:
+			return f, nil
+		}
+	}
+}
+
+// subscript parses what follows "[" in .[], .[n] and .["key"].
+func (p *parser) subscript() (filter, error) {
+	if p.accept("]") {
+		return iterate, nil
+	}
+	idx, err := p.pipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	return func(in kit.Value) ([]kit.Value, error) {
+		keys, err := idx(in)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]kit.Value, 0, len(keys))
+		for _, k := range keys {
+			var v kit.Value
+			switch {
+			case k.K == kit.String:
+				var vs []kit.Value
+				if vs, err = field(k.Text())(in); err == nil {
+					v = vs[0]
+				}
+			case k.K == kit.Number && (in.K == kit.Array || in.K == kit.Nil):
+				i := int(k.Int())
+				if i < 0 {
+					i += in.Len()
+				}
+				v = orNull(in.Index(i))
+			default:
+				err = fmt.Errorf("cannot index %s with %s", typeName(in), typeName(k))
+			}
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}, nil
+}
+
+func (p *parser) primary() (filter, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", t.text, t.pos)
+		}
+		return constant(kit.New(n)), nil
+	case tokString:
+		return constant(kit.New(t.text)), nil
+	case tokIdent:
+		return p.call(t)
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of filter")
+	}
+
+	switch t.text {
+	case ".":
+		if n := p.peek(); n.glued && (n.kind == tokIdent || n.kind == tokString) {
+			p.pos++
+			return field(n.text), nil
+		}
+		if n := p.peek(); n.glued && n.kind == tokPunct && n.text == "[" {
+			p.pos++
+			return p.subscript()
+		}
+		return identity, nil
+	case "(":
+		f, err := p.pipe()
+		if err == nil {
+			err = p.expect(")")
+		}
+		return f, err
+	case "[":
+		if p.accept("]") {
+			return constant(kit.New([]any{})), nil
+		}
+		f, err := p.pipe()
+		if err == nil {
+			err = p.expect("]")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return func(in kit.Value) ([]kit.Value, error) {
+			xs, err := f(in)
+			if err != nil {
+				return nil, err
+			}
+			return one(kit.New(append([]kit.Value{}, xs...))), nil
+		}, nil
+	case "-":
+		f, err := p.postfix()
+		if err != nil {
+			return nil, err
+		}
+		return pipeOf(f, func(in kit.Value) ([]kit.Value, error) {
+			if in.K != kit.Number {
+				return nil, fmt.Errorf("cannot negate %s", typeName(in))
+			}
+			return one(kit.New(-in.Float())), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("unexpected %q at offset %d", t.text, t.pos)
+}
+
+// call parses a literal keyword or a builtin, with its arguments.
+func (p *parser) call(t token) (filter, error) {
+	switch t.text {
+	case "true", "false":
+		return constant(kit.New(t.text == "true")), nil
+	case "null":
+		return constant(kit.Value{K: kit.Nil}), nil
+	case "not":
+		return func(in kit.Value) ([]kit.Value, error) { return one(kit.New(!truthy(in))), nil }, nil
+	}
+
+	if fn, ok := builtins[t.text]; ok {
+		return func(in kit.Value) ([]kit.Value, error) {
+			v, err := fn(in)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", t.text, err)
+			}
+			return one(v), nil
+		}, nil
+	}
+	if t.text == "empty" {
+		return func(kit.Value) ([]kit.Value, error) { return nil, nil }, nil
+	}
+
+	mk, ok := higherOrder[t.text]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %q at offset %d", t.text, t.pos)
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	arg, err := p.pipe()
+	if err == nil {
+		err = p.expect(")")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mk(arg), nil
+}
+
+func identity(in kit.Value) ([]kit.Value, error) { return one(in), nil }
+
+func one(v kit.Value) []kit.Value { return []kit.Value{v} }
+
+func constant(v kit.Value) filter {
+	return func(kit.Value) ([]kit.Value, error) { return one(v), nil }
+}
+
+func orNull(v kit.Value) kit.Value {
+	if v.K == kit.Invalid {
+		return kit.Value{K: kit.Nil}
+	}
+	return v
+}
+
+func field(name string) filter {
+	return func(in kit.Value) ([]kit.Value, error) {
+		switch in.K {
+		case kit.Nil:
+			return one(in), nil
+		case kit.Map, kit.Struct:
+			if !in.Has(name) {
+				return one(kit.Value{K: kit.Nil}), nil
+			}
+			return one(orNull(in.Get(name))), nil
+		}
+		return nil, fmt.Errorf("cannot index %s with %q", typeName(in), name)
+	}
+}
+
+func iterate(in kit.Value) ([]kit.Value, error) {
+	switch in.K {
+	case kit.Array:
+		return in.MustArray(), nil
+	case kit.Map:
+		return in.SortedValues().MustArray(), nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeName(in))
+}
+
+// typeName names kinds the way jq does.
+func typeName(v kit.Value) string {
+	switch v.K {
+	case kit.Nil, kit.Invalid:
+		return "null"
+	case kit.Bool:
+		return "boolean"
+	case kit.Number:
+		return "number"
+	case kit.Array:
+		return "array"
+	case kit.Map, kit.Struct:
+		return "object"
+	}
+	return "string"
+}
+
+// rng backs shuffle and pick; main seeds it from -seed.
+var rng = kit.NewRand(1)
+
+var builtins = map[string]func(kit.Value) (kit.Value, error){
+	"length": func(v kit.Value) (kit.Value, error) {
+		switch v.K {
+		case kit.Nil:
+			return kit.New(0), nil
+		case kit.Number:
+			return kit.New(max(v.Float(), -v.Float())), nil
+		case kit.String:
+			return kit.New(v.RuneLen()), nil
+		}
+		return kit.New(v.Len()), nil
+	},
+	"keys": func(v kit.Value) (kit.Value, error) {
+		switch v.K {
+		case kit.Map:
+			return v.SortedKeys(), nil
+		case kit.Array:
+			idx := make([]int, v.Len())
+			for i := range idx {
+				idx[i] = i
+			}
+			return kit.New(idx), nil
+		}
+		return kit.Value{}, fmt.Errorf("%s has no keys", typeName(v))
+	},
+	"values": func(v kit.Value) (kit.Value, error) {
+		elems, err := iterate(v)
+		return kit.New(elems), err
+	},
+	"sort":    array(sortValues),
+	"unique":  array(func(v kit.Value) kit.Value { return sortValues(v.Unique()) }),
+	"reverse": array(func(v kit.Value) kit.Value { return v.Reverse() }),
+	"first":   array(func(v kit.Value) kit.Value { return orNull(v.Index(0)) }),
+	"last":    array(func(v kit.Value) kit.Value { return orNull(v.Index(v.Len() - 1)) }),
+	"sum":     array(func(v kit.Value) kit.Value { return v.Sum() }),
+	"min":     array(func(v kit.Value) kit.Value { return orNull(v.Min()) }),
+	"max":     array(func(v kit.Value) kit.Value { return orNull(v.Max()) }),
+	"type":    func(v kit.Value) (kit.Value, error) { return kit.New(typeName(v)), nil },
+	"tostring": func(v kit.Value) (kit.Value, error) {
+		if v.K == kit.String {
+			return v, nil
+		}
+		b, err := v.MarshalJSON()
+		return kit.New(string(b)), err
+	},
+	"floor":   number(kit.Value.Floor),
+	"ceil":    number(kit.Value.Ceil),
+	"round":   number(func(v kit.Value) kit.Value { return v.Round(0, kit.RoundHalfUp) }),
+	"sqrt":    number(kit.Value.Sqrt),
+	"fabs":    number(kit.Value.Abs),
+	"shuffle": array(func(v kit.Value) kit.Value { return rng.Shuffle(v) }),
+	"pick":    array(func(v kit.Value) kit.Value { return rng.Pick(v) }),
+	"tonumber": func(v kit.Value) (kit.Value, error) {
+		if v.K == kit.Number {
+			return v, nil
+		}
+		if n := kit.FromText(v.Text()); v.K == kit.String && n.K == kit.Number {
+			return n, nil
+		}
+		return kit.Value{}, fmt.Errorf("cannot parse %s as a number", typeName(v))
+	},
+}
+
+func sortValues(v kit.Value) kit.Value {
+	return v.Sort(func(a, b kit.Value) bool { return a.Compare(b) < 0 })
+}
+
+// array restricts fn to Array inputs.
+func array(fn func(kit.Value) kit.Value) func(kit.Value) (kit.Value, error) {
+	return func(v kit.Value) (kit.Value, error) {
+		if v.K != kit.Array {
+			return kit.Value{}, fmt.Errorf("%s is not an array", typeName(v))
+		}
+		return fn(v), nil
+	}
+}
+
+// number restricts fn to Number inputs.
+func number(fn func(kit.Value) kit.Value) func(kit.Value) (kit.Value, error) {
+	return func(v kit.Value) (kit.Value, error) {
+		if v.K != kit.Number {
+			return kit.Value{}, fmt.Errorf("%s is not a number", typeName(v))
+		}
+		return fn(v), nil
+	}
+}
+
+var higherOrder = map[string]func(f filter) filter{
+	"select": func(f filter) filter {
+		return func(in kit.Value) ([]kit.Value, error) {
+			xs, err := f(in)
+			if err != nil {
+				return nil, err
+			}
+			for _, x := range xs {
+				if truthy(x) {
+					return one(in), nil
+				}
+			}
+			return nil, nil
+		}
+	},
+	"map": func(f filter) filter {
+		return func(in kit.Value) ([]kit.Value, error) {
+			elems, err := iterate(in)
+			if err != nil {
+				return nil, err
+			}
+			out := []kit.Value{}
+			for _, e := range elems {
+				xs, err := f(e)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, xs...)
+			}
+			return one(kit.New(out)), nil
+		}
+	},
+	"sort_by": func(f filter) filter {
+		return func(in kit.Value) ([]kit.Value, error) {
+			if in.K != kit.Array {
+				return nil, fmt.Errorf("sort_by: %s is not an array", typeName(in))
+			}
+			elems := append([]kit.Value(nil), in.MustArray()...)
+			keys := make([]kit.Value, len(elems))
+			for i, e := range elems {
+				xs, err := f(e)
+				if err != nil {
+					return nil, err
+				}
+				keys[i] = kit.New(xs)
+			}
+			idx := make([]int, len(elems))
+			for i := range idx {
+				idx[i] = i
+			}
+			sort.SliceStable(idx, func(a, b int) bool { return keys[idx[a]].Compare(keys[idx[b]]) < 0 })
+			out := make([]kit.Value, len(elems))
+			for i, j := range idx {
+				out[i] = elems[j]
+			}
+			return one(kit.New(out)), nil
+		}
+	},
+}
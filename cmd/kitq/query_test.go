@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kitwork/kit"
+)
+
+const orders = `{
+  "items": [
+    {"id": 1, "ok": true, "price": 9.5, "tags": ["a"]},
+    {"id": 2, "ok": false, "price": 3},
+    {"id": 3, "ok": true, "price": 1, "meta": {"x y": "z"}}
+  ],
+  "name": "orders"
+}`
+
+func TestQuery(t *testing.T) {
+	in, err := kit.FromJSON([]byte(orders))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct{ filter, want string }{
+		{".", ""},
+		{".name", `"orders"`},
+		{".items[] | select(.ok) | .id", "1 3"},
+		{".items[0].tags[0]", `"a"`},
+		{".items[-1].meta.\"x y\"", `"z"`},
+		{`.items[2]["meta"]["x y"]`, `"z"`},
+		{".missing.deep", "null"},
+		{".items | length", "3"},
+		{"[.items[] | .price] | sum", "13.5"},
+		{".items | sort_by(.price) | map(.id)", "[3,2,1]"},
+		{".items | map(select(.price > 2 and .ok)) | map(.id)", "[1]"},
+		{".items[] | select(.ok == false or .id == 3) | .id", "2 3"},
+		{".items[1].ok | not", "true"},
+		{"keys", `["items","name"]`},
+		{".name, .items[0].id", `"orders" 1`},
+		{"[.items[].id] | reverse | first", "3"},
+		{".items[0] | type", `"object"`},
+		{"[1, -2, 1] | unique", "[-2,1]"},
+		{`"7" | tonumber`, "7"},
+		{".items[] | .price | floor", "9 3 1"},
+		{"(.items[0].price | round), (.items[1].id | sqrt | ceil)", "10 2"},
+		{"[.items[].id] | shuffle | sort", "[1,2,3]"},
+		{"[7] | pick", "7"},
+		{".items[] | select(.price < 0)", ""},
+	}
+	for _, c := range cases {
+		f, err := compile(c.filter)
+		if err != nil {
+			t.Errorf("compile(%q): %v", c.filter, err)
+			continue
+		}
+		out, err := f(in)
+		if err != nil {
+			t.Errorf("%q: %v", c.filter, err)
+			continue
+		}
+		var got []string
+		for _, v := range out {
+			b, _ := v.MarshalJSON()
+			got = append(got, string(b))
+		}
+		want := c.want
+		if c.filter == "." {
+			b, _ := in.MarshalJSON()
+			want = string(b)
+		}
+		if s := strings.Join(got, " "); s != want {
+			t.Errorf("%q = %s, want %s", c.filter, s, want)
+		}
+	}
+}
+
+func TestQuery_Errors(t *testing.T) {
+	in := kit.New(map[string]any{"n": 1})
+	for _, src := range []string{".[", "select(", "nope", ".a |", `"open`, ".n ]"} {
+		if _, err := compile(src); err == nil {
+			t.Errorf("compile(%q) succeeded", src)
+		}
+	}
+	for _, src := range []string{".n.x", ".n[]", ".n | sort", ".n | keys"} {
+		f, err := compile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f(in); err == nil {
+			t.Errorf("%q succeeded", src)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	var out bytes.Buffer
+	err := run([]string{".[] | select(.age >= 18) | .name"}, strings.NewReader("name,age\nann,30\nbob,12\n"),
+		&out, options{in: "csv", out: "json", raw: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "ann\n" {
+		t.Errorf("csv output = %q", out.String())
+	}
+
+	out.Reset()
+	err = run([]string{".db"}, strings.NewReader("db:\n  port: 5432\n"), &out, options{in: "auto", out: "json", compact: true})
+	if err != nil || out.String() != "{\"port\":5432}\n" {
+		t.Errorf("yaml output = %q, %v", out.String(), err)
+	}
+}
@@ -0,0 +1,147 @@
+package kit
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   COLLATION
+   Locale-aware string order for user-visible lists, a compact take on the
+   three levels of UCA: letters first, ignoring accents and case, then
+   accents, then case. Letters a Locale lists in its Collation sort as
+   letters of their own, so Vietnamese "ơ" follows every "ô" and Turkish
+   "ı" precedes "i".
+   ============================================================================= */
+
+// Collator compares Strings in the alphabetical order of a Locale. It is
+// immutable and safe for concurrent use.
+type Collator struct {
+	letters map[string]int32 // tailored letter (base rune plus at most one mark, NFD) to primary weight
+	marks   map[rune]int32
+	lower   unicode.SpecialCase
+}
+
+// NewCollator returns the Collator for a language tag, resolved as
+// FormatLocale does. Locales without a Collation order letters as English.
+func NewCollator(tag string) *Collator {
+	loc := lookupLocale(tag)
+	c := &Collator{letters: map[string]int32{}, marks: map[rune]int32{}, lower: loc.Case}
+	for _, group := range loc.Collation {
+		letters := strings.Fields(group)
+		anchor := 0
+		for i, l := range letters {
+			if len(l) == 1 {
+				anchor = i
+			}
+		}
+		base, _ := utf8.DecodeRuneInString(letters[anchor])
+		for i, l := range letters {
+			c.letters[NFD(l)] = primaryWeight(base) + int32(i-anchor)
+		}
+	}
+	for i, m := range []rune(loc.Marks) {
+		c.marks[m] = int32(i + 1)
+	}
+	return c
+}
+
+// primaryWeight spaces code points apart so tailored letters fit between.
+func primaryWeight(r rune) int32 { return r<<8 | 0x80 }
+
+// collationKey holds the weights of the three levels.
+type collationKey struct {
+	primary, secondary []int32
+	tertiary           []bool // upper case per letter
+}
+
+func (c *Collator) key(s string) collationKey {
+	var k collationKey
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			k.tertiary = append(k.tertiary, unicode.IsUpper(r) || unicode.IsTitle(r))
+		}
+	}
+	rs := decompose(strings.Map(c.lower.ToLower, s))
+	for i := 0; i < len(rs); {
+		j := i + 1
+		for j < len(rs) && combiningClass[rs[j]] != 0 {
+			j++
+		}
+		base, marks := rs[i], rs[i+1:j]
+		w, ok := c.letters[string(base)]
+		if !ok {
+			w = primaryWeight(base)
+		}
+		// Combining marks are reordered by class, so a letter's own mark
+		// need not follow its base directly: "ậ" is a, dot below, circumflex.
+		for n, m := range marks {
+			if lw, ok := c.letters[string([]rune{base, m})]; ok {
+				w, marks = lw, slices.Delete(slices.Clone(marks), n, n+1)
+				break
+			}
+		}
+		k.primary = append(k.primary, w)
+		k.secondary = append(k.secondary, 0)
+		for _, m := range marks {
+			mw, ok := c.marks[m]
+			if !ok {
+				mw = 1<<16 + m
+			}
+			k.secondary = append(k.secondary, mw)
+		}
+		i = j
+	}
+	return k
+}
+
+// CompareStrings returns -1, 0 or +1 as a sorts before, equal to or after b.
+// Strings that differ only in normalization compare as 0; any other
+// difference left after the three levels falls back to byte order.
+func (c *Collator) CompareStrings(a, b string) int {
+	if a == b {
+		return 0
+	}
+	x, y := c.key(a), c.key(b)
+	if r := slices.Compare(x.primary, y.primary); r != 0 {
+		return r
+	}
+	if r := slices.Compare(x.secondary, y.secondary); r != 0 {
+		return r
+	}
+	if r := slices.CompareFunc(x.tertiary, y.tertiary, func(p, q bool) int {
+		return cmp.Compare(b2i(p), b2i(q))
+	}); r != 0 {
+		return r
+	}
+	if NFC(a) == NFC(b) {
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Compare orders two Values as Value.Compare does, except that a pair of
+// Strings compares with CompareStrings. Strings nested in containers keep
+// byte order.
+func (c *Collator) Compare(a, b Value) int {
+	a, b = a.Force(), b.Force()
+	if a.K == String && b.K == String {
+		return c.CompareStrings(a.String(), b.String())
+	}
+	return a.Compare(b)
+}
+
+// Less reports whether a sorts before b, for use with Value.Sort:
+//
+//	names.Sort(kit.NewCollator("vi").Less)
+func (c *Collator) Less(a, b Value) bool { return c.Compare(a, b) < 0 }
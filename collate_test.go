@@ -0,0 +1,37 @@
+package kit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCollator(t *testing.T) {
+	sorted := func(tag string, words ...string) string {
+		return New(words).Sort(NewCollator(tag).Less).Join(" ").String()
+	}
+	cases := []struct{ tag, in, want string }{
+		{"en", "banana Apple apple cherry", "apple Apple banana cherry"},
+		{"en", "résumé resume Resume rose", "resume Resume résumé rose"},
+		{"vi", "đá dao ơn ông ăn an bàn ba", "an ăn ba bàn dao đá ông ơn"},
+		{"vi", "á ạ à ả ã a", "a à ả ã á ạ"},
+		{"vi", "ậu ăm ấm", "ăm ấm ậu"},
+		{"vi-VN", "Ư u ư U", "u U ư Ư"},
+		{"tr", "ılık iğne Işık İstanbul ınce", "ılık ınce Işık iğne İstanbul"},
+		{"tr", "şeker sabah çay cam", "cam çay sabah şeker"},
+		{"es", "ñame nube oso", "nube ñame oso"},
+		{"de", "Äpfel Affe Zebra", "Affe Äpfel Zebra"},
+	}
+	for _, c := range cases {
+		if got := sorted(c.tag, strings.Fields(c.in)...); got != c.want {
+			t.Errorf("%s: sort(%s) = %s, want %s", c.tag, c.in, got, c.want)
+		}
+	}
+
+	vi := NewCollator("vi")
+	if vi.CompareStrings("Vi\u1ec7t", "Vie\u0323\u0302t") != 0 {
+		t.Error("NFC and NFD forms compare unequal")
+	}
+	if vi.Compare(New(2), New("a")) >= 0 || vi.Compare(New(1), New(2)) >= 0 {
+		t.Error("non-Strings do not compare as Value.Compare")
+	}
+}
@@ -0,0 +1,487 @@
+package kit
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+/* =============================================================================
+   COLLECTIONS
+   Callbacks are Go functions or Func Values. They receive the element first,
+   then its index (Arrays) or key (Maps); see Call for how surplus arguments
+   are handled. Results are new Values; receivers are never modified.
+   ============================================================================= */
+
+// elements returns the Array items of v, or nil with false for other kinds.
+func (v Value) elements() ([]Value, bool) {
+	v = v.Force()
+	if v.K != Array {
+		return nil, false
+	}
+	a, _ := v.V.([]Value)
+	return a, true
+}
+
+// MapEach returns a collection of the same shape with every element (or Map
+// value) replaced by fn(elem, index|key).
+func (v Value) MapEach(fn any) Value {
+	call := callable(fn)
+	v = v.Force()
+	switch v.K {
+	case Array:
+		a := v.V.([]Value)
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = call(e, Value{K: Number, N: float64(i)})
+		}
+		return Value{K: Array, V: out}
+	case Map:
+		m := v.mapping()
+		out := make(map[string]Value, len(m))
+		for k, e := range m {
+			out[k] = call(e, Value{K: String, V: k})
+		}
+		return Value{K: Map, V: out}
+	}
+	return Value{K: Invalid}
+}
+
+// Filter keeps the elements (or Map entries) for which fn returns a truthy Value.
+func (v Value) Filter(fn any) Value {
+	call := callable(fn)
+	v = v.Force()
+	switch v.K {
+	case Array:
+		var out []Value
+		for i, e := range v.V.([]Value) {
+			if call(e, Value{K: Number, N: float64(i)}).Truthy() {
+				out = append(out, e)
+			}
+		}
+		if out == nil {
+			out = []Value{}
+		}
+		return Value{K: Array, V: out}
+	case Map:
+		out := make(map[string]Value)
+		for k, e := range v.mapping() {
+			if call(e, Value{K: String, V: k}).Truthy() {
+				out[k] = e
+			}
+		}
+		return Value{K: Map, V: out}
+	}
+	return Value{K: Invalid}
+}
+
+// Partition splits an Array in one pass into the elements for which pred
+// returns a truthy Value and the rest, both in original order.
+func (v Value) Partition(pred any) (matched, rest Value) {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}, Value{K: Invalid}
+	}
+	call := callable(pred)
+	yes, no := []Value{}, []Value{}
+	for i, e := range a {
+		if call(e, Value{K: Number, N: float64(i)}).Truthy() {
+			yes = append(yes, e)
+		} else {
+			no = append(no, e)
+		}
+	}
+	return Value{K: Array, V: yes}, Value{K: Array, V: no}
+}
+
+// FlatMap maps every element with fn(elem, index) and splices Array results
+// into the output; other results are appended as single elements.
+func (v Value) FlatMap(fn any) Value {
+	return v.MapEach(fn).Flatten(1)
+}
+
+// Flatten splices nested Arrays into their parent up to depth levels;
+// a negative depth flattens completely.
+func (v Value) Flatten(depth int) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	return Value{K: Array, V: flattenInto(make([]Value, 0, len(a)), a, depth)}
+}
+
+func flattenInto(out, a []Value, depth int) []Value {
+	for _, e := range a {
+		if inner, ok := e.elements(); ok && depth != 0 {
+			out = flattenInto(out, inner, depth-1)
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// Every reports whether pred returns a truthy Value for all elements of an
+// Array or values of a Map, stopping at the first failure. It is true for
+// empty collections and false for other kinds.
+func (v Value) Every(pred any) bool {
+	v = v.Force()
+	if v.K != Array && v.K != Map {
+		return false
+	}
+	return !v.any(pred, false)
+}
+
+// Some reports whether pred returns a truthy Value for at least one element
+// of an Array or value of a Map, stopping at the first match.
+func (v Value) Some(pred any) bool {
+	return v.any(pred, true)
+}
+
+// any reports whether some element's predicate result has truthiness want.
+func (v Value) any(pred any, want bool) bool {
+	call := callable(pred)
+	v = v.Force()
+	switch v.K {
+	case Array:
+		for i, e := range v.V.([]Value) {
+			if call(e, Value{K: Number, N: float64(i)}).Truthy() == want {
+				return true
+			}
+		}
+	case Map:
+		for k, e := range v.mapping() {
+			if call(e, Value{K: String, V: k}).Truthy() == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Reduce folds an Array from the left: acc = fn(acc, elem, index), starting
+// from init.
+func (v Value) Reduce(fn any, init Value) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	call := callable(fn)
+	acc := init
+	for i, e := range a {
+		acc = call(acc, e, Value{K: Number, N: float64(i)})
+	}
+	return acc
+}
+
+// Sort returns a stably sorted copy of an Array ordered by less.
+func (v Value) Sort(less func(a, b Value) bool) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	out := make([]Value, len(a))
+	copy(out, a)
+	sort.SliceStable(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return Value{K: Array, V: out}
+}
+
+// SortBy stably sorts an Array by the Value at a dot-separated path of each
+// element ("" sorts by the elements themselves), ascending unless desc, in
+// the order of Compare. Elements where the path is missing or Nil always
+// sort last.
+func (v Value) SortBy(path string, desc bool) Value {
+	return v.Sort(func(a, b Value) bool {
+		x, y := a.Path(path), b.Path(path)
+		if x.IsBlank() || y.IsBlank() {
+			return !x.IsBlank()
+		}
+		if desc {
+			return x.Compare(y) > 0
+		}
+		return x.Compare(y) < 0
+	})
+}
+
+// GroupBy groups Array elements by the Text form of the Value at path,
+// returning a Map of Arrays in original order. Missing paths group under "null".
+func (v Value) GroupBy(path string) Value {
+	return v.GroupByFn(func(e Value) Value { return e.Path(path) })
+}
+
+// GroupByFn groups Array elements by the Text form of fn(elem, index).
+func (v Value) GroupByFn(fn any) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	call := callable(fn)
+	groups := make(map[string][]Value)
+	for i, e := range a {
+		k := call(e, Value{K: Number, N: float64(i)}).Text()
+		groups[k] = append(groups[k], e)
+	}
+	out := make(map[string]Value, len(groups))
+	for k, g := range groups {
+		out[k] = Value{K: Array, V: g}
+	}
+	return Value{K: Map, V: out}
+}
+
+// CountBy counts Array elements by the Text form of the Value at path,
+// returning a Map of Numbers. Missing paths count under "null".
+func (v Value) CountBy(path string) Value {
+	return v.CountByFn(func(e Value) Value { return e.Path(path) })
+}
+
+// CountByFn counts Array elements by the Text form of fn(elem, index).
+func (v Value) CountByFn(fn any) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	call := callable(fn)
+	out := make(map[string]Value)
+	for i, e := range a {
+		k := call(e, Value{K: Number, N: float64(i)}).Text()
+		c := out[k]
+		out[k] = Value{K: Number, N: c.N + 1}
+	}
+	return Value{K: Map, V: out}
+}
+
+// Unique removes deeply Equal duplicates from an Array, keeping the first
+// occurrence of each element.
+func (v Value) Unique() Value {
+	return v.UniqueBy("")
+}
+
+// UniqueBy keeps the first element for each distinct Value at path.
+func (v Value) UniqueBy(path string) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	seen := make(valueSet)
+	out := make([]Value, 0, len(a))
+	for _, e := range a {
+		if seen.add(e.Path(path)) {
+			out = append(out, e)
+		}
+	}
+	return Value{K: Array, V: out}
+}
+
+// Union returns the distinct elements of v followed by those of other that
+// v lacks, compared with Equal.
+func (v Value) Union(other Value) Value { return v.UnionBy(other, "") }
+
+// Intersect returns the distinct elements of v that also occur in other.
+func (v Value) Intersect(other Value) Value { return v.IntersectBy(other, "") }
+
+// Difference returns the distinct elements of v that do not occur in other.
+func (v Value) Difference(other Value) Value { return v.DifferenceBy(other, "") }
+
+// UnionBy is Union comparing elements by the Value at path.
+func (v Value) UnionBy(other Value, path string) Value {
+	a, ok1 := v.elements()
+	b, ok2 := other.elements()
+	if !ok1 || !ok2 {
+		return Value{K: Invalid}
+	}
+	seen := make(valueSet)
+	out := make([]Value, 0, len(a)+len(b))
+	for _, list := range [2][]Value{a, b} {
+		for _, e := range list {
+			if seen.add(e.Path(path)) {
+				out = append(out, e)
+			}
+		}
+	}
+	return Value{K: Array, V: out}
+}
+
+// IntersectBy is Intersect comparing elements by the Value at path.
+func (v Value) IntersectBy(other Value, path string) Value {
+	return v.filterMembers(other, path, true)
+}
+
+// DifferenceBy is Difference comparing elements by the Value at path.
+func (v Value) DifferenceBy(other Value, path string) Value {
+	return v.filterMembers(other, path, false)
+}
+
+func (v Value) filterMembers(other Value, path string, keep bool) Value {
+	a, ok1 := v.elements()
+	b, ok2 := other.elements()
+	if !ok1 || !ok2 {
+		return Value{K: Invalid}
+	}
+	in := make(valueSet, len(b))
+	for _, e := range b {
+		in.add(e.Path(path))
+	}
+	seen := make(valueSet)
+	out := make([]Value, 0, len(a))
+	for _, e := range a {
+		k := e.Path(path)
+		if in.has(k) == keep && seen.add(k) {
+			out = append(out, e)
+		}
+	}
+	return Value{K: Array, V: out}
+}
+
+// Chunk splits an Array into consecutive sub-Arrays of n elements; the last
+// one holds the remainder. Chunks share storage with v.
+func (v Value) Chunk(n int) Value {
+	a, ok := v.elements()
+	if !ok || n <= 0 {
+		return Value{K: Invalid}
+	}
+	out := make([]Value, 0, (len(a)+n-1)/n)
+	for i := 0; i < len(a); i += n {
+		end := min(i+n, len(a))
+		out = append(out, Value{K: Array, V: a[i:end:end]})
+	}
+	return Value{K: Array, V: out}
+}
+
+// Batch calls fn with each chunk of at most n elements in order, stopping at
+// the first error.
+func (v Value) Batch(n int, fn func(chunk Value) error) error {
+	chunks := v.Chunk(n)
+	if chunks.K != Array {
+		return fmt.Errorf("kit: cannot batch %s by %d", v.Force().K, n)
+	}
+	for _, c := range chunks.V.([]Value) {
+		if err := fn(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reverse returns Arrays reversed by element, Strings by rune and Bytes by
+// byte. Other kinds are Invalid.
+func (v Value) Reverse() Value {
+	v = v.Force()
+	switch v.K {
+	case Array:
+		a := v.V.([]Value)
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[len(a)-1-i] = e
+		}
+		return Value{K: Array, V: out}
+	case String:
+		r := []rune(v.String())
+		slices.Reverse(r)
+		return Value{K: String, V: string(r)}
+	case Bytes:
+		b := slices.Clone(v.Bytes())
+		slices.Reverse(b)
+		return Value{K: Bytes, V: b}
+	}
+	return Value{K: Invalid}
+}
+
+// Contains reports whether x is an element of an Array (deep Equal), a
+// substring of a String, a contiguous sub-slice of Bytes, or a key of a Map.
+func (v Value) Contains(x Value) bool {
+	v, x = v.Force(), x.Force()
+	if v.K == Map {
+		if x.K != String {
+			return false
+		}
+		if s, ok := v.V.(*sharded); ok {
+			_, ok = s.get(x.String())
+			return ok
+		}
+		_, ok := v.V.(map[string]Value)[x.String()]
+		return ok
+	}
+	return v.IndexOf(x) >= 0
+}
+
+// IndexOf returns the position of the first element of an Array deeply Equal
+// to x, or the byte offset of x within a String or Bytes; -1 if absent.
+func (v Value) IndexOf(x Value) int {
+	v, x = v.Force(), x.Force()
+	switch v.K {
+	case Array:
+		for i, e := range v.V.([]Value) {
+			if e.Equal(x) {
+				return i
+			}
+		}
+	case String:
+		if x.K == String {
+			return strings.Index(v.String(), x.String())
+		}
+	case Bytes:
+		if x.K == Bytes || x.K == String {
+			return bytes.Index(v.Bytes(), x.ByteSlice())
+		}
+	}
+	return -1
+}
+
+// Keys returns the keys of a Map as an Array of Strings in unspecified order.
+func (v Value) Keys() Value {
+	v = v.Force()
+	if v.K != Map {
+		return Value{K: Invalid}
+	}
+	m := v.mapping()
+	out := make([]Value, 0, len(m))
+	for k := range m {
+		out = append(out, Value{K: String, V: k})
+	}
+	return Value{K: Array, V: out}
+}
+
+// Values returns the values of a Map as an Array in unspecified order.
+func (v Value) Values() Value {
+	v = v.Force()
+	if v.K != Map {
+		return Value{K: Invalid}
+	}
+	m := v.mapping()
+	out := make([]Value, 0, len(m))
+	for _, e := range m {
+		out = append(out, e)
+	}
+	return Value{K: Array, V: out}
+}
+
+// SortedKeys returns the keys of a Map in ascending byte order.
+func (v Value) SortedKeys() Value {
+	v = v.Force()
+	if v.K != Map {
+		return Value{K: Invalid}
+	}
+	keys := sortedKeys(v.mapping())
+	out := make([]Value, len(keys))
+	for i, k := range keys {
+		out[i] = Value{K: String, V: k}
+	}
+	return Value{K: Array, V: out}
+}
+
+// SortedValues returns the values of a Map ordered by their keys.
+func (v Value) SortedValues() Value {
+	v = v.Force()
+	if v.K != Map {
+		return Value{K: Invalid}
+	}
+	m := v.mapping()
+	keys := sortedKeys(m)
+	out := make([]Value, len(keys))
+	for i, k := range keys {
+		out[i] = m[k]
+	}
+	return Value{K: Array, V: out}
+}
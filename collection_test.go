@@ -0,0 +1,422 @@
+package kit
+
+import (
+	"cmp"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValue_MapFilterReduce(t *testing.T) {
+	nums := New([]int{1, 2, 3, 4})
+
+	doubled := nums.MapEach(func(x Value) Value { return x.Mul(New(2)) })
+	if !doubled.Equal(New([]int{2, 4, 6, 8})) {
+		t.Errorf("MapEach = %v", doubled)
+	}
+
+	even := nums.Filter(func(x Value) bool { return x.Int()%2 == 0 })
+	if !even.Equal(New([]int{2, 4})) {
+		t.Errorf("Filter = %v", even)
+	}
+
+	sum := nums.Reduce(func(acc, x Value) Value { return acc.Add(x) }, New(0))
+	if sum.Int() != 10 {
+		t.Errorf("Reduce = %v", sum)
+	}
+
+	// Func Values and reflected Go functions work the same way.
+	idx := nums.MapEach(New(func(x float64, i int) string { return "#" + New(i).Text() }))
+	if idx.Index(2).String() != "#2" {
+		t.Errorf("MapEach with reflected func = %v", idx)
+	}
+
+	m := New(map[string]any{"a": 1, "b": 2})
+	if got := m.Filter(func(x Value) bool { return x.Int() > 1 }); got.Len() != 1 || !got.Get("b").IsValid() {
+		t.Errorf("Filter on Map = %v", got)
+	}
+	if !New("x").MapEach(func(x Value) Value { return x }).IsInvalid() {
+		t.Error("MapEach on String should be Invalid")
+	}
+}
+
+func TestValue_Call(t *testing.T) {
+	add := New(func(a, b int) (int, error) { return a + b, nil })
+	if add.K != Func || add.Call(New(2), New(3)).Int() != 5 {
+		t.Errorf("Call = %v", add.Call(New(2), New(3)))
+	}
+	if !add.Call(New(1)).IsInvalid() {
+		t.Error("missing arguments should be Invalid")
+	}
+	if !New(3).Call().IsInvalid() {
+		t.Error("calling a Number should be Invalid")
+	}
+}
+
+type cart struct{ Items []float64 }
+
+func (c cart) Total() float64           { return c.Items[0] + c.Items[1] }
+func (c cart) Scaled(f float64) float64 { return c.Total() * f }
+func (c *cart) Add(xs ...float64)       { c.Items = append(c.Items, xs...) }
+func (c cart) hidden()                  {}
+
+func TestValue_Methods(t *testing.T) {
+	c := &cart{Items: []float64{1, 2}}
+	ms := New(c).Methods()
+	if got := ms.SortedKeys(); !got.Equal(New([]any{"Add", "Scaled", "Total"})) {
+		t.Fatalf("Methods = %s", got)
+	}
+	if got := ms.Get("Scaled").Call(New(10)); got.Float() != 30 {
+		t.Errorf("Scaled(10) = %s", got)
+	}
+	ms.Get("Add").Call(New(3), New(4))
+	if len(c.Items) != 4 {
+		t.Errorf("Add did not reach the bound pointer: %v", c.Items)
+	}
+
+	for name, want := range map[string][2]any{"Total": {0, false}, "Scaled": {1, false}, "Add": {0, true}} {
+		if n, variadic := ms.Get(name).Arity(); n != want[0] || variadic != want[1] {
+			t.Errorf("%s.Arity() = %d, %v, want %v", name, n, variadic, want)
+		}
+	}
+	if n, variadic := New(func(...Value) Value { return Value{} }).Arity(); n != 0 || !variadic {
+		t.Errorf("Arity(func(...Value)) = %d, %v", n, variadic)
+	}
+	if n, _ := New(3).Arity(); n != 0 {
+		t.Errorf("Arity(Number) = %d", n)
+	}
+
+	if got := New(*c).Methods(); got.Len() != 2 {
+		t.Errorf("value Methods = %s, want the value-receiver ones", got.SortedKeys())
+	}
+	if got := New(map[string]any{}).Methods(); got.K != Invalid {
+		t.Errorf("Map Methods = %s, want Invalid", got)
+	}
+}
+
+func TestValue_SortBy(t *testing.T) {
+	users := New([]map[string]any{
+		{"name": "c", "user": map[string]any{"age": 30}},
+		{"name": "a", "user": map[string]any{"age": 20}},
+		{"name": "x"},
+		{"name": "b", "user": map[string]any{"age": 30}},
+	})
+	names := func(v Value) string {
+		s := ""
+		for i := 0; i < v.Len(); i++ {
+			s += v.Index(i).Get("name").String()
+		}
+		return s
+	}
+
+	if got := names(users.SortBy("user.age", false)); got != "acbx" {
+		t.Errorf("SortBy asc = %s, want acbx", got)
+	}
+	if got := names(users.SortBy("user.age", true)); got != "cbax" {
+		t.Errorf("SortBy desc = %s, want cbax (stable, missing last)", got)
+	}
+	if got := names(users.Sort(func(a, b Value) bool { return a.Get("name").Less(b.Get("name")) })); got != "abcx" {
+		t.Errorf("Sort = %s", got)
+	}
+	if names(users) != "caxb" {
+		t.Error("Sort modified the receiver")
+	}
+}
+
+func TestValue_GroupBy(t *testing.T) {
+	orders := New([]map[string]any{
+		{"id": 1, "status": "paid"},
+		{"id": 2, "status": "open"},
+		{"id": 3, "status": "paid"},
+		{"id": 4},
+	})
+
+	g := orders.GroupBy("status")
+	if g.Len() != 3 || g.Get("paid").Len() != 2 || g.At("paid", 1, "id").Int() != 3 || g.Get("null").Len() != 1 {
+		t.Errorf("GroupBy = %v", g)
+	}
+
+	parity := orders.GroupByFn(func(e Value) Value {
+		if e.Get("id").Int()%2 == 0 {
+			return New("even")
+		}
+		return New("odd")
+	})
+	if parity.Get("even").Len() != 2 || parity.Get("odd").Len() != 2 {
+		t.Errorf("GroupByFn = %v", parity)
+	}
+}
+
+func TestValue_Unique(t *testing.T) {
+	v := New([]any{1, "a", 1, map[string]any{"x": 1}, "a", map[string]any{"x": 1}, 0.0, -0.0})
+	want := New([]any{1, "a", map[string]any{"x": 1}, 0})
+	if got := v.Unique(); !got.Equal(want) {
+		t.Errorf("Unique = %v, want %v", got, want)
+	}
+
+	users := New([]map[string]any{{"id": 1, "n": "a"}, {"id": 2, "n": "b"}, {"id": 1, "n": "c"}})
+	if got := users.UniqueBy("id"); got.Len() != 2 || got.At(1, "n").String() != "b" {
+		t.Errorf("UniqueBy = %v", got)
+	}
+}
+
+func TestValue_SetOperations(t *testing.T) {
+	a := New([]int{1, 2, 2, 3})
+	b := New([]int{3, 4, 1})
+
+	tests := []struct {
+		name string
+		got  Value
+		want []int
+	}{
+		{"Union", a.Union(b), []int{1, 2, 3, 4}},
+		{"Intersect", a.Intersect(b), []int{1, 3}},
+		{"Difference", a.Difference(b), []int{2}},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(New(tt.want)) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	old := New([]map[string]any{{"id": 1, "v": "a"}, {"id": 2, "v": "b"}})
+	cur := New([]map[string]any{{"id": 2, "v": "B"}, {"id": 3, "v": "c"}})
+	if got := cur.DifferenceBy(old, "id"); got.Len() != 1 || got.At(0, "id").Int() != 3 {
+		t.Errorf("DifferenceBy = %v", got)
+	}
+	if got := old.UnionBy(cur, "id"); got.Len() != 3 || got.At(1, "v").String() != "b" {
+		t.Errorf("UnionBy = %v", got)
+	}
+	if got := old.IntersectBy(cur, "id"); got.Len() != 1 || got.At(0, "v").String() != "b" {
+		t.Errorf("IntersectBy = %v", got)
+	}
+}
+
+func TestValue_Chunk(t *testing.T) {
+	v := New([]int{1, 2, 3, 4, 5})
+	if got := v.Chunk(2); !got.Equal(New([][]int{{1, 2}, {3, 4}, {5}})) {
+		t.Errorf("Chunk(2) = %v", got)
+	}
+	if !v.Chunk(0).IsInvalid() {
+		t.Error("Chunk(0) should be Invalid")
+	}
+
+	var sizes []int
+	err := v.Batch(3, func(c Value) error {
+		sizes = append(sizes, c.Len())
+		return nil
+	})
+	if err != nil || len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 2 {
+		t.Errorf("Batch sizes = %v, %v", sizes, err)
+	}
+}
+
+func TestValue_Reverse(t *testing.T) {
+	tests := []struct {
+		in, want Value
+	}{
+		{New([]int{1, 2, 3}), New([]int{3, 2, 1})},
+		{New("Việt Nam 🇻"), New("🇻 maN tệiV")},
+		{New([]byte{1, 2}), New([]byte{2, 1})},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Reverse(); !got.Equal(tt.want) {
+			t.Errorf("Reverse(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if !New(1).Reverse().IsInvalid() {
+		t.Error("Reverse on Number should be Invalid")
+	}
+}
+
+func TestValue_ContainsIndexOf(t *testing.T) {
+	arr := New([]any{1, map[string]any{"id": 2}, "x"})
+	if !arr.Contains(New(map[string]any{"id": 2})) || arr.IndexOf(New("x")) != 2 || arr.Contains(New("1")) {
+		t.Error("Array Contains/IndexOf")
+	}
+	if !New("hello world").Contains(New("o w")) || New("hello").IndexOf(New("l")) != 2 {
+		t.Error("String Contains/IndexOf")
+	}
+	if New([]byte("abc")).IndexOf(New("c")) != 2 {
+		t.Error("Bytes IndexOf")
+	}
+	m := New(map[string]any{"k": nil})
+	if !m.Contains(New("k")) || m.Contains(New("z")) || m.IndexOf(New("k")) != -1 {
+		t.Error("Map Contains should check key presence")
+	}
+}
+
+func TestValue_KeysValues(t *testing.T) {
+	m := New(map[string]any{"b": 2, "a": 1, "c": 3})
+	if got := m.SortedKeys(); !got.Equal(New([]string{"a", "b", "c"})) {
+		t.Errorf("SortedKeys = %v", got)
+	}
+	if got := m.SortedValues(); !got.Equal(New([]int{1, 2, 3})) {
+		t.Errorf("SortedValues = %v", got)
+	}
+	if m.Keys().Len() != 3 || m.Values().Sum().Int() != 6 {
+		t.Error("Keys/Values")
+	}
+	if !New([]int{1}).Keys().IsInvalid() {
+		t.Error("Keys on Array should be Invalid")
+	}
+}
+
+func TestValue_CountBy(t *testing.T) {
+	events := New([]map[string]any{
+		{"type": "click"}, {"type": "view"}, {"type": "click"}, {},
+	})
+	want := New(map[string]any{"click": 2, "view": 1, "null": 1})
+	if got := events.CountBy("type"); !got.Equal(want) {
+		t.Errorf("CountBy = %v, want %v", got, want)
+	}
+
+	hist := New([]int{1, 5, 12, 15, 30}).CountByFn(func(x Value) Value {
+		return New(x.Int() / 10 * 10)
+	})
+	if hist.Get("10").Int() != 2 || hist.Get("0").Int() != 2 || hist.Get("30").Int() != 1 {
+		t.Errorf("CountByFn = %v", hist)
+	}
+}
+
+func TestValue_Partition(t *testing.T) {
+	records := New([]map[string]any{{"qty": 2}, {"qty": -1}, {"qty": 5}, {}})
+	valid, invalid := records.Partition(func(r Value) bool { return r.Get("qty").Int() > 0 })
+	if valid.Len() != 2 || invalid.Len() != 2 || valid.At(1, "qty").Int() != 5 {
+		t.Errorf("Partition = %v / %v", valid, invalid)
+	}
+	if a, b := New("x").Partition(func(Value) bool { return true }); !a.IsInvalid() || !b.IsInvalid() {
+		t.Error("Partition on String should be Invalid")
+	}
+}
+
+func TestValue_Flatten(t *testing.T) {
+	v := New([]any{1, []any{2, []any{3, []any{4}}}, 5})
+	if got := v.Flatten(1); !got.Equal(New([]any{1, 2, []any{3, []any{4}}, 5})) {
+		t.Errorf("Flatten(1) = %v", got)
+	}
+	if got := v.Flatten(-1); !got.Equal(New([]int{1, 2, 3, 4, 5})) {
+		t.Errorf("Flatten(-1) = %v", got)
+	}
+
+	orders := New([]map[string]any{{"items": []string{"a", "b"}}, {"items": []string{"c"}}, {"items": "d"}})
+	if got := orders.FlatMap(func(o Value) Value { return o.Get("items") }); !got.Equal(New([]string{"a", "b", "c", "d"})) {
+		t.Errorf("FlatMap = %v", got)
+	}
+}
+
+func TestValue_EverySome(t *testing.T) {
+	items := New([]map[string]any{{"qty": 1}, {"qty": 3}, {"qty": 0}})
+	positive := func(x Value) bool { return x.Get("qty").Int() > 0 }
+
+	calls := 0
+	counting := func(x Value) bool { calls++; return positive(x) }
+	if items.Every(counting) {
+		t.Error("Every should be false")
+	}
+	if calls != 3 {
+		t.Errorf("Every evaluated %d elements", calls)
+	}
+	calls = 0
+	if !items.Some(counting) || calls != 1 {
+		t.Errorf("Some should short-circuit after 1 call, made %d", calls)
+	}
+
+	if !New([]int{}).Every(positive) || New([]int{}).Some(positive) {
+		t.Error("empty Array: Every true, Some false")
+	}
+	if !New(map[string]any{"a": 1, "b": 2}).Every(func(x Value) bool { return x.Int() > 0 }) {
+		t.Error("Every over Map values")
+	}
+}
+
+func TestValue_EqualGoValues(t *testing.T) {
+	fn := func(x int) int { return x }
+	type tagged struct {
+		Tags []string
+		Any  any
+	}
+	if !New(fn).Equal(New(fn)) || New(fn).Equal(New(func(x int) int { return -x })) {
+		t.Error("Funcs should compare by identity")
+	}
+	if !New(tagged{Tags: []string{"a"}}).Equal(New(tagged{Tags: []string{"a"}})) ||
+		New(tagged{Any: []int{1}}).Equal(New(tagged{Any: []int{2}})) {
+		t.Error("uncomparable Structs should compare deeply")
+	}
+	if got := New([]any{fn, fn, 1}).Unique(); got.Len() != 2 {
+		t.Errorf("Unique over Funcs = %d elements, want 2", got.Len())
+	}
+}
+
+func TestValue_Compare(t *testing.T) {
+	ordered := []Value{
+		{},
+		New(nil),
+		New(false),
+		New(true),
+		New(math.NaN()),
+		New(-1),
+		New(2),
+		New(time.Unix(0, 0)),
+		New(time.Second),
+		New(""),
+		New("a"),
+		New([]byte("a")),
+		New([]any{1}),
+		New([]any{1, 2}),
+		New([]any{2}),
+		New(map[string]any{"a": 1}),
+		New(map[string]any{"a": 1, "b": 0}),
+		New(map[string]any{"a": 2}),
+		New(map[string]any{"b": 0}),
+	}
+	for i, a := range ordered {
+		for j, b := range ordered {
+			if got, want := a.Compare(b), cmp.Compare(i, j); got != want {
+				t.Errorf("%+v.Compare(%+v) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+	type tagged struct{ Tags []string }
+	structs := New([]any{tagged{[]string{"b"}}, tagged{}, tagged{[]string{"a"}}})
+	if got := structs.SortBy("", false); got.Index(0).Compare(New(tagged{})) != 0 || got.Index(2).Compare(New(tagged{[]string{"b"}})) != 0 {
+		t.Errorf("SortBy Structs with slices = %v", got)
+	}
+	if New(0).Compare(New(math.Copysign(0, -1))) != 0 {
+		t.Error("-0 should compare equal to 0")
+	}
+
+	mixed := New([]any{"b", 3, nil, true, "a", 1})
+	want := New([]any{true, 1, 3, "a", "b", nil})
+	if got := mixed.SortBy("", false); !got.Equal(want) {
+		t.Errorf("SortBy mixed = %v, want %v", got, want)
+	}
+}
+
+func TestValue_ToBool(t *testing.T) {
+	cases := []struct {
+		in   any
+		want Value
+	}{
+		{"yes", New(true)}, {" ON ", New(true)}, {"1", New(true)}, {"T", New(true)},
+		{"no", New(false)}, {"Off", New(false)}, {"0", New(false)}, {[]byte("false"), New(false)},
+		{1, New(true)}, {0, New(false)}, {true, New(true)},
+		{"", Value{K: Invalid}}, {"nope", Value{K: Invalid}}, {2, Value{K: Invalid}}, {nil, Value{K: Invalid}},
+	}
+	for _, c := range cases {
+		if got := New(c.in).ToBool(); got.K != c.want.K || !got.Equal(c.want) {
+			t.Errorf("ToBool(%#v) = %s, want %s", c.in, got, c.want)
+		}
+	}
+
+	yes, no := []string{"ja", "j"}, []string{"nein"}
+	if got := New("JA").ToBoolWords(yes, no); !got.IsTrue() {
+		t.Errorf("ToBoolWords(JA) = %s", got)
+	}
+	if got := New("Nein").ToBoolWords(yes, no); got.K != Bool || got.IsTrue() {
+		t.Errorf("ToBoolWords(Nein) = %s", got)
+	}
+	if got := New("yes").ToBoolWords(yes, no); got.K != Invalid {
+		t.Errorf("ToBoolWords(yes) = %s, want Invalid", got)
+	}
+}
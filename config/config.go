@@ -0,0 +1,348 @@
+// Package config loads layered configuration into a single kit.Value.
+//
+// Layers are merged with kit.Merge in a fixed order of precedence, lowest
+// first: defaults, files in the order given, environment variables, then
+// command-line flags. The order of the options passed to Load does not
+// change precedence.
+//
+//	cfg, err := config.Load(
+//		config.Defaults(map[string]any{"http": map[string]any{"addr": ":8080"}}),
+//		config.File("/etc/app/config.yaml"),
+//		config.OptionalFile("config.local.toml"),
+//		config.Env("APP_", kit.EnvSeparator("__")),
+//		config.Flags(os.Args[1:]),
+//	)
+//	addr := cfg.String("http.addr")
+//
+// A Config can reload itself: Reload re-reads every layer and swaps the
+// result in atomically, Watch does so whenever a file changes, and
+// Subscribe runs a callback when the value under a path changes.
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kitwork/kit"
+)
+
+type file struct {
+	path     string
+	optional bool
+}
+
+type options struct {
+	defaults  kit.Value
+	files     []file
+	env       bool
+	envPrefix string
+	envOpts   []kit.EnvOption
+	flags     bool
+	args      []string
+	schema    *kit.ObjectSchema
+	onError   func(error)
+}
+
+// Option configures Load.
+type Option func(*options)
+
+// Defaults sets the lowest layer; d is converted with kit.New.
+func Defaults(d any) Option {
+	return func(o *options) { o.defaults = kit.New(d) }
+}
+
+// File adds a configuration file that must exist. The format follows the
+// extension: .json, .yaml or .yml, and .toml.
+func File(path string) Option {
+	return func(o *options) { o.files = append(o.files, file{path: path}) }
+}
+
+// OptionalFile is File for a file that may be missing.
+func OptionalFile(path string) Option {
+	return func(o *options) { o.files = append(o.files, file{path: path, optional: true}) }
+}
+
+// Env adds the environment variables starting with prefix, mapped as by
+// kit.FromEnv. With the default "_" separator APP_DB_HOST sets db.host;
+// use kit.EnvSeparator("__") to reach keys that contain underscores.
+func Env(prefix string, opts ...kit.EnvOption) Option {
+	return func(o *options) { o.env, o.envPrefix, o.envOpts = true, prefix, opts }
+}
+
+// Flags adds command-line arguments parsed by kit.ParseFlags, so
+// --db.port=5432 sets db.port. Positional arguments are available from
+// Config.Args.
+func Flags(args []string) Option {
+	return func(o *options) { o.flags, o.args = true, args }
+}
+
+// Schema validates the merged configuration with s.Conform, which coerces
+// kinds and fills defaults. Conform drops undeclared keys, so the schema
+// must declare every key the program reads.
+func Schema(s *kit.ObjectSchema) Option {
+	return func(o *options) { o.schema = s }
+}
+
+// OnError sets the function that receives the errors of reloads started
+// by Watch; the previous configuration stays in place. Without it such
+// errors are dropped.
+func OnError(fn func(error)) Option {
+	return func(o *options) { o.onError = fn }
+}
+
+// Config is a loaded configuration. It is safe for concurrent use.
+type Config struct {
+	opts  options
+	state atomic.Pointer[state]
+
+	reload sync.Mutex // serializes Reload, including its notifications
+
+	subsMu sync.Mutex
+	next   uint64
+	subs   []subscription
+}
+
+type state struct {
+	root  kit.Value
+	args  []string
+	stamp string // the files' fingerprint, taken before they were read
+}
+
+type subscription struct {
+	id   uint64
+	path string
+	fn   func(old, new kit.Value)
+}
+
+// Load reads every layer, merges them and validates the result. Errors name
+// the layer that failed.
+func Load(opts ...Option) (*Config, error) {
+	c := &Config{}
+	for _, opt := range opts {
+		opt(&c.opts)
+	}
+	stamp := c.opts.stat()
+	root, args, err := c.opts.load()
+	if err != nil {
+		return nil, err
+	}
+	c.state.Store(&state{root: root, args: args, stamp: stamp})
+	return c, nil
+}
+
+// Reload re-reads every layer and, when that succeeds, replaces the
+// configuration in one atomic step, so readers see either the old or the
+// new tree and never a mix. It then calls the subscribers whose path
+// changed, in subscription order, before returning. On error the current
+// configuration is kept.
+func (c *Config) Reload() error {
+	c.reload.Lock()
+	defer c.reload.Unlock()
+
+	stamp := c.opts.stat()
+	root, args, err := c.opts.load()
+	if err != nil {
+		return err
+	}
+	old := c.state.Swap(&state{root: root, args: args, stamp: stamp}).root
+
+	c.subsMu.Lock()
+	subs := c.subs
+	c.subsMu.Unlock()
+	for _, s := range subs {
+		before, after := old.Path(s.path), root.Path(s.path)
+		if old.Exists(s.path) != root.Exists(s.path) || !before.Equal(after) {
+			s.fn(before, after)
+		}
+	}
+	return nil
+}
+
+// Subscribe registers fn to run after a Reload that changes the value at
+// path, or anything below it; "" watches the whole tree. fn receives the
+// old and new values at path. The returned function removes the
+// subscription.
+func (c *Config) Subscribe(path string, fn func(old, new kit.Value)) (cancel func()) {
+	c.subsMu.Lock()
+	c.next++
+	id := c.next
+	c.subs = append(c.subs, subscription{id: id, path: path, fn: fn})
+	c.subsMu.Unlock()
+
+	return func() {
+		c.subsMu.Lock()
+		defer c.subsMu.Unlock()
+		for i, s := range c.subs {
+			if s.id == id {
+				c.subs = append(c.subs[:i:i], c.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Watch polls the configuration files every interval and calls Reload
+// when one of them is modified, created or removed, until ctx is done. It
+// returns ctx.Err(). Polling keeps Watch portable and catches editors that
+// replace files by renaming; reload errors go to the OnError function.
+func (c *Config) Watch(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	tried := "" // a file set that failed to load is reported only once
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+		cur := c.opts.stat()
+		if cur == c.state.Load().stamp || cur == tried {
+			continue
+		}
+		tried = cur
+		if err := c.Reload(); err != nil && c.opts.onError != nil {
+			c.opts.onError(err)
+		}
+	}
+}
+
+// stat fingerprints the files by size and modification time.
+func (o *options) stat() string {
+	var b strings.Builder
+	for _, f := range o.files {
+		if fi, err := os.Stat(f.path); err == nil {
+			fmt.Fprintf(&b, "%d/%d;", fi.Size(), fi.ModTime().UnixNano())
+		} else {
+			b.WriteString("-;")
+		}
+	}
+	return b.String()
+}
+
+func (o *options) load() (kit.Value, []string, error) {
+	layers := []kit.Value{kit.New(map[string]any{}), o.defaults}
+	for _, f := range o.files {
+		v, err := ReadFile(f.path)
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return kit.Value{}, nil, err
+		}
+		layers = append(layers, v)
+	}
+	if o.env {
+		layers = append(layers, kit.FromEnv(o.envPrefix, o.envOpts...))
+	}
+	var args []string
+	if o.flags {
+		v, rest, err := kit.ParseFlags(o.args)
+		if err != nil {
+			return kit.Value{}, nil, fmt.Errorf("config: flags: %w", err)
+		}
+		layers = append(layers, v)
+		args = rest
+	}
+
+	root := kit.Merge(layers...)
+	if o.schema != nil {
+		v, err := o.schema.Conform(root)
+		if err != nil {
+			return kit.Value{}, nil, fmt.Errorf("config: %w", err)
+		}
+		root = v
+	}
+	return root, args, nil
+}
+
+// ReadFile decodes a single configuration file, choosing the format from
+// its extension as kit.FormatOf does. CSV is not a configuration format.
+func ReadFile(path string) (kit.Value, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return kit.Value{}, fmt.Errorf("config: %w", err)
+	}
+	format := kit.FormatOf(path)
+	if format == kit.FormatAuto || format == kit.FormatCSV {
+		return kit.Value{}, fmt.Errorf("config: %s: unknown format %q", path, filepath.Ext(path))
+	}
+	v, err := kit.Decode(data, format)
+	if err != nil {
+		return kit.Value{}, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if v.IsNil() {
+		v = kit.New(map[string]any{})
+	}
+	if v.K != kit.Map {
+		return kit.Value{}, fmt.Errorf("config: %s: top level is %s, want Map", path, v.K)
+	}
+	return v, nil
+}
+
+// Value returns the whole configuration as a Map. The tree is never
+// modified; a Reload replaces it.
+func (c *Config) Value() kit.Value { return c.state.Load().root }
+
+// Args returns the positional arguments left over by Flags.
+func (c *Config) Args() []string { return c.state.Load().args }
+
+// Get resolves a dot-separated path as kit.Value.Path does.
+func (c *Config) Get(path string) kit.Value { return c.Value().Path(path) }
+
+// Has reports whether path is set, even to null.
+func (c *Config) Has(path string) bool { return c.Value().Exists(path) }
+
+// String returns the text form of a scalar at path, or "" when it is
+// missing or a collection.
+func (c *Config) String(path string) string {
+	switch v := c.Get(path); v.K {
+	case kit.Map, kit.Array, kit.Nil, kit.Invalid:
+		return ""
+	default:
+		return v.Text()
+	}
+}
+
+// Int returns the integer at path, accepting numeric Strings such as
+// those from JSON files; anything else is 0.
+func (c *Config) Int(path string) int64 {
+	return int64(c.Float(path))
+}
+
+// Float returns the number at path, accepting numeric Strings; anything
+// else is 0.
+func (c *Config) Float(path string) float64 {
+	v := c.Get(path)
+	if v.K == kit.String {
+		v = kit.FromText(v.Text())
+	}
+	if v.K != kit.Number {
+		return 0
+	}
+	return v.Float()
+}
+
+// Bool returns the boolean at path, accepting the Strings ToBool does,
+// such as "yes", "on" and "1"; anything else is false.
+func (c *Config) Bool(path string) bool {
+	return c.Get(path).ToBool().IsTrue()
+}
+
+// Duration returns the duration at path as converted by ToDuration, so
+// "1m30s", "PT90S" and a number of seconds all work; anything else is 0.
+func (c *Config) Duration(path string) time.Duration {
+	v := c.Get(path).ToDuration()
+	if v.K != kit.Duration {
+		return 0
+	}
+	return v.MustDuration()
+}
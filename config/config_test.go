@@ -0,0 +1,175 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitwork/kit"
+)
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	dir := t.TempDir()
+	yml := writeFile(t, dir, "base.yaml", "http:\n  addr: \":80\"\n  timeout: 5s\ndb:\n  host: db1\n  port: 5432\n")
+	toml := writeFile(t, dir, "local.toml", "[db]\nhost = \"db2\"\n")
+	t.Setenv("KITCFG_DB_PORT", "6543")
+	t.Setenv("KITCFG_DB_TLS", "on")
+
+	cfg, err := Load(
+		Flags([]string{"--http.addr=:9090", "serve"}),
+		Env("KITCFG_"),
+		File(yml),
+		OptionalFile(toml),
+		OptionalFile(filepath.Join(dir, "missing.json")),
+		Defaults(map[string]any{"debug": true, "db": map[string]any{"host": "localhost"}}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.String("http.addr"); got != ":9090" {
+		t.Errorf("http.addr = %q, want flag value", got)
+	}
+	if got := cfg.Int("db.port"); got != 6543 {
+		t.Errorf("db.port = %d, want env value", got)
+	}
+	if got := cfg.String("db.host"); got != "db2" {
+		t.Errorf("db.host = %q, want later file", got)
+	}
+	if !cfg.Bool("db.tls") {
+		t.Error(`db.tls = false, want env value "on"`)
+	}
+	if !cfg.Bool("debug") || cfg.Duration("http.timeout") != 5*time.Second {
+		t.Errorf("debug = %v, timeout = %v", cfg.Bool("debug"), cfg.Duration("http.timeout"))
+	}
+	if args := cfg.Args(); len(args) != 1 || args[0] != "serve" {
+		t.Errorf("Args = %q", args)
+	}
+	if cfg.Has("nope") || cfg.String("db") != "" || cfg.Int("nope") != 0 {
+		t.Error("missing keys")
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := t.TempDir()
+	bad := writeFile(t, dir, "bad.json", "{")
+	list := writeFile(t, dir, "list.yaml", "- a\n")
+	ini := writeFile(t, dir, "x.ini", "a=1")
+
+	for name, opt := range map[string]Option{
+		"missing":   File(filepath.Join(dir, "missing.yaml")),
+		"malformed": File(bad),
+		"not a map": File(list),
+		"format":    File(ini),
+	} {
+		if _, err := Load(opt); err == nil {
+			t.Errorf("%s: Load succeeded", name)
+		} else if name != "missing" && !strings.Contains(err.Error(), dir) {
+			t.Errorf("%s: error %q does not name the file", name, err)
+		}
+	}
+}
+
+func TestLoad_Schema(t *testing.T) {
+	s := kit.Schema().
+		Field("port", kit.Num().Int().Min(1)).
+		Default("name", kit.Str(), "kit")
+
+	cfg, err := Load(Defaults(map[string]any{"port": "8080"}), Schema(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Get("port").K != kit.Number || cfg.String("name") != "kit" {
+		t.Errorf("conformed = %v", cfg.Value())
+	}
+
+	if _, err := Load(Defaults(map[string]any{"port": 0}), Schema(s)); err == nil {
+		t.Error("invalid config accepted")
+	}
+}
+
+func TestConfig_ReloadSubscribe(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "app.json", `{"db": {"host": "a", "port": 1}, "name": "x"}`)
+	cfg, err := Load(File(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dbCalls, nameCalls int
+	var oldHost, newHost string
+	cfg.Subscribe("db", func(old, new kit.Value) {
+		dbCalls++
+		oldHost, newHost = old.Get("host").Text(), new.Get("host").Text()
+	})
+	cancel := cfg.Subscribe("name", func(old, new kit.Value) { nameCalls++ })
+
+	writeFile(t, dir, "app.json", `{"db": {"host": "b", "port": 1}, "name": "x"}`)
+	if err := cfg.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if dbCalls != 1 || nameCalls != 0 || oldHost != "a" || newHost != "b" {
+		t.Errorf("db calls = %d (%s -> %s), name calls = %d", dbCalls, oldHost, newHost, nameCalls)
+	}
+	if cfg.String("db.host") != "b" {
+		t.Errorf("db.host = %q after Reload", cfg.String("db.host"))
+	}
+
+	cancel()
+	writeFile(t, dir, "app.json", `{"db": {"host": "b", "port": 1}, "name": "y"}`)
+	if err := cfg.Reload(); err != nil || nameCalls != 0 || dbCalls != 1 {
+		t.Errorf("after cancel: err = %v, db = %d, name = %d", err, dbCalls, nameCalls)
+	}
+
+	writeFile(t, dir, "app.json", `{`)
+	if err := cfg.Reload(); err == nil {
+		t.Error("Reload of a malformed file succeeded")
+	}
+	if cfg.String("name") != "y" {
+		t.Error("failed Reload replaced the configuration")
+	}
+}
+
+func TestConfig_Watch(t *testing.T) {
+	dir := t.TempDir()
+	path := writeFile(t, dir, "app.yaml", "level: info\n")
+	errs := make(chan error, 1)
+	cfg, err := Load(File(path), OnError(func(err error) { errs <- err }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(chan string, 1)
+	cfg.Subscribe("level", func(_, new kit.Value) { changed <- new.Text() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- cfg.Watch(ctx, time.Millisecond) }()
+
+	writeFile(t, dir, "app.yaml", "level: debug\n")
+	select {
+	case got := <-changed:
+		if got != "debug" {
+			t.Errorf("level = %q", got)
+		}
+	case err := <-errs:
+		t.Fatal(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload after the file changed")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch = %v", err)
+	}
+}
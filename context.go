@@ -0,0 +1,54 @@
+package kit
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+type ctxKey struct{}
+
+// scope is the mutable, request-scoped holder stored in a context. Writes
+// replace the root copy-on-write, so a Value read earlier stays unchanged.
+type scope struct {
+	mu sync.RWMutex
+	v  Value
+}
+
+// ErrNoScope is returned by SetContext when the context carries no Value.
+var ErrNoScope = errors.New("kit: context has no Value scope")
+
+// WithValue returns a child context carrying a new scope seeded with v.
+// Writes made through SetContext on the child never reach parent scopes.
+func WithValue(ctx context.Context, v Value) context.Context {
+	return context.WithValue(ctx, ctxKey{}, &scope{v: v})
+}
+
+// FromContext returns the current Value of the nearest scope, or Nil.
+func FromContext(ctx context.Context) Value {
+	s, ok := ctx.Value(ctxKey{}).(*scope)
+	if !ok {
+		return Value{K: Nil}
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.v
+}
+
+// SetContext stores x at the dot-separated path of the nearest scope, so
+// middleware can accumulate request data (claims, trace attributes) that
+// later layers read with FromContext.
+func SetContext(ctx context.Context, path string, x any) error {
+	s, ok := ctx.Value(ctxKey{}).(*scope)
+	if !ok {
+		return ErrNoScope
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out, err := s.v.setIn(splitPath(path), New(x))
+	if err != nil {
+		return err
+	}
+	s.v = out
+	return nil
+}
@@ -0,0 +1,33 @@
+package kit
+
+import (
+	"context"
+	"testing"
+)
+
+func TestContextScope(t *testing.T) {
+	if err := SetContext(context.Background(), "a", 1); err != ErrNoScope {
+		t.Errorf("SetContext without scope = %v, want ErrNoScope", err)
+	}
+	if !FromContext(context.Background()).IsNil() {
+		t.Error("FromContext without scope should be Nil")
+	}
+
+	ctx := WithValue(context.Background(), New(map[string]any{"req": "r1"}))
+	before := FromContext(ctx)
+	if err := SetContext(ctx, "auth.user", "an"); err != nil {
+		t.Fatal(err)
+	}
+	if got := FromContext(ctx).Path("auth.user").String(); got != "an" {
+		t.Errorf("auth.user = %q", got)
+	}
+	if before.Get("auth").IsValid() {
+		t.Error("earlier read observed a later write")
+	}
+
+	child := WithValue(ctx, FromContext(ctx))
+	SetContext(child, "trace", "t1")
+	if FromContext(ctx).Get("trace").IsValid() {
+		t.Error("child scope write leaked into parent")
+	}
+}
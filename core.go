@@ -2,9 +2,12 @@ package kit
 
 import (
 	"bytes"
+	"cmp"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 	"unsafe"
 )
@@ -23,7 +26,7 @@ const (
 	// --- Scalar Types (Fast-path, data stored in N) ---
 	Number   // float64
 	Bool     // 0/1 in N
-	Time     // UnixNano stored in N
+	Time     // UnixNano stored in N, *time.Location in V (nil = Local)
 	Duration // Nanoseconds stored in N
 
 	// --- Reference Types (Slow-path, data stored in V) ---
@@ -36,8 +39,33 @@ const (
 	Struct // Go struct or pointer
 	Func   // Callable function / pipe
 	Any    // Opaque Go interface{}
+	Error  // Failed operation; the error is stored in V
 )
 
+var kindNames = [...]string{
+	Invalid:  "Invalid",
+	Nil:      "Nil",
+	Number:   "Number",
+	Bool:     "Bool",
+	Time:     "Time",
+	Duration: "Duration",
+	String:   "String",
+	Bytes:    "Bytes",
+	Map:      "Map",
+	Array:    "Array",
+	Struct:   "Struct",
+	Func:     "Func",
+	Any:      "Any",
+	Error:    "Error",
+}
+
+func (k Kind) String() string {
+	if int(k) < len(kindNames) {
+		return kindNames[k]
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
 // Value is the atomic runtime unit of the engine.
 // 24 bytes on 64-bit for cache & stack efficiency.
 type Value struct {
@@ -76,6 +104,21 @@ func (v Value) IsCallable() bool  { return v.K == Func }
 func (v Value) IsReference() bool { return v.K >= String }
 func (v Value) IsObject() bool    { return v.K >= String && v.V != nil }
 
+// IsNilCollection reports whether v is an Array, Map or Bytes built from a
+// nil Go slice or map. Such Values behave as empty but encode as null, so
+// API payloads keep the difference between "none" and "empty".
+func (v Value) IsNilCollection() bool {
+	switch x := v.V.(type) {
+	case []Value:
+		return x == nil
+	case map[string]Value:
+		return x == nil
+	case []byte:
+		return x == nil
+	}
+	return false
+}
+
 func (v Value) IsIterable() bool {
 	switch v.K {
 	case Array, Map, Bytes:
@@ -86,15 +129,74 @@ func (v Value) IsIterable() bool {
 }
 
 // Truthy evaluates logical truthiness:
-// - Scalars: N > 0
+// - Scalars: N > 0 (so NaN is false)
 // - Objects: non-nil
+// - Errors: false
 func (v Value) Truthy() bool {
 	if v.IsImmediate() {
 		return v.N > 0
 	}
+	if v.K == Error {
+		return false
+	}
+	if v.K == Func {
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().Truthy()
+		}
+	}
 	return v.IsObject()
 }
 
+// boolWords are the words ToBool accepts, in lower case.
+var boolWords = map[string]bool{
+	"true": true, "t": true, "yes": true, "y": true, "on": true, "1": true,
+	"false": false, "f": false, "no": false, "n": false, "off": false, "0": false,
+}
+
+// ToBool converts v to the Bool kind the way env vars and form fields
+// spell booleans: "true", "yes", "y", "on", "t" and "1" are true, "false",
+// "no", "n", "off", "f" and "0" are false, ignoring case and surrounding
+// space. The Numbers 1 and 0 convert too and Bools are returned as is;
+// anything else is Invalid, so a typo is not mistaken for false.
+func (v Value) ToBool() Value {
+	return v.toBool(func(w string) (bool, bool) {
+		b, ok := boolWords[w]
+		return b, ok
+	})
+}
+
+// ToBoolWords is ToBool with caller-chosen words, compared ignoring case:
+//
+//	v.ToBoolWords([]string{"ja", "j"}, []string{"nein", "n"})
+func (v Value) ToBoolWords(yes, no []string) Value {
+	return v.toBool(func(w string) (bool, bool) {
+		for _, y := range yes {
+			if strings.EqualFold(w, y) {
+				return true, true
+			}
+		}
+		for _, n := range no {
+			if strings.EqualFold(w, n) {
+				return false, true
+			}
+		}
+		return false, false
+	})
+}
+
+func (v Value) toBool(lookup func(word string) (bool, bool)) Value {
+	v = v.Force()
+	switch v.K {
+	case Bool:
+		return v
+	case Number, String, Bytes:
+		if b, ok := lookup(strings.ToLower(strings.TrimSpace(v.Text()))); ok {
+			return New(b)
+		}
+	}
+	return Value{K: Invalid}
+}
+
 /* =============================================================================
    3. STRINGIFY & CONVERSION
    ============================================================================= */
@@ -109,6 +211,9 @@ func (v Value) Append(b []byte) []byte {
 	case String:
 		return append(b, v.String()...)
 	case Number:
+		if n, ok := v.literal(); ok {
+			return append(b, n...)
+		}
 		i := int64(v.N)
 		if v.N == float64(i) {
 			return strconv.AppendInt(b, i, 10)
@@ -122,11 +227,21 @@ func (v Value) Append(b []byte) []byte {
 	case Nil:
 		return append(b, "null"...)
 	case Time:
-		return time.Unix(0, int64(v.N)).AppendFormat(b, time.RFC3339)
+		return v.goTime().AppendFormat(b, time.RFC3339)
 	case Duration:
 		return append(b, time.Duration(int64(v.N)).String()...)
 	case Bytes:
 		return append(b, v.Bytes()...)
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().Append(b)
+		}
+		return b
+	case Error:
+		if err := v.Err(); err != nil {
+			return append(b, err.Error()...)
+		}
+		return append(b, "error"...)
 	default:
 		return b
 	}
@@ -139,7 +254,16 @@ func (v Value) String() string {
 	return ""
 }
 
-func (v Value) Int() int64     { return int64(v.N) }
+// Int returns the integer part of a Number, exactly for one decoded from a
+// json.Number beyond ±2^53.
+func (v Value) Int() int64 {
+	if n, ok := v.literal(); ok {
+		i, _ := n.Int64()
+		return i
+	}
+	return int64(v.N)
+}
+
 func (v Value) Float() float64 { return v.N }
 
 func (v Value) Bytes() []byte {
@@ -149,6 +273,55 @@ func (v Value) Bytes() []byte {
 	return nil
 }
 
+// Export converts v back into plain Go data: float64, bool, time.Time,
+// time.Duration, string, []byte, []any and map[string]any. Struct, Func,
+// Any and Error kinds return the wrapped Go value.
+func (v Value) Export() any {
+	switch v.K {
+	case Number:
+		return v.N
+	case Bool:
+		return v.N > 0
+	case Time:
+		return v.goTime()
+	case Duration:
+		return time.Duration(int64(v.N))
+	case String:
+		return v.String()
+	case Bytes:
+		return v.Bytes()
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return []any(nil)
+		}
+		out := make([]any, len(a))
+		for i, e := range a {
+			out[i] = e.Export()
+		}
+		return out
+	case Map:
+		m := v.mapping()
+		if m == nil {
+			return map[string]any(nil)
+		}
+		out := make(map[string]any, len(m))
+		for k, e := range m {
+			out[k] = e.Export()
+		}
+		return out
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().Export()
+		}
+		return v.V
+	case Struct, Any, Error:
+		return v.V
+	default:
+		return nil
+	}
+}
+
 // AsBytes provides a zero-copy read-only view into string data.
 func (v Value) AsBytes() []byte {
 	if v.K == Bytes {
@@ -188,7 +361,7 @@ func (a Value) Extend(b Value) Value {
 	case a.K == String || b.K == String:
 		return Value{K: String, V: a.Text() + b.Text()}
 	case a.K == Time && b.K == Duration:
-		return Value{K: Time, N: a.N + b.N}
+		return Value{K: Time, N: a.N + b.N, V: a.V}
 	default:
 		return Value{K: Invalid}
 	}
@@ -199,7 +372,7 @@ func (a Value) Sub(b Value) Value {
 		return Value{K: Number, N: a.N - b.N}
 	}
 	if a.K == Time && b.K == Duration {
-		return Value{K: Time, N: a.N - b.N}
+		return Value{K: Time, N: a.N - b.N, V: a.V}
 	}
 	return Value{K: Invalid}
 }
@@ -223,12 +396,14 @@ func (a Value) Div(b Value) Value {
 
 // Deep equality
 func (a Value) Equal(b Value) bool {
+	a, b = a.Force(), b.Force()
 	if a.K != b.K {
 		return false
 	}
 	switch a.K {
 	case Number, Bool, Time, Duration:
-		return a.N == b.N
+		// NaN equals NaN so that Values behave as set members and map keys.
+		return a.N == b.N || a.N != a.N && b.N != b.N
 	case String:
 		return a.String() == b.String()
 	case Nil:
@@ -247,7 +422,7 @@ func (a Value) Equal(b Value) bool {
 		}
 		return true
 	case Map:
-		x, y := a.V.(map[string]Value), b.V.(map[string]Value)
+		x, y := a.mapping(), b.mapping()
 		if len(x) != len(y) {
 			return false
 		}
@@ -259,13 +434,34 @@ func (a Value) Equal(b Value) bool {
 		}
 		return true
 	default:
-		return a.V == b.V
+		return goEqual(a.V, b.V)
+	}
+}
+
+// goEqual compares the Go values behind Struct, Func and Any kinds without
+// panicking on uncomparable ones: funcs compare by identity, and values
+// holding slices, maps or funcs compare deeply.
+func goEqual(x, y any) bool {
+	if reflect.TypeOf(x) != reflect.TypeOf(y) {
+		return false
+	}
+	rx, ry := reflect.ValueOf(x), reflect.ValueOf(y)
+	switch {
+	case !rx.IsValid():
+		return true
+	case rx.Kind() == reflect.Func:
+		return rx.Pointer() == ry.Pointer()
+	case !rx.Comparable() || !ry.Comparable():
+		return reflect.DeepEqual(x, y)
 	}
+	return x == y
 }
 
+// Less orders scalars by N and Strings lexically. NaN sorts before every
+// other number, as in sort.Float64s.
 func (a Value) Less(b Value) bool {
 	if a.K <= Duration && b.K <= Duration {
-		return a.N < b.N
+		return a.N < b.N || a.N != a.N && b.N == b.N
 	}
 	if a.K == String && b.K == String {
 		return a.String() < b.String()
@@ -278,6 +474,66 @@ func (a Value) Greater(b Value) bool      { return b.Less(a) }
 func (a Value) LessEqual(b Value) bool    { return !b.Less(a) }
 func (a Value) GreaterEqual(b Value) bool { return !a.Less(b) }
 
+// kindRank positions each Kind in the total order used by Compare.
+var kindRank = [...]uint8{
+	Invalid: 0, Nil: 1, Bool: 2, Number: 3, Time: 4, Duration: 5, String: 6,
+	Bytes: 7, Array: 8, Map: 9, Struct: 10, Func: 11, Any: 12, Error: 13,
+}
+
+// Compare returns -1, 0 or +1 under a total order over all Values, usable
+// for sorting heterogeneous Arrays and as the key order of sorted
+// containers. Kinds order as Invalid < Nil < Bool < Number < Time <
+// Duration < String < Bytes < Array < Map < Struct < Func < Any < Error.
+// Within a kind, scalars compare by value (NaN first, -0 equal to 0),
+// Strings and Bytes lexically, Arrays element by element and Maps entry by
+// entry in sorted key order, shorter first on a common prefix. Go values
+// compare by type name and then their %v form. Compare is 0 exactly when
+// Equal holds, except for distinct Go values that print identically.
+func (a Value) Compare(b Value) int {
+	a, b = a.Force(), b.Force()
+	if a.K != b.K {
+		return cmp.Compare(kindRank[a.K], kindRank[b.K])
+	}
+	switch a.K {
+	case Invalid, Nil:
+		return 0
+	case Number, Bool, Time, Duration:
+		return cmp.Compare(a.N, b.N)
+	case String:
+		return strings.Compare(a.String(), b.String())
+	case Bytes:
+		return bytes.Compare(a.Bytes(), b.Bytes())
+	case Array:
+		x, y := a.V.([]Value), b.V.([]Value)
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := x[i].Compare(y[i]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(x), len(y))
+	case Map:
+		x, y := a.mapping(), b.mapping()
+		kx, ky := sortedKeys(x), sortedKeys(y)
+		for i := 0; i < len(kx) && i < len(ky); i++ {
+			if c := strings.Compare(kx[i], ky[i]); c != 0 {
+				return c
+			}
+			if c := x[kx[i]].Compare(y[ky[i]]); c != 0 {
+				return c
+			}
+		}
+		return cmp.Compare(len(kx), len(ky))
+	default:
+		if goEqual(a.V, b.V) {
+			return 0
+		}
+		if c := strings.Compare(fmt.Sprintf("%T", a.V), fmt.Sprintf("%T", b.V)); c != 0 {
+			return c
+		}
+		return strings.Compare(fmt.Sprint(a.V), fmt.Sprint(b.V))
+	}
+}
+
 /* =============================================================================
    5. NAVIGATION & REFLECTION
    ============================================================================= */
@@ -294,11 +550,18 @@ func (v Value) Len() int {
 	case Array:
 		return len(v.V.([]Value))
 	case Map:
+		if s, ok := v.V.(*sharded); ok {
+			return s.len()
+		}
 		return len(v.V.(map[string]Value))
+	case Func:
+		return v.Force().Len()
 	}
 	return 0
 }
 
+// Index returns the i-th element of an Array, byte of Bytes, or byte of a
+// String; use RuneAt and Slice to address a String by character.
 func (v Value) Index(i int) Value {
 	if !v.IsObject() {
 		return Value{K: Nil}
@@ -307,7 +570,7 @@ func (v Value) Index(i int) Value {
 	case Array:
 		a := v.V.([]Value)
 		if i >= 0 && i < len(a) {
-			return a[i]
+			return a[i].Force()
 		}
 	case Bytes:
 		b := v.V.([]byte)
@@ -319,6 +582,10 @@ func (v Value) Index(i int) Value {
 		if i >= 0 && i < len(s) {
 			return Value{K: String, V: string(s[i])}
 		}
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().Index(i)
+		}
 	}
 	return Value{K: Nil}
 }
@@ -329,11 +596,21 @@ func (v Value) Get(key string) Value {
 	}
 	switch v.K {
 	case Map:
+		if s, ok := v.V.(*sharded); ok {
+			if val, ok := s.get(key); ok {
+				return val.Force()
+			}
+			break
+		}
 		if val, ok := v.V.(map[string]Value)[key]; ok {
-			return val
+			return val.Force()
 		}
 	case Struct:
 		return v.reflect(key)
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().Get(key)
+		}
 	}
 	return Value{K: Nil}
 }
@@ -358,6 +635,9 @@ func (v Value) At(path ...any) Value {
 }
 
 func (v Value) reflect(key string) Value {
+	if m := loadMetrics(); m != nil {
+		m.ReflectGet(reflect.TypeOf(v.V), key)
+	}
 	rv := reflect.ValueOf(v.V)
 	for rv.Kind() == reflect.Ptr {
 		if rv.IsNil() {
@@ -368,8 +648,8 @@ func (v Value) reflect(key string) Value {
 	if rv.Kind() != reflect.Struct {
 		return Value{K: Nil}
 	}
-	f := rv.FieldByName(key)
-	if !f.IsValid() {
+	f, ok := structFieldByKey(rv, key)
+	if !ok {
 		return Value{K: Nil}
 	}
 	return New(f.Interface())
@@ -379,41 +659,85 @@ func (v Value) reflect(key string) Value {
    6. CONSTRUCTORS & NORMALIZATION
    ============================================================================= */
 
+// New converts a Go value into a Value, through Parse for types without a
+// fast path. A *Dict becomes the Map of its ToMap. A json.Number becomes a
+// Number; one holding an integer beyond ±2^53 keeps its digits, which Int,
+// Text and JSON reproduce exactly while arithmetic works on the rounded
+// float64. A json.RawMessage becomes a Lazy Value decoded on first access.
 func New(i any) Value {
+	if v, ok := newFast(i); ok {
+		return v
+	}
+	return Parse(i)
+}
+
+// newFast converts the types New handles without reflection.
+func newFast(i any) (Value, bool) {
 	if i == nil {
-		return Value{K: Nil}
+		return Value{K: Nil}, true
 	}
 
 	switch v := i.(type) {
 	case Value:
-		return v
+		return v, true
 	case string:
-		return Value{K: String, V: v}
+		return Value{K: String, V: v}, true
 	case []byte:
-		return Value{K: Bytes, V: v}
+		return Value{K: Bytes, V: v}, true
 	case bool:
 		if v {
-			return Value{K: Bool, N: 1}
+			return Value{K: Bool, N: 1}, true
 		}
-		return Value{K: Bool}
+		return Value{K: Bool}, true
 	case int:
-		return Value{K: Number, N: float64(v)}
+		return Value{K: Number, N: float64(v)}, true
 	case float64:
-		return Value{K: Number, N: v}
+		return Value{K: Number, N: v}, true
 	case time.Time:
-		return Value{K: Time, N: float64(v.UnixNano())}
+		return Value{K: Time, N: float64(v.UnixNano()), V: v.Location()}, true
 	case time.Duration:
-		return Value{K: Duration, N: float64(v.Nanoseconds())}
+		return Value{K: Duration, N: float64(v.Nanoseconds())}, true
 	case []Value:
-		return Value{K: Array, V: v}
+		return Value{K: Array, V: v}, true
 	case map[string]Value:
-		return Value{K: Map, V: v}
-	default:
-		return Parse(i)
+		return Value{K: Map, V: v}, true
+	case func(...Value) Value:
+		return Value{K: Func, V: v}, true
+	case *Dict:
+		if v == nil {
+			return Value{K: Nil}, true
+		}
+		return v.ToMap(), true
 	}
+	return Value{}, false
 }
 
+// Parse converts any Go value through reflection. New calls it for types
+// without a fast path; installed Metrics observe every call, but not the
+// nested elements it converts along the way.
 func Parse(i any) Value {
+	var allocs int
+	if m := loadMetrics(); m != nil {
+		start := time.Now()
+		v := parse(i, &allocs)
+		m.Parse(reflect.TypeOf(i), allocs, time.Since(start))
+		return v
+	}
+	return parse(i, &allocs)
+}
+
+// parseNested is New for the elements of a value being parsed, so they
+// count towards the outer Parse call.
+func parseNested(i any, allocs *int) Value {
+	if v, ok := newFast(i); ok {
+		return v
+	}
+	return parse(i, allocs)
+}
+
+// parse does the work of Parse, adding the slices and maps it makes to
+// allocs.
+func parse(i any, allocs *int) Value {
 	rv := reflect.ValueOf(i)
 	for rv.Kind() == reflect.Ptr {
 		if rv.IsNil() {
@@ -422,20 +746,35 @@ func Parse(i any) Value {
 		rv = rv.Elem()
 	}
 
+	switch rv.Type() {
+	case jsonNumberType:
+		return fromJSONNumber(json.Number(rv.String()))
+	case rawMessageType:
+		return fromRawMessage(rv.Bytes())
+	}
+
 	switch rv.Kind() {
 	case reflect.Slice, reflect.Array:
 		if rv.Type().Elem().Kind() == reflect.Uint8 {
 			return Value{K: Bytes, V: rv.Bytes()}
 		}
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return Value{K: Array, V: []Value(nil)}
+		}
 		n := rv.Len()
 		out := make([]Value, n)
+		*allocs++
 		for i := 0; i < n; i++ {
-			out[i] = New(rv.Index(i).Interface())
+			out[i] = parseNested(rv.Index(i).Interface(), allocs)
 		}
 		return Value{K: Array, V: out}
 
 	case reflect.Map:
-		out := make(map[string]Value)
+		if rv.IsNil() {
+			return Value{K: Map, V: map[string]Value(nil)}
+		}
+		out := make(map[string]Value, rv.Len())
+		*allocs++
 		iter := rv.MapRange()
 		for iter.Next() {
 			var key string
@@ -446,13 +785,19 @@ func Parse(i any) Value {
 			} else {
 				key = fmt.Sprint(rk.Interface())
 			}
-			out[key] = New(iter.Value().Interface())
+			out[key] = parseNested(iter.Value().Interface(), allocs)
 		}
 		return Value{K: Map, V: out}
 
 	case reflect.Struct:
 		return Value{K: Struct, V: i}
 
+	case reflect.Func:
+		if rv.IsNil() {
+			return Value{K: Nil}
+		}
+		return Value{K: Func, V: i}
+
 	default:
 		if rv.CanFloat() {
 			return Value{K: Number, N: rv.Float()}
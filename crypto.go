@@ -0,0 +1,250 @@
+package kit
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+/* =============================================================================
+   CRYPTOGRAPHY
+   Digests and signatures are computed over CanonicalJSON, so Values that
+   mean the same thing hash alike in every process. The JSON form does not
+   record kinds: a Time and its RFC 3339 String, or Bytes and their base64
+   String, produce the same digest.
+   ============================================================================= */
+
+// Digest resets h, writes the canonical JSON of v to it and returns the sum
+// as Bytes. It returns Invalid when v has no canonical form, such as a NaN.
+func (v Value) Digest(h hash.Hash) Value {
+	data, err := v.CanonicalJSON()
+	if err != nil {
+		return Value{K: Invalid}
+	}
+	h.Reset()
+	h.Write(data)
+	return Value{K: Bytes, V: h.Sum(nil)}
+}
+
+// SHA256 returns the SHA-256 Digest of v, for content addressing and change
+// detection.
+func (v Value) SHA256() Value {
+	return v.Digest(sha256.New())
+}
+
+// Sign returns the HMAC-SHA256 of the canonical JSON of v under key, as
+// Bytes. It fails only when v has no canonical form.
+func Sign(v Value, key []byte) (Value, error) {
+	data, err := v.CanonicalJSON()
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return Value{K: Bytes, V: mac.Sum(nil)}, nil
+}
+
+// Verify reports whether sig is the Sign signature of v under key, in
+// constant time. sig may be Bytes, or a String in hex or base64 (standard
+// or URL alphabet, padded or not) as webhook headers carry it.
+func Verify(v Value, key []byte, sig Value) bool {
+	want, err := Sign(v, key)
+	if err != nil {
+		return false
+	}
+	got, ok := signatureBytes(sig.Force())
+	return ok && hmac.Equal(got, want.Bytes())
+}
+
+func signatureBytes(sig Value) ([]byte, bool) {
+	switch sig.K {
+	case Bytes:
+		return sig.Bytes(), true
+	case String:
+		s := sig.String()
+		if b, err := hex.DecodeString(s); err == nil {
+			return b, true
+		}
+		for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+			if b, err := enc.DecodeString(s); err == nil {
+				return b, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// EqualConstantTime compares the contents of two String or Bytes Values in
+// time that depends only on their lengths, for tokens and signatures. A
+// String and Bytes with the same content are equal; any other kind is
+// never equal.
+func (v Value) EqualConstantTime(other Value) bool {
+	v, other = v.Force(), other.Force()
+	if v.K != String && v.K != Bytes || other.K != String && other.K != Bytes {
+		return false
+	}
+	return subtle.ConstantTimeCompare(v.ByteSlice(), other.ByteSlice()) == 1
+}
+
+// Cipher seals and opens the content of Bytes Values. AESGCM is the
+// built-in implementation; schemes such as age or NaCl box plug in by
+// implementing Cipher.
+type Cipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(ciphertext []byte) ([]byte, error)
+}
+
+// KeyProvider returns the key with the given id, so that keys can rotate:
+// ciphertexts name the key that sealed them.
+type KeyProvider func(id string) ([]byte, error)
+
+// StaticKey provides a single key under every id.
+func StaticKey(key []byte) KeyProvider {
+	return func(string) ([]byte, error) { return key, nil }
+}
+
+// ErrDecrypt reports a ciphertext that is malformed, was sealed under a
+// different key, or has been tampered with.
+var ErrDecrypt = errors.New("kit: message authentication failed")
+
+const aesGCMVersion = 1
+
+type aesGCM struct {
+	keys    KeyProvider
+	current string
+}
+
+// AESGCM returns a Cipher using AES-GCM with keys of 16, 24 or 32 bytes.
+// It seals under the key named current and opens with whichever key a
+// ciphertext names. Each ciphertext carries a random nonce, so sealing the
+// same plaintext twice gives different results.
+func AESGCM(keys KeyProvider, current string) Cipher {
+	return &aesGCM{keys: keys, current: current}
+}
+
+func (c *aesGCM) aead(id string) (cipher.AEAD, error) {
+	key, err := c.keys(id)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Seal lays out version, key id length, key id, nonce and sealed data.
+func (c *aesGCM) Seal(plaintext []byte) ([]byte, error) {
+	if len(c.current) > 255 {
+		return nil, fmt.Errorf("kit: key id longer than 255 bytes")
+	}
+	aead, err := c.aead(c.current)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte{aesGCMVersion, byte(len(c.current))}, c.current...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out = append(out, nonce...)
+	// The header is authenticated, so the key id cannot be swapped.
+	return aead.Seal(out, nonce, plaintext, out[:2+len(c.current)]), nil
+}
+
+func (c *aesGCM) Open(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < 2 || ciphertext[0] != aesGCMVersion || len(ciphertext) < 2+int(ciphertext[1]) {
+		return nil, ErrDecrypt
+	}
+	header := ciphertext[:2+int(ciphertext[1])]
+	aead, err := c.aead(string(header[2:]))
+	if err != nil {
+		return nil, err
+	}
+	rest := ciphertext[len(header):]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrDecrypt
+	}
+	out, err := aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return out, nil
+}
+
+// Encrypt seals the content of a String or Bytes Value with c and returns
+// the ciphertext as Bytes, to be stored in place of the plaintext.
+func (v Value) Encrypt(c Cipher) (Value, error) {
+	v = v.Force()
+	if v.K != String && v.K != Bytes {
+		return Value{K: Invalid}, fmt.Errorf("kit: cannot encrypt %s", v.K)
+	}
+	out, err := c.Seal(v.ByteSlice())
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	return Value{K: Bytes, V: out}, nil
+}
+
+// Decrypt opens a Bytes Value sealed by Encrypt and returns the plaintext
+// as Bytes; use Text for what was a String.
+func (v Value) Decrypt(c Cipher) (Value, error) {
+	v = v.Force()
+	if v.K != Bytes {
+		return Value{K: Invalid}, fmt.Errorf("kit: cannot decrypt %s", v.K)
+	}
+	out, err := c.Open(v.Bytes())
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	return Value{K: Bytes, V: out}, nil
+}
+
+// Wipe zeroes every Bytes held in v and empties its Arrays and Maps in
+// place, for credentials that should not outlive their use:
+//
+//	token := kit.New(readToken()) // a []byte
+//	defer token.Wipe()
+//
+// Go strings are immutable and may sit in read-only memory, so a String
+// cannot be zeroed; Wipe only drops the tree's reference to it. Keep
+// secrets as Bytes and convert to a string, if at all, as late as possible.
+//
+// Wipe writes through shared memory: every Value that shares the wiped
+// slices and containers, including copies made by Set, sees them emptied.
+// Lazy Values that were never forced are left alone.
+func (v Value) Wipe() {
+	switch x := v.V.(type) {
+	case []byte:
+		clear(x)
+	case []Value:
+		for _, e := range x {
+			e.Wipe()
+		}
+		clear(x)
+	case map[string]Value:
+		for _, e := range x {
+			e.Wipe()
+		}
+		clear(x)
+	case *sharded:
+		for i := range x.shards {
+			sh := &x.shards[i]
+			sh.mu.Lock()
+			for _, e := range sh.m {
+				e.Wipe()
+			}
+			sh.m, sh.shared = map[string]Value{}, false
+			sh.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package kit
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestValue_SHA256(t *testing.T) {
+	a := New(map[string]any{"b": 1, "a": []any{"x", -0.0}})
+	b := New(map[string]any{"a": []any{"x", 0}, "b": 1.0})
+
+	sum := a.SHA256()
+	if sum.K != Bytes || sum.Len() != sha256.Size {
+		t.Fatalf("SHA256 = %v", sum)
+	}
+	if !sum.Equal(b.SHA256()) {
+		t.Error("equivalent Values hash differently")
+	}
+	want := sha256.Sum256([]byte(`{"a":["x",0],"b":1}`))
+	if got := hex.EncodeToString(sum.Bytes()); got != hex.EncodeToString(want[:]) {
+		t.Errorf("SHA256 = %s", got)
+	}
+	if a.SHA256().Equal(New(map[string]any{"b": 2}).SHA256()) {
+		t.Error("different Values hash alike")
+	}
+	if New(math.NaN()).SHA256().K != Invalid {
+		t.Error("NaN has a digest")
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	key := []byte("secret")
+	payload := New(map[string]any{"event": "paid", "amount": 42})
+
+	sig, err := Sign(payload, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(`{"amount":42,"event":"paid"}`))
+	if !bytes.Equal(sig.Bytes(), mac.Sum(nil)) {
+		t.Errorf("Sign = %x", sig.Bytes())
+	}
+
+	for name, s := range map[string]Value{
+		"bytes":  sig,
+		"hex":    New(hex.EncodeToString(sig.Bytes())),
+		"base64": New(base64.StdEncoding.EncodeToString(sig.Bytes())),
+		"raw":    New(base64.RawURLEncoding.EncodeToString(sig.Bytes())),
+	} {
+		if !Verify(payload, key, s) {
+			t.Errorf("Verify rejected the %s signature", name)
+		}
+	}
+
+	if Verify(payload.Set("amount", 43), key, sig) || Verify(payload, []byte("other"), sig) {
+		t.Error("Verify accepted a forged payload or key")
+	}
+	if Verify(payload, key, New("not a signature!")) || Verify(payload, key, New(1)) {
+		t.Error("Verify accepted a malformed signature")
+	}
+	if _, err := Sign(New(math.Inf(1)), key); err == nil {
+		t.Error("Sign of +Inf succeeded")
+	}
+}
+
+func TestValue_EqualConstantTime(t *testing.T) {
+	tests := []struct {
+		a, b Value
+		want bool
+	}{
+		{New("tok_123"), New("tok_123"), true},
+		{New("tok_123"), New([]byte("tok_123")), true},
+		{New("tok_123"), New("tok_124"), false},
+		{New("tok"), New("tok_123"), false},
+		{New(""), New([]byte{}), true},
+		{New(1), New(1), false},
+		{New("1"), New(1), false},
+	}
+	for _, tt := range tests {
+		if got := tt.a.EqualConstantTime(tt.b); got != tt.want {
+			t.Errorf("%v.EqualConstantTime(%v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValue_EncryptDecrypt(t *testing.T) {
+	keys := map[string][]byte{
+		"2024": bytes.Repeat([]byte{1}, 32),
+		"2025": bytes.Repeat([]byte{2}, 16),
+	}
+	provider := func(id string) ([]byte, error) {
+		if k, ok := keys[id]; ok {
+			return k, nil
+		}
+		return nil, fmt.Errorf("no key %q", id)
+	}
+	old, cur := AESGCM(provider, "2024"), AESGCM(provider, "2025")
+
+	doc := New(map[string]any{"name": "ann", "ssn": "123-45-6789"})
+	sealed, err := doc.Get("ssn").Encrypt(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc = doc.Set("ssn", sealed)
+	if bytes.Contains(must(doc.MarshalJSON()), []byte("6789")) {
+		t.Error("plaintext visible in the sealed document")
+	}
+
+	// A Cipher sealing under a newer key still opens older ciphertexts.
+	plain, err := doc.Get("ssn").Decrypt(cur)
+	if err != nil || plain.Text() != "123-45-6789" {
+		t.Fatalf("Decrypt = %v, %v", plain, err)
+	}
+
+	again, _ := New("123-45-6789").Encrypt(old)
+	if again.Equal(sealed) {
+		t.Error("sealing twice gave identical ciphertexts")
+	}
+
+	tampered := append([]byte(nil), sealed.Bytes()...)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := New(tampered).Decrypt(cur); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("tampered ciphertext: %v", err)
+	}
+	if _, err := New([]byte{1}).Decrypt(cur); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("short ciphertext: %v", err)
+	}
+	if _, err := sealed.Decrypt(AESGCM(StaticKey(bytes.Repeat([]byte{9}, 32)), "x")); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("wrong key: %v", err)
+	}
+	if _, err := New(1).Encrypt(cur); err == nil {
+		t.Error("encrypted a Number")
+	}
+	if _, err := New("x").Encrypt(AESGCM(provider, "missing")); err == nil {
+		t.Error("sealed with a missing key")
+	}
+}
+
+func TestValue_Wipe(t *testing.T) {
+	secret := []byte("hunter2")
+	nested := []byte("s3cr3t")
+	v := New(map[string]any{
+		"password": secret,
+		"list":     []any{nested, "token"},
+		"name":     "ann",
+	})
+	list := v.Get("list")
+	sharded := NewMap(Shards(4)).Set("key", []byte("k"))
+	shardedBytes := sharded.Get("key").Bytes()
+
+	v.Wipe()
+	sharded.Wipe()
+
+	if !bytes.Equal(secret, make([]byte, len(secret))) || !bytes.Equal(nested, make([]byte, len(nested))) {
+		t.Errorf("bytes not zeroed: %q %q", secret, nested)
+	}
+	if v.Len() != 0 || list.Index(1).K != Invalid {
+		t.Errorf("containers not emptied: %v %v", v, list)
+	}
+	if shardedBytes[0] != 0 || sharded.Len() != 0 {
+		t.Errorf("sharded Map not wiped: %v", sharded)
+	}
+	New("literal").Wipe() // must not fault on read-only string data
+}
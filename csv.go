@@ -0,0 +1,82 @@
+package kit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+type csvOptions struct {
+	types bool
+}
+
+// CSVOption configures FromCSV.
+type CSVOption func(*csvOptions)
+
+// CSVTypes types fields with FromText, so numbers and booleans come out as
+// such. Fields that look numeric but would not survive as a Number stay
+// Strings: those with leading zeros or a "+" sign, such as zip codes and
+// phone numbers, and integers beyond ±2^53.
+func CSVTypes() CSVOption {
+	return func(o *csvOptions) { o.types = true }
+}
+
+// FromCSV reads CSV records into an Array of Maps keyed by the header row.
+// Fields are Strings unless CSVTypes is given; rows shorter than the header
+// leave the missing keys out, and longer rows are an error.
+func FromCSV(r io.Reader, opts ...CSVOption) (Value, error) {
+	var o csvOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return Value{K: Array, V: []Value{}}, nil
+	}
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	header = append([]string(nil), header...)
+
+	out := []Value{}
+	for {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return Value{K: Array, V: out}, nil
+		}
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		if len(rec) > len(header) {
+			line, _ := cr.FieldPos(0)
+			return Value{K: Invalid}, fmt.Errorf("kit: csv line %d: %d fields, header has %d", line, len(rec), len(header))
+		}
+		row := make(map[string]Value, len(rec))
+		for i, f := range rec {
+			if o.types {
+				row[header[i]] = csvField(f)
+			} else {
+				row[header[i]] = Value{K: String, V: f}
+			}
+		}
+		out = append(out, Value{K: Map, V: row})
+	}
+}
+
+// csvField types f with FromText unless it is an identifier that merely
+// looks numeric.
+func csvField(f string) Value {
+	digits := strings.TrimPrefix(f, "-")
+	if strings.HasPrefix(f, "+") || len(digits) > 1 && digits[0] == '0' && digits[1] >= '0' && digits[1] <= '9' {
+		return Value{K: String, V: f}
+	}
+	v := FromText(f)
+	if v.K == Number && math.Abs(v.N) > maxSafeInt && !strings.ContainsAny(f, ".eE") {
+		return Value{K: String, V: f}
+	}
+	return v
+}
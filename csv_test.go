@@ -0,0 +1,39 @@
+package kit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromCSV(t *testing.T) {
+	v, err := FromCSV(strings.NewReader("id,name,ok\n1,ann,true\n2,\"b, c\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Len() != 2 {
+		t.Fatalf("rows = %d", v.Len())
+	}
+	if v.Path("0.id").K != String || v.Path("0.ok").String() != "true" || v.Path("1.name").Text() != "b, c" {
+		t.Errorf("rows = %v", v)
+	}
+	if v.Exists("1.ok") {
+		t.Error("short row has a value for ok")
+	}
+
+	typed, err := FromCSV(strings.NewReader("id,ok,zip,phone,big,neg,ratio\n1,true,01234,+84901234567,9007199254740993,-0.5,1e3\n"), CSVTypes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := New(map[string]any{"id": 1, "ok": true, "zip": "01234", "phone": "+84901234567",
+		"big": "9007199254740993", "neg": -0.5, "ratio": 1000})
+	if got := typed.Index(0); !got.Equal(want) {
+		t.Errorf("typed row = %s, want %s", got, want)
+	}
+
+	if _, err := FromCSV(strings.NewReader("a\n1,2\n")); err == nil {
+		t.Error("long row accepted")
+	}
+	if v, err := FromCSV(strings.NewReader("")); err != nil || v.Len() != 0 {
+		t.Errorf("empty input = %v, %v", v, err)
+	}
+}
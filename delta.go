@@ -0,0 +1,179 @@
+package kit
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   DELTA ENCODING
+   A compact binary patch from one Value tree to another, for syncing large
+   trees that change a little at a time. It walks the trees as Diff does
+   and encodes the edits with MessagePack:
+
+	version byte, 8-byte FNV-1a of the base's MessagePack, then an Array
+	of [op, path, arg] edits, path being an Array of key or index Strings
+
+   Arrays that only grow or shrink at the end are sent as an append or a
+   truncation instead of one edit per element.
+   ============================================================================= */
+
+const deltaVersion = 1
+
+const (
+	deltaSet      = iota // arg replaces the Value at path
+	deltaDelete          // the Map key at path is removed
+	deltaTruncate        // the Array at path is cut to arg elements
+	deltaAppend          // the elements of arg are appended to the Array at path
+)
+
+// ErrDeltaBase is returned by ApplyDelta when the delta was made from a
+// different tree than the one it is applied to.
+var ErrDeltaBase = errors.New("kit: delta does not apply to this base")
+
+// Delta returns a binary delta that turns old into new with ApplyDelta.
+// It fails when either tree holds a Value MessagePack cannot encode.
+func Delta(old, new Value) ([]byte, error) {
+	base, err := deltaChecksum(old)
+	if err != nil {
+		return nil, err
+	}
+	edits := []Value{}
+	deltaInto(&edits, nil, old.Force(), new.Force())
+
+	out := binary.BigEndian.AppendUint64([]byte{deltaVersion}, base)
+	return Value{K: Array, V: edits}.AppendMsgPack(out)
+}
+
+func deltaChecksum(v Value) (uint64, error) {
+	data, err := v.AppendMsgPack(nil)
+	if err != nil {
+		return 0, err
+	}
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64(), nil
+}
+
+func deltaEdit(op int, path []string, arg Value) Value {
+	segs := make([]Value, len(path))
+	for i, s := range path {
+		segs[i] = Value{K: String, V: s}
+	}
+	e := []Value{{K: Number, N: float64(op)}, {K: Array, V: segs}}
+	if arg.K != Invalid {
+		e = append(e, arg)
+	}
+	return Value{K: Array, V: e}
+}
+
+func deltaInto(edits *[]Value, path []string, a, b Value) {
+	// Clip so that appends for siblings never share a backing array.
+	path = path[:len(path):len(path)]
+	switch {
+	case a.K == Map && b.K == Map:
+		x, y := a.mapping(), b.mapping()
+		for _, k := range sortedKeys(x) {
+			if _, ok := y[k]; !ok {
+				*edits = append(*edits, deltaEdit(deltaDelete, append(path, k), Value{K: Invalid}))
+			}
+		}
+		for _, k := range sortedKeys(y) {
+			if xv, ok := x[k]; ok {
+				deltaInto(edits, append(path, k), xv.Force(), y[k].Force())
+			} else {
+				*edits = append(*edits, deltaEdit(deltaSet, append(path, k), y[k].Force()))
+			}
+		}
+	case a.K == Array && b.K == Array:
+		x, y := a.V.([]Value), b.V.([]Value)
+		for i := 0; i < min(len(x), len(y)); i++ {
+			deltaInto(edits, append(path, strconv.Itoa(i)), x[i].Force(), y[i].Force())
+		}
+		switch {
+		case len(y) < len(x):
+			*edits = append(*edits, deltaEdit(deltaTruncate, path, Value{K: Number, N: float64(len(y))}))
+		case len(y) > len(x):
+			*edits = append(*edits, deltaEdit(deltaAppend, path, Value{K: Array, V: y[len(x):]}))
+		}
+	case !a.Equal(b):
+		*edits = append(*edits, deltaEdit(deltaSet, path, b))
+	}
+}
+
+// ApplyDelta applies a delta from Delta to base and returns the new tree;
+// base is not modified. It returns ErrDeltaBase when base is not the tree
+// the delta was made from.
+func ApplyDelta(base Value, delta []byte) (Value, error) {
+	if len(delta) < 9 || delta[0] != deltaVersion {
+		return Value{K: Invalid}, errors.New("kit: not a delta")
+	}
+	sum, err := deltaChecksum(base)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	if sum != binary.BigEndian.Uint64(delta[1:9]) {
+		return Value{K: Invalid}, ErrDeltaBase
+	}
+	edits, err := DecodeMsgPack(delta[9:])
+	if err != nil {
+		return Value{K: Invalid}, fmt.Errorf("kit: corrupt delta: %w", err)
+	}
+	if edits.K != Array {
+		return Value{K: Invalid}, errors.New("kit: corrupt delta")
+	}
+
+	// A live sharded root would be written in place; edit a frozen view.
+	root := base.Snapshot()
+	for _, e := range edits.V.([]Value) {
+		if root, err = applyEdit(root, e); err != nil {
+			return Value{K: Invalid}, err
+		}
+	}
+	return root, nil
+}
+
+func applyEdit(root, e Value) (Value, error) {
+	if e.K != Array || e.Len() < 2 || e.Index(1).K != Array {
+		return root, errors.New("kit: corrupt delta edit")
+	}
+	var path []string
+	for _, s := range e.Index(1).V.([]Value) {
+		path = append(path, s.Text())
+	}
+	arg := e.Index(2)
+	switch e.Index(0).Int() {
+	case deltaSet:
+		return root.setIn(path, arg)
+	case deltaDelete:
+		return root.deleteIn(path)
+	case deltaTruncate, deltaAppend:
+		cur := root.Force()
+		for _, seg := range path {
+			cur = cur.step(seg).Force()
+		}
+		if cur.K != Array {
+			return root, fmt.Errorf("kit: delta resizes %s at %q", cur.K, strings.Join(path, "."))
+		}
+		a := cur.V.([]Value)
+		var out []Value
+		if e.Index(0).Int() == deltaTruncate {
+			n := int(arg.Int())
+			if n < 0 || n > len(a) {
+				return root, errors.New("kit: corrupt delta edit")
+			}
+			out = a[:n:n]
+		} else {
+			if arg.K != Array {
+				return root, errors.New("kit: corrupt delta edit")
+			}
+			out = append(append(make([]Value, 0, len(a)+arg.Len()), a...), arg.V.([]Value)...)
+		}
+		return root.setIn(path, Value{K: Array, V: out})
+	}
+	return root, errors.New("kit: corrupt delta edit")
+}
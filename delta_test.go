@@ -0,0 +1,93 @@
+package kit
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDelta(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := New(map[string]any{
+		"server":  map[string]any{"port": 8080, "hosts": []any{"a", "b", "c"}},
+		"debug":   false,
+		"a.b":     1,
+		"list":    []any{1},
+		"matrix":  []any{[]any{1, 2}, []any{3}},
+		"updated": at,
+	})
+	b := New(map[string]any{
+		"server":  map[string]any{"port": 9090, "hosts": []any{"a", "x"}, "tls": true},
+		"a.b":     2,
+		"list":    []any{1, 2, map[string]any{"k": "v"}},
+		"matrix":  []any{[]any{1}, []any{3, 4}},
+		"updated": at.Add(time.Hour),
+	})
+
+	cases := []struct {
+		name     string
+		old, new Value
+	}{
+		{"tree", a, b},
+		{"reverse", b, a},
+		{"same", a, a},
+		{"root kind", a, New([]any{1, 2})},
+		{"from nil", Value{K: Nil}, b},
+	}
+	for _, c := range cases {
+		d, err := Delta(c.old, c.new)
+		if err != nil {
+			t.Fatalf("%s: Delta: %v", c.name, err)
+		}
+		got, err := ApplyDelta(c.old, d)
+		if err != nil {
+			t.Fatalf("%s: ApplyDelta: %v", c.name, err)
+		}
+		if !got.Equal(c.new) {
+			t.Errorf("%s: ApplyDelta = %s, want %s", c.name, got, c.new)
+		}
+	}
+	if got, _ := ApplyDelta(a, mustDelta(t, a, b)); got.Path("updated").K != Time {
+		t.Errorf("updated kind = %s, want Time", got.Path("updated").K)
+	}
+	if a.Path("server.port").Int() != 8080 || a.Path("server.hosts").Len() != 3 {
+		t.Errorf("ApplyDelta modified its base: %s", a)
+	}
+
+	if _, err := ApplyDelta(b, mustDelta(t, a, b)); !errors.Is(err, ErrDeltaBase) {
+		t.Errorf("wrong base: err = %v, want ErrDeltaBase", err)
+	}
+	for _, bad := range [][]byte{nil, []byte("kit"), append(mustDelta(t, a, b)[:9], 0xc1)} {
+		if _, err := ApplyDelta(a, bad); err == nil {
+			t.Errorf("ApplyDelta(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestDeltaSize(t *testing.T) {
+	rows := make([]any, 1000)
+	for i := range rows {
+		rows[i] = map[string]any{"id": i, "name": "user " + strconv.Itoa(i), "active": true}
+	}
+	old := New(map[string]any{"users": rows})
+	new := old.Set("users", old.Get("users").Set("500", New(map[string]any{"id": 500, "name": "renamed", "active": true})))
+
+	d := mustDelta(t, old, new)
+	full, err := new.AppendMsgPack(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d) > 40 {
+		t.Errorf("delta of one field is %d bytes (full tree %d)", len(d), len(full))
+	}
+}
+
+func mustDelta(t *testing.T, old, new Value) []byte {
+	t.Helper()
+	d, err := Delta(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
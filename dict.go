@@ -0,0 +1,174 @@
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+/* =============================================================================
+   DICT
+   Maps keyed by any Value. New turns Go map keys into Strings, which loses
+   what an int, time or tuple key was and their order; a Dict keeps keys as
+   Values, equal as Equal says, so 1 and 1.0 are one key while 1 and "1"
+   are two. The Value model itself has no ordered or non-String keyed Map:
+   New(d) is the Map of d.ToMap, which Get, Path and the encoders can read
+   but which has neither.
+   ============================================================================= */
+
+// Dict is an insertion-ordered map from Values to Values. Its keys and
+// order are only seen through its own methods. Like a Go map it is not
+// safe for concurrent writes. Keys must not be modified once set.
+type Dict struct {
+	index   map[uint64][]int // key hash to positions in entries
+	entries []dictEntry
+	dead    int
+}
+
+type dictEntry struct {
+	key, val Value
+	live     bool
+}
+
+// NewDict returns an empty Dict.
+func NewDict() *Dict {
+	return &Dict{index: map[uint64][]int{}}
+}
+
+// DictOf copies a Go map of any key type into a Dict, converting keys and
+// values with New, so a time.Time key stays a Time. Map Values are copied
+// with their String keys. Entries are ordered by Compare of their keys.
+func DictOf(m any) (*Dict, error) {
+	d := NewDict()
+	if v, ok := m.(Value); ok && v.Force().K == Map {
+		for k, e := range v.Force().mapping() {
+			d.Set(k, e)
+		}
+	} else {
+		rv := reflect.ValueOf(m)
+		if rv.Kind() != reflect.Map {
+			return nil, fmt.Errorf("kit: DictOf needs a map, got %T", m)
+		}
+		for it := rv.MapRange(); it.Next(); {
+			d.Set(it.Key().Interface(), it.Value().Interface())
+		}
+	}
+	slices.SortFunc(d.entries, func(a, b dictEntry) int { return a.key.Compare(b.key) })
+	d.reindex()
+	return d, nil
+}
+
+// Len returns the number of entries.
+func (d *Dict) Len() int { return len(d.entries) - d.dead }
+
+// find returns the position of key in entries, or -1.
+func (d *Dict) find(key Value, h uint64) int {
+	for _, i := range d.index[h] {
+		if d.entries[i].key.Equal(key) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Get returns the Value stored under key, converted with New, and whether
+// it was present.
+func (d *Dict) Get(key any) (Value, bool) {
+	k := New(key).Force()
+	if i := d.find(k, k.hash()); i >= 0 {
+		return d.entries[i].val, true
+	}
+	return Value{K: Nil}, false
+}
+
+// Set stores val under key, both converted with New. A new key goes last;
+// an existing one keeps its place.
+func (d *Dict) Set(key, val any) {
+	k := New(key).Force()
+	h := k.hash()
+	if i := d.find(k, h); i >= 0 {
+		d.entries[i].val = New(val)
+		return
+	}
+	d.index[h] = append(d.index[h], len(d.entries))
+	d.entries = append(d.entries, dictEntry{key: k, val: New(val), live: true})
+}
+
+// Delete removes key and reports whether it was present.
+func (d *Dict) Delete(key any) bool {
+	k := New(key).Force()
+	h := k.hash()
+	i := d.find(k, h)
+	if i < 0 {
+		return false
+	}
+	d.index[h] = slices.DeleteFunc(d.index[h], func(j int) bool { return j == i })
+	if len(d.index[h]) == 0 {
+		delete(d.index, h)
+	}
+	d.entries[i] = dictEntry{}
+	// Compact once tombstones outnumber the live entries.
+	if d.dead++; d.dead > len(d.entries)/2 {
+		d.entries = slices.DeleteFunc(d.entries, func(e dictEntry) bool { return !e.live })
+		d.dead = 0
+		d.reindex()
+	}
+	return true
+}
+
+func (d *Dict) reindex() {
+	clear(d.index)
+	for i, e := range d.entries {
+		if e.live {
+			h := e.key.hash()
+			d.index[h] = append(d.index[h], i)
+		}
+	}
+}
+
+// Range calls fn for each entry in order until fn returns false. fn must
+// not modify d.
+func (d *Dict) Range(fn func(key, val Value) bool) {
+	for _, e := range d.entries {
+		if e.live && !fn(e.key, e.val) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys as an Array, in order.
+func (d *Dict) Keys() Value {
+	out := make([]Value, 0, d.Len())
+	d.Range(func(k, _ Value) bool {
+		out = append(out, k)
+		return true
+	})
+	return Value{K: Array, V: out}
+}
+
+// Values returns the values as an Array, in order.
+func (d *Dict) Values() Value {
+	out := make([]Value, 0, d.Len())
+	d.Range(func(_, v Value) bool {
+		out = append(out, v)
+		return true
+	})
+	return Value{K: Array, V: out}
+}
+
+// ToMap returns a Map keyed by the Text of each key, the lossy form JSON
+// and templates need and the one New converts a Dict to. Of keys with the
+// same Text, the last one wins.
+func (d *Dict) ToMap() Value {
+	out := make(map[string]Value, d.Len())
+	d.Range(func(k, v Value) bool {
+		out[k.Text()] = v
+		return true
+	})
+	return Value{K: Map, V: out}
+}
+
+// MarshalJSON implements json.Marshaler with the object of ToMap.
+func (d *Dict) MarshalJSON() ([]byte, error) {
+	return d.ToMap().AppendJSON(nil)
+}
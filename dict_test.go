@@ -0,0 +1,103 @@
+package kit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDict(t *testing.T) {
+	d := NewDict()
+	d.Set(1, "one")
+	d.Set("1", "string one")
+	d.Set([]any{1, "a"}, "tuple")
+	d.Set(1.0, "uno") // Equal to 1: replaces in place
+	if d.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", d.Len())
+	}
+	for _, c := range []struct {
+		key  any
+		want string
+	}{{1, "uno"}, {"1", "string one"}, {[]any{1.0, "a"}, "tuple"}} {
+		if got, ok := d.Get(c.key); !ok || got.String() != c.want {
+			t.Errorf("Get(%v) = %s, %v, want %q", c.key, got, ok, c.want)
+		}
+	}
+	if got, ok := d.Get(2); ok || got.K != Nil {
+		t.Errorf("Get(missing) = %s, %v", got, ok)
+	}
+	if got := d.Keys(); !got.Equal(New([]any{1, "1", []any{1, "a"}})) {
+		t.Errorf("Keys = %s", got)
+	}
+
+	if !d.Delete("1") || d.Delete("1") || d.Len() != 2 {
+		t.Error("Delete did not remove exactly once")
+	}
+	for i := 0; i < 10; i++ {
+		d.Set(i+10, i)
+		d.Delete(i + 10)
+	}
+	if got := d.Values(); !got.Equal(New([]any{"uno", "tuple"})) || len(d.entries) > 4 {
+		t.Errorf("after churn Values = %s with %d slots", got, len(d.entries))
+	}
+	// As a Value, a Dict is a plain Map keyed by Text.
+	byDay := NewDict()
+	byDay.Set(2, "b")
+	byDay.Set(1, "a")
+	doc := New(map[string]any{"dict": d, "days": byDay})
+	if doc.Path("dict.1").String() != "uno" || doc.Get("dict").K != Map {
+		t.Errorf("New(Dict) = %s, want a Map", doc.Get("dict"))
+	}
+	if b, err := doc.Get("days").MarshalJSON(); err != nil || string(b) != `{"1":"a","2":"b"}` {
+		t.Errorf("MarshalJSON = %s, %v", b, err)
+	}
+	if New((*Dict)(nil)).K != Nil {
+		t.Error("New(nil *Dict) is not Nil")
+	}
+	n := 0
+	d.Range(func(k, v Value) bool { n++; return false })
+	if n != 1 {
+		t.Errorf("Range visited %d entries after stopping", n)
+	}
+}
+
+func TestDictOf(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	d, err := DictOf(map[time.Time]int{day.AddDate(0, 0, 1): 2, day: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := d.Keys()
+	if k.Index(0).K != Time || !k.Index(0).Equal(New(day)) {
+		t.Errorf("Keys = %s, want Times in order", k)
+	}
+	if got, _ := d.Get(day); got.Int() != 1 {
+		t.Errorf("Get(day) = %s", got)
+	}
+
+	ints, _ := DictOf(map[int]string{10: "ten", 2: "two"})
+	data, err := json.Marshal(map[string]any{"d": ints})
+	if err != nil || string(data) != `{"d":{"10":"ten","2":"two"}}` {
+		t.Errorf("Marshal = %s, %v", data, err)
+	}
+	if got, _ := ints.Get(10); got.String() != "ten" {
+		t.Errorf("Get(10) = %s", got)
+	}
+
+	type pair struct{ A, B int }
+	pairs := map[pair]int{}
+	for i := 0; i < 1000; i++ {
+		pairs[pair{i, -i}] = i
+	}
+	byPair, _ := DictOf(pairs)
+	if got, _ := byPair.Get(pair{7, -7}); got.Int() != 7 || len(byPair.index) < 900 {
+		t.Errorf("Get(pair) = %s with %d hash buckets", got, len(byPair.index))
+	}
+
+	if m, _ := DictOf(New(map[string]any{"a": 1})); m.Len() != 1 {
+		t.Error("DictOf(Map Value) lost entries")
+	}
+	if _, err := DictOf([]int{1}); err == nil {
+		t.Error("DictOf(slice) succeeded")
+	}
+}
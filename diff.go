@@ -0,0 +1,215 @@
+package kit
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   DIFF
+   ============================================================================= */
+
+// ChangeOp is the kind of a Change.
+type ChangeOp uint8
+
+const (
+	Added ChangeOp = iota + 1
+	Removed
+	Changed
+)
+
+// Change is one difference between two Value trees. Path is dot-separated
+// in the syntax of Path, "" for the roots themselves. Old is Invalid for
+// Added and New is Invalid for Removed.
+type Change struct {
+	Op       ChangeOp
+	Path     string
+	Old, New Value
+}
+
+// Diff lists the differences from a to b in depth-first order, Map keys
+// sorted. Maps are compared key by key and Arrays index by index, so an
+// insertion shifts every later element; any other pair, or a change of
+// kind, is compared with Equal.
+func Diff(a, b Value) []Change {
+	var out []Change
+	diffInto(&out, "", a.Force(), b.Force())
+	return out
+}
+
+func diffInto(out *[]Change, path string, a, b Value) {
+	switch {
+	case a.K == Map && b.K == Map:
+		x, y := a.mapping(), b.mapping()
+		keys := sortedKeys(x)
+		for k := range y {
+			if _, ok := x[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			xv, inX := x[k]
+			yv, inY := y[k]
+			p := joinPath(path, k)
+			switch {
+			case !inY:
+				*out = append(*out, Change{Op: Removed, Path: p, Old: xv.Force(), New: Value{K: Invalid}})
+			case !inX:
+				*out = append(*out, Change{Op: Added, Path: p, Old: Value{K: Invalid}, New: yv.Force()})
+			default:
+				diffInto(out, p, xv.Force(), yv.Force())
+			}
+		}
+	case a.K == Array && b.K == Array:
+		x, y := a.V.([]Value), b.V.([]Value)
+		for i := 0; i < max(len(x), len(y)); i++ {
+			p := joinPath(path, strconv.Itoa(i))
+			switch {
+			case i >= len(y):
+				*out = append(*out, Change{Op: Removed, Path: p, Old: x[i].Force(), New: Value{K: Invalid}})
+			case i >= len(x):
+				*out = append(*out, Change{Op: Added, Path: p, Old: Value{K: Invalid}, New: y[i].Force()})
+			default:
+				diffInto(out, p, x[i].Force(), y[i].Force())
+			}
+		}
+	case !a.Equal(b):
+		*out = append(*out, Change{Op: Changed, Path: path, Old: a, New: b})
+	}
+}
+
+type diffOptions struct {
+	color bool
+}
+
+// DiffOption configures DiffString.
+type DiffOption func(*diffOptions)
+
+// DiffColor highlights removals in red and additions in green with ANSI
+// escapes, for terminals.
+func DiffColor() DiffOption {
+	return func(o *diffOptions) { o.color = true }
+}
+
+// DiffString reports the differences from a to b in a unified-diff style,
+// one "-" line for each old value and one "+" line for each new one:
+//
+//	- server.port: 8080
+//	+ server.port: 9090
+//	+ server.tls: true
+//
+// It returns "" when the trees are equal.
+func DiffString(a, b Value, opts ...DiffOption) string {
+	var o diffOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	var sb strings.Builder
+	line := func(sign byte, path string, v Value) {
+		if o.color {
+			if sign == '-' {
+				sb.WriteString("\x1b[31m")
+			} else {
+				sb.WriteString("\x1b[32m")
+			}
+		}
+		sb.WriteByte(sign)
+		sb.WriteByte(' ')
+		if path == "" {
+			path = "(root)"
+		}
+		sb.WriteString(path)
+		sb.WriteString(": ")
+		sb.WriteString(diffText(v))
+		if o.color {
+			sb.WriteString("\x1b[0m")
+		}
+		sb.WriteByte('\n')
+	}
+	for _, c := range Diff(a, b) {
+		if c.Op != Added {
+			line('-', c.Path, c.Old)
+		}
+		if c.Op != Removed {
+			line('+', c.Path, c.New)
+		}
+	}
+	return sb.String()
+}
+
+// diffText renders a Value on one line: Strings quoted, containers as JSON
+// where possible.
+func diffText(v Value) string {
+	switch v.K {
+	case String:
+		return strconv.Quote(v.String())
+	case Array, Map:
+		if data, err := v.AppendJSON(nil); err == nil {
+			return string(data)
+		}
+		return string(v.appendPlain(nil))
+	case Invalid:
+		return "!INVALID"
+	}
+	return string(v.appendPlain(nil))
+}
+
+// JSONPatch converts changes from Diff into an RFC 6902 JSON Patch: an
+// Array of {op, path, value} Maps with "add", "remove" and "replace" ops
+// and JSON Pointer paths. Removals of trailing Array elements are emitted
+// from the last index down, so the patch applies in sequence.
+func JSONPatch(changes []Change) Value {
+	ops := make([]Value, 0, len(changes))
+	for i := 0; i < len(changes); i++ {
+		c := changes[i]
+		if c.Op == Removed {
+			// Reverse each run of removals under one parent.
+			j := i
+			for j+1 < len(changes) && changes[j+1].Op == Removed && parentPath(changes[j+1].Path) == parentPath(c.Path) {
+				j++
+			}
+			for k := j; k >= i; k-- {
+				ops = append(ops, patchOp("remove", changes[k].Path, Value{K: Invalid}))
+			}
+			i = j
+			continue
+		}
+		op := "replace"
+		if c.Op == Added {
+			op = "add"
+		}
+		ops = append(ops, patchOp(op, c.Path, c.New))
+	}
+	return Value{K: Array, V: ops}
+}
+
+func patchOp(op, path string, v Value) Value {
+	m := map[string]Value{
+		"op":   {K: String, V: op},
+		"path": {K: String, V: jsonPointer(path)},
+	}
+	if v.K != Invalid {
+		m["value"] = v
+	}
+	return Value{K: Map, V: m}
+}
+
+// jsonPointer converts a dot-separated path to an RFC 6901 JSON Pointer.
+func jsonPointer(path string) string {
+	var sb strings.Builder
+	esc := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, seg := range splitPath(path) {
+		sb.WriteByte('/')
+		esc.WriteString(&sb, seg)
+	}
+	return sb.String()
+}
+
+func parentPath(path string) string {
+	if i := strings.LastIndexByte(path, '.'); i >= 0 {
+		return path[:i]
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package kit
+
+import "testing"
+
+func TestDiff(t *testing.T) {
+	a := New(map[string]any{
+		"server": map[string]any{"port": 8080, "hosts": []any{"a", "b"}},
+		"debug":  false,
+		"name":   "api",
+	})
+	b := New(map[string]any{
+		"server": map[string]any{"port": 9090, "hosts": []any{"a"}, "tls": true},
+		"name":   "api",
+		"tags":   []any{1},
+	})
+
+	changes := Diff(a, b)
+	want := []Change{
+		{Removed, "debug", New(false), Value{}},
+		{Removed, "server.hosts.1", New("b"), Value{}},
+		{Changed, "server.port", New(8080), New(9090)},
+		{Added, "server.tls", Value{}, New(true)},
+		{Added, "tags", Value{}, New([]any{1})},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff = %+v", changes)
+	}
+	for i, c := range changes {
+		w := want[i]
+		if c.Op != w.Op || c.Path != w.Path || !c.Old.Equal(w.Old) || !c.New.Equal(w.New) {
+			t.Errorf("change %d = %+v, want %+v", i, c, w)
+		}
+	}
+
+	wantText := `- debug: false
+- server.hosts.1: "b"
+- server.port: 8080
++ server.port: 9090
++ server.tls: true
++ tags: [1]
+`
+	if got := DiffString(a, b); got != wantText {
+		t.Errorf("DiffString =\n%s\nwant\n%s", got, wantText)
+	}
+	if got := DiffString(New(1), New("1"), DiffColor()); got != "\x1b[31m- (root): 1\x1b[0m\n\x1b[32m+ (root): \"1\"\x1b[0m\n" {
+		t.Errorf("colored DiffString = %q", got)
+	}
+	if DiffString(a, a) != "" || Diff(a, a) != nil {
+		t.Error("equal trees should have no diff")
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	a := New(map[string]any{"a/b": 1, "list": []int{1, 2, 3}, "gone": true})
+	b := New(map[string]any{"a/b": 2, "list": []int{1}, "new": "x"})
+	got, _ := JSONPatch(Diff(a, b)).MarshalJSON()
+	want := `[{"op":"replace","path":"/a~1b","value":2},{"op":"remove","path":"/gone"},` +
+		`{"op":"remove","path":"/list/2"},{"op":"remove","path":"/list/1"},{"op":"add","path":"/new","value":"x"}]`
+	if string(got) != want {
+		t.Errorf("JSONPatch =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package kit
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/* =============================================================================
+   DUMP
+   ============================================================================= */
+
+// Dump renders v as indented, multi-line text for debugging: Maps with
+// sorted keys, one element or entry per line, Strings quoted so stray
+// whitespace shows.
+//
+//	{
+//	  id: 1
+//	  tags: [
+//	    "a"
+//	  ]
+//	}
+func (v Value) Dump() string {
+	return string(v.appendDump(nil, 0, false))
+}
+
+// DumpVerbose is Dump with every Value annotated with its Kind, and
+// containers with their length, as in `tags: Array(1) [`.
+func (v Value) DumpVerbose() string {
+	return string(v.appendDump(nil, 0, true))
+}
+
+func (v Value) appendDump(b []byte, indent int, verbose bool) []byte {
+	v = v.Force()
+	if verbose {
+		b = append(b, v.K.String()...)
+		if v.K == Array || v.K == Map {
+			b = append(strconv.AppendInt(append(b, '('), int64(v.Len()), 10), ')')
+		}
+		b = append(b, ' ')
+	}
+	switch v.K {
+	case Array:
+		a := v.V.([]Value)
+		if len(a) == 0 {
+			return append(b, "[]"...)
+		}
+		b = append(b, "[\n"...)
+		for _, e := range a {
+			b = appendIndent(b, indent+2)
+			b = append(e.appendDump(b, indent+2, verbose), '\n')
+		}
+		return append(appendIndent(b, indent), ']')
+	case Map:
+		m := v.mapping()
+		if len(m) == 0 {
+			return append(b, "{}"...)
+		}
+		b = append(b, "{\n"...)
+		for _, k := range sortedKeys(m) {
+			b = appendIndent(b, indent+2)
+			b = append(b, k...)
+			b = append(b, ": "...)
+			b = append(m[k].appendDump(b, indent+2, verbose), '\n')
+		}
+		return append(appendIndent(b, indent), '}')
+	case String:
+		return strconv.AppendQuote(b, v.String())
+	case Bytes:
+		return fmt.Appendf(b, "%q", v.Bytes())
+	case Struct, Any, Func:
+		return fmt.Appendf(b, "%T(%+v)", v.V, v.V)
+	case Invalid:
+		return append(b, "!INVALID"...)
+	default:
+		return v.Append(b)
+	}
+}
@@ -0,0 +1,37 @@
+package kit
+
+import "testing"
+
+func TestValue_Dump(t *testing.T) {
+	v := New(map[string]any{
+		"id":    1,
+		"name":  "Ann ",
+		"tags":  []any{"a", nil},
+		"empty": map[string]any{},
+	})
+	want := `{
+  empty: {}
+  id: 1
+  name: "Ann "
+  tags: [
+    "a"
+    null
+  ]
+}`
+	if got := v.Dump(); got != want {
+		t.Errorf("Dump =\n%s\nwant\n%s", got, want)
+	}
+
+	wantVerbose := `Map(2) {
+  id: Number 1
+  tags: Array(1) [
+    Bool true
+  ]
+}`
+	if got := New(map[string]any{"id": 1, "tags": []any{true}}).DumpVerbose(); got != wantVerbose {
+		t.Errorf("DumpVerbose =\n%s\nwant\n%s", got, wantVerbose)
+	}
+	if got := New("x").Dump(); got != `"x"` {
+		t.Errorf("scalar Dump = %s", got)
+	}
+}
@@ -0,0 +1,78 @@
+package kit
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+type envOptions struct {
+	sep      string
+	environ  []string
+	keepCase bool
+}
+
+// EnvOption configures FromEnv.
+type EnvOption func(*envOptions)
+
+// EnvSeparator sets the string that splits variable names into path
+// segments (default "_"). Use "__" to keep single underscores inside keys.
+func EnvSeparator(sep string) EnvOption {
+	return func(o *envOptions) { o.sep = sep }
+}
+
+// EnvKeepCase keeps the original case of keys instead of lowering them.
+func EnvKeepCase() EnvOption {
+	return func(o *envOptions) { o.keepCase = true }
+}
+
+// Environ reads variables from a "KEY=value" list instead of os.Environ.
+func Environ(environ []string) EnvOption {
+	return func(o *envOptions) { o.environ = environ }
+}
+
+// FromEnv maps environment variables starting with prefix into a nested Map:
+// with prefix "APP_", APP_DB_HOST=x becomes {db: {host: "x"}}. Values are
+// typed with FromText. When a variable collides with a nested one
+// (APP_DB and APP_DB_HOST), the shorter name wins and the other is skipped.
+func FromEnv(prefix string, opts ...EnvOption) Value {
+	o := envOptions{sep: "_"}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.environ == nil {
+		o.environ = os.Environ()
+	}
+
+	env := make([]string, 0, len(o.environ))
+	for _, kv := range o.environ {
+		if strings.HasPrefix(kv, prefix) {
+			env = append(env, kv)
+		}
+	}
+	sort.Strings(env)
+
+	out := Value{K: Map, V: map[string]Value{}}
+	for _, kv := range env {
+		name, raw, ok := strings.Cut(kv[len(prefix):], "=")
+		if !ok || name == "" {
+			continue
+		}
+		if !o.keepCase {
+			name = strings.ToLower(name)
+		}
+		var path []string
+		for _, seg := range strings.Split(name, o.sep) {
+			if seg != "" {
+				path = append(path, seg)
+			}
+		}
+		if len(path) == 0 {
+			continue
+		}
+		if next, err := out.setIn(path, FromText(raw)); err == nil {
+			out = next
+		}
+	}
+	return out
+}
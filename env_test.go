@@ -0,0 +1,28 @@
+package kit
+
+import "testing"
+
+func TestFromEnv(t *testing.T) {
+	environ := []string{
+		"APP_DB_HOST=db.local",
+		"APP_DB_PORT=5432",
+		"APP_DEBUG=true",
+		"APP_NAME=kit",
+		"APP_NAME_SUFFIX=ignored",
+		"OTHER_X=1",
+	}
+	got := FromEnv("APP_", Environ(environ))
+	want := New(map[string]any{
+		"db":    map[string]any{"host": "db.local", "port": 5432},
+		"debug": true,
+		"name":  "kit",
+	})
+	if !got.Equal(want) {
+		t.Errorf("FromEnv = %v, want %v", got, want)
+	}
+
+	got = FromEnv("APP_", Environ([]string{"APP_MAX_CONNS__LIMIT=3"}), EnvSeparator("__"), EnvKeepCase())
+	if got.Path("MAX_CONNS.LIMIT").Int() != 3 {
+		t.Errorf("FromEnv with separator = %v", got)
+	}
+}
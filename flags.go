@@ -0,0 +1,72 @@
+package kit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseFlags parses command-line style arguments into a nested Map:
+//
+//	--db.port=5432   {db: {port: 5432}}
+//	--tags=x,y       {tags: [x, y]}
+//	--verbose        {verbose: true}
+//	--no-color       {color: false}
+//
+// Values are typed with FromText, dotted names nest, and repeating a flag
+// collects its values into an Array. Flags take values only through "=",
+// so a bare flag is always boolean. Arguments that are not flags, and
+// everything after "--", are returned as positional arguments.
+func ParseFlags(args []string) (Value, []string, error) {
+	out := Value{K: Map, V: map[string]Value{}}
+	var rest []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			rest = append(rest, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			rest = append(rest, arg)
+			continue
+		}
+		name := strings.TrimLeft(arg, "-")
+		var val Value
+		if k, raw, ok := strings.Cut(name, "="); ok {
+			name, val = k, flagValue(raw)
+		} else if strings.HasPrefix(name, "no-") {
+			name, val = name[3:], Value{K: Bool}
+		} else {
+			val = Value{K: Bool, N: 1}
+		}
+		if name == "" || strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") {
+			return Value{K: Invalid}, nil, fmt.Errorf("kit: invalid flag %q", arg)
+		}
+
+		path := splitPath(name)
+		if prev := out.Path(name); prev.IsValid() {
+			if prev.K == Array {
+				val = Value{K: Array, V: append(append([]Value(nil), prev.V.([]Value)...), val)}
+			} else {
+				val = Value{K: Array, V: []Value{prev, val}}
+			}
+		}
+		var err error
+		if out, err = out.setIn(path, val); err != nil {
+			return Value{K: Invalid}, nil, fmt.Errorf("kit: flag %q: %w", arg, err)
+		}
+	}
+	return out, rest, nil
+}
+
+func flagValue(raw string) Value {
+	if !strings.Contains(raw, ",") {
+		return FromText(raw)
+	}
+	parts := strings.Split(raw, ",")
+	arr := make([]Value, len(parts))
+	for i, p := range parts {
+		arr[i] = FromText(p)
+	}
+	return Value{K: Array, V: arr}
+}
@@ -0,0 +1,34 @@
+package kit
+
+import "testing"
+
+func TestParseFlags(t *testing.T) {
+	v, rest, err := ParseFlags([]string{
+		"--db.port=5432", "--db.host=localhost", "--tags=x,y", "--verbose",
+		"-no-color", "input.txt", "--tag=a", "--tag=b", "--", "--raw",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := New(map[string]any{
+		"db":      map[string]any{"port": 5432, "host": "localhost"},
+		"tags":    []any{"x", "y"},
+		"verbose": true,
+		"color":   false,
+		"tag":     []any{"a", "b"},
+	})
+	if !v.Equal(want) {
+		t.Errorf("ParseFlags = %v, want %v", v, want)
+	}
+	if len(rest) != 2 || rest[0] != "input.txt" || rest[1] != "--raw" {
+		t.Errorf("positional = %q", rest)
+	}
+
+	if _, _, err := ParseFlags([]string{"--a=1", "--a.b=2"}); err == nil {
+		t.Error("expected error when nesting under a scalar flag")
+	}
+	if _, _, err := ParseFlags([]string{"--=1"}); err == nil {
+		t.Error("expected error for empty flag name")
+	}
+}
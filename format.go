@@ -0,0 +1,169 @@
+package kit
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Format implements fmt.Formatter:
+//
+//	%v, %s  the Text form; Arrays print as [a b], Maps as {k:v} with sorted keys
+//	%+v     a typed debug form such as Map{id: Number(1)}
+//	%#v     a Go literal that rebuilds the Value through kit.New
+//	%q      the quoted Text form
+//
+// Numeric verbs (%d, %f, %g, %e, %x, ...) format the exported Go value.
+func (v Value) Format(f fmt.State, verb rune) {
+	v = v.Force()
+	switch verb {
+	case 'v':
+		switch {
+		case f.Flag('#'):
+			f.Write(v.appendGo(nil))
+		case f.Flag('+'):
+			f.Write(v.appendDebug(nil))
+		default:
+			fmt.Fprintf(f, fmt.FormatString(f, 's'), v.appendPlain(nil))
+		}
+	case 's', 'q':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.appendPlain(nil))
+	case 'd':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), int64(v.N))
+	case 't':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.Truthy())
+	default:
+		if v.IsScalar() {
+			fmt.Fprintf(f, fmt.FormatString(f, verb), v.N)
+			return
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), v.Export())
+	}
+}
+
+func sortedKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v Value) appendPlain(b []byte) []byte {
+	switch v.K {
+	case Array:
+		b = append(b, '[')
+		for i, e := range v.V.([]Value) {
+			if i > 0 {
+				b = append(b, ' ')
+			}
+			b = e.Force().appendPlain(b)
+		}
+		return append(b, ']')
+	case Map:
+		m := v.mapping()
+		b = append(b, '{')
+		for i, k := range sortedKeys(m) {
+			if i > 0 {
+				b = append(b, ' ')
+			}
+			b = append(b, k...)
+			b = append(b, ':')
+			b = m[k].Force().appendPlain(b)
+		}
+		return append(b, '}')
+	case Struct, Any, Func:
+		return fmt.Appendf(b, "%v", v.V)
+	case Invalid:
+		return append(b, "!INVALID"...)
+	default:
+		return v.Append(b)
+	}
+}
+
+func (v Value) appendDebug(b []byte) []byte {
+	b = append(b, v.K.String()...)
+	switch v.K {
+	case Array:
+		b = append(b, '[')
+		for i, e := range v.V.([]Value) {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = e.Force().appendDebug(b)
+		}
+		return append(b, ']')
+	case Map:
+		m := v.mapping()
+		b = append(b, '{')
+		for i, k := range sortedKeys(m) {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = append(b, k...)
+			b = append(b, ": "...)
+			b = m[k].Force().appendDebug(b)
+		}
+		return append(b, '}')
+	case String:
+		return append(strconv.AppendQuote(append(b, '('), v.String()), ')')
+	case Invalid, Nil:
+		return b
+	case Struct, Any, Func:
+		return fmt.Appendf(b, "(%T)", v.V)
+	default:
+		return append(v.Append(append(b, '(')), ')')
+	}
+}
+
+func (v Value) appendGo(b []byte) []byte {
+	if v.K == Invalid {
+		return append(b, "kit.Value{}"...)
+	}
+	b = append(b, "kit.New("...)
+	b = v.appendGoArg(b)
+	return append(b, ')')
+}
+
+func (v Value) appendGoArg(b []byte) []byte {
+	switch v.K {
+	case Nil:
+		return append(b, "nil"...)
+	case Number:
+		return strconv.AppendFloat(b, v.N, 'g', -1, 64)
+	case Bool:
+		return strconv.AppendBool(b, v.N > 0)
+	case Time:
+		return fmt.Appendf(b, "time.Unix(0, %d)", int64(v.N))
+	case Duration:
+		return fmt.Appendf(b, "time.Duration(%d)", int64(v.N))
+	case String:
+		return strconv.AppendQuote(b, v.String())
+	case Bytes:
+		return fmt.Appendf(b, "%#v", v.Bytes())
+	case Array:
+		b = append(b, "[]any{"...)
+		for i, e := range v.V.([]Value) {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = e.Force().appendGoArg(b)
+		}
+		return append(b, '}')
+	case Map:
+		m := v.mapping()
+		b = append(b, "map[string]any{"...)
+		for i, k := range sortedKeys(m) {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = strconv.AppendQuote(b, k)
+			b = append(b, ": "...)
+			b = m[k].Force().appendGoArg(b)
+		}
+		return append(b, '}')
+	default:
+		return fmt.Appendf(b, "%#v", v.V)
+	}
+}
@@ -0,0 +1,33 @@
+package kit
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValue_Format(t *testing.T) {
+	v := New(map[string]any{"id": 1, "tags": []string{"a", "b"}, "ok": true})
+
+	tests := []struct {
+		format string
+		val    Value
+		want   string
+	}{
+		{"%v", New(42), "42"},
+		{"%v", v, "{id:1 ok:true tags:[a b]}"},
+		{"%+v", v, `Map{id: Number(1), ok: Bool(true), tags: Array[String("a"), String("b")]}`},
+		{"%#v", v, `kit.New(map[string]any{"id": 1, "ok": true, "tags": []any{"a", "b"}})`},
+		{"%#v", New(time.Duration(5)), "kit.New(time.Duration(5))"},
+		{"%#v", Value{}, "kit.Value{}"},
+		{"%q", New("hi"), `"hi"`},
+		{"%5s|", New("x"), "    x|"},
+		{"%d", New(3.9), "3"},
+		{"%.2f", New(3.14159), "3.14"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprintf(tt.format, tt.val); got != tt.want {
+			t.Errorf("Sprintf(%q) = %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package kit
+
+import (
+	"reflect"
+	"time"
+)
+
+/* =============================================================================
+   CALLABLES
+   A Func Value wraps a Go function. Common Value-typed signatures are called
+   directly; anything else goes through reflection, converting arguments from
+   their exported Go form. Surplus arguments are dropped, so a callback may
+   ignore the index or key that collection helpers pass after the element.
+   ============================================================================= */
+
+var valueType = reflect.TypeOf(Value{})
+
+// Call invokes a Func Value with args. It returns Invalid when v is not
+// callable, the arguments cannot be converted, or the function reports a
+// non-nil error as its last result.
+func (v Value) Call(args ...Value) Value {
+	if v.K != Func {
+		return Value{K: Invalid}
+	}
+	switch fn := v.V.(type) {
+	case *lazy:
+		return fn.get()
+	case func(...Value) Value:
+		return fn(args...)
+	case func() Value:
+		return fn()
+	case func(Value) Value:
+		return fn(arg(args, 0))
+	case func(Value, Value) Value:
+		return fn(arg(args, 0), arg(args, 1))
+	case func(Value, Value, Value) Value:
+		return fn(arg(args, 0), arg(args, 1), arg(args, 2))
+	case func(Value) bool:
+		return New(fn(arg(args, 0)))
+	case func(Value, Value) bool:
+		return New(fn(arg(args, 0), arg(args, 1)))
+	}
+	if m := loadMetrics(); m != nil {
+		start := time.Now()
+		out := callReflect(reflect.ValueOf(v.V), args)
+		m.ReflectCall(reflect.TypeOf(v.V), time.Since(start))
+		return out
+	}
+	return callReflect(reflect.ValueOf(v.V), args)
+}
+
+func arg(args []Value, i int) Value {
+	if i < len(args) {
+		return args[i]
+	}
+	return Value{K: Nil}
+}
+
+func callReflect(fn reflect.Value, args []Value) Value {
+	t := fn.Type()
+	n := t.NumIn()
+	if t.IsVariadic() {
+		n--
+		if len(args) < n {
+			return Value{K: Invalid}
+		}
+	} else if len(args) > n {
+		args = args[:n]
+	} else if len(args) < n {
+		return Value{K: Invalid}
+	}
+
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		var pt reflect.Type
+		if t.IsVariadic() && i >= n {
+			pt = t.In(n).Elem()
+		} else {
+			pt = t.In(i)
+		}
+		rv, ok := convertArg(a, pt)
+		if !ok {
+			return Value{K: Invalid}
+		}
+		in[i] = rv
+	}
+
+	out := fn.Call(in)
+	if len(out) == 0 {
+		return Value{K: Nil}
+	}
+	if last := out[len(out)-1]; last.Type() == reflect.TypeOf((*error)(nil)).Elem() {
+		if !last.IsNil() {
+			return Value{K: Invalid}
+		}
+		out = out[:len(out)-1]
+		if len(out) == 0 {
+			return Value{K: Nil}
+		}
+	}
+	return New(out[0].Interface())
+}
+
+func convertArg(a Value, t reflect.Type) (reflect.Value, bool) {
+	if t == valueType {
+		return reflect.ValueOf(a), true
+	}
+	x := a.Export()
+	if x == nil {
+		return reflect.Zero(t), true
+	}
+	rv := reflect.ValueOf(x)
+	switch {
+	case rv.Type().AssignableTo(t):
+		return rv, true
+	case rv.Type().ConvertibleTo(t) && rv.Kind() != reflect.Slice && rv.Kind() != reflect.Map:
+		return rv.Convert(t), true
+	}
+	return reflect.Value{}, false
+}
+
+// callable adapts fn, a Go function or a Func Value, into a Value-typed call.
+func callable(fn any) func(args ...Value) Value {
+	switch f := fn.(type) {
+	case func(...Value) Value:
+		return f
+	case func(Value) Value:
+		return func(args ...Value) Value { return f(arg(args, 0)) }
+	case func(Value) bool:
+		return func(args ...Value) Value { return New(f(arg(args, 0))) }
+	}
+	v := New(fn)
+	return v.Call
+}
+
+// Arity returns the number of parameters a Func Value declares and whether
+// it also takes variadic ones. Lazy Values take none; other kinds report
+// 0, false.
+func (v Value) Arity() (n int, variadic bool) {
+	if v.K != Func {
+		return 0, false
+	}
+	switch v.V.(type) {
+	case *lazy:
+		return 0, false
+	case func(...Value) Value:
+		return 0, true
+	}
+	t := reflect.TypeOf(v.V)
+	if t.IsVariadic() {
+		return t.NumIn() - 1, true
+	}
+	return t.NumIn(), false
+}
+
+// Methods returns the exported methods of a Struct as a Map from name to a
+// Func Value bound to the struct, callable with Call and described by
+// Arity. A Struct holding a pointer includes pointer-receiver methods; one
+// held by value only value-receiver ones. Other kinds give Invalid.
+//
+// Expression hosts can expose an allowlist from it:
+//
+//	ops := v.Methods()
+//	allowed := map[string]kit.Value{"Total": ops.Get("Total")}
+func (v Value) Methods() Value {
+	if v = v.Force(); v.K != Struct {
+		return Value{K: Invalid}
+	}
+	rv := reflect.ValueOf(v.V)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return Value{K: Map, V: map[string]Value{}}
+	}
+	t := rv.Type()
+	out := make(map[string]Value, t.NumMethod())
+	for i := 0; i < t.NumMethod(); i++ {
+		out[t.Method(i).Name] = Value{K: Func, V: rv.Method(i).Interface()}
+	}
+	return Value{K: Map, V: out}
+}
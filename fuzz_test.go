@@ -0,0 +1,127 @@
+package kit
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// The fuzz targets check that decoders never panic on hostile input and
+// that whatever they accept survives a round trip through the encoder.
+// Run one with, for example:
+//
+//	go test -fuzz=FuzzFromYAML
+
+func FuzzFromJSON(f *testing.F) {
+	for _, s := range []string{`{"a":[1,2.5,"x",null,true]}`, `[]`, `"\u00e9"`, `1e400`, `[[[[]]]]`} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := FromJSON(data)
+		if err != nil {
+			return
+		}
+		out, err := v.AppendJSON(nil)
+		if err != nil {
+			t.Fatalf("re-encoding %q: %v", data, err)
+		}
+		back, err := FromJSON(out)
+		if err != nil || !back.Equal(v) {
+			t.Fatalf("round trip of %q: %s, %v", data, out, err)
+		}
+	})
+}
+
+func FuzzDecodeMsgPack(f *testing.F) {
+	for _, v := range []Value{
+		New(map[string]any{"a": []any{1, -1, 2.5, "x", nil, true}}),
+		New([]byte{1, 2}),
+		Random(rand.New(rand.NewSource(1))),
+	} {
+		data, _ := v.AppendMsgPack(nil)
+		f.Add(data)
+	}
+	f.Add([]byte{0xdd, 0xff, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		v, err := DecodeMsgPack(data)
+		if view, verr := DecodeMsgPackView(data); (err == nil) != (verr == nil) || err == nil && !view.Equal(v) {
+			t.Fatalf("DecodeMsgPackView(%x) = %v, %v; DecodeMsgPack gave %v, %v", data, view, verr, v, err)
+		}
+		if err != nil {
+			return
+		}
+		out, err := v.AppendMsgPack(nil)
+		if err != nil {
+			t.Fatalf("re-encoding %x: %v", data, err)
+		}
+		back, err := DecodeMsgPack(out)
+		if err != nil || !back.Equal(v) {
+			t.Fatalf("round trip of %x: %x, %v", data, out, err)
+		}
+	})
+}
+
+func FuzzFromYAML(f *testing.F) {
+	for _, s := range []string{
+		"a: 1\nb:\n  - x\n  - {y: [1, 2]}\n",
+		"- |\n  text\n- >-\n  folded\n",
+		"'q''s': \"\\u00e9\"\n",
+		"%YAML 1.2\n---\n~\n...\n",
+	} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if !utf8.Valid(data) {
+			// Accepted, but like JSON the encoder replaces invalid bytes.
+			FromYAML(data)
+			return
+		}
+		v, err := FromYAML(data)
+		if err != nil {
+			return
+		}
+		out, err := v.AppendYAML(nil)
+		if err != nil {
+			t.Fatalf("re-encoding %q: %v", data, err)
+		}
+		back, err := FromYAML(out)
+		if err != nil || !back.Equal(v) {
+			t.Fatalf("round trip of %q:\n%s\n%v %s", data, out, err, DiffString(v, back))
+		}
+	})
+}
+
+func FuzzFromTOML(f *testing.F) {
+	for _, s := range []string{
+		"a = 1\n[t]\nb = [1, 'x', {c = true}]\n",
+		"[[arr]]\nx = 1979-05-27T07:32:00Z\n[[arr]]\ny = \"\"\"\nml\"\"\"\n",
+		"k.\"q\" = inf\n",
+	} {
+		f.Add([]byte(s))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		FromTOML(data)
+	})
+}
+
+func FuzzFromCSV(f *testing.F) {
+	f.Add("a,b\n1,\"x,y\"\n")
+	f.Fuzz(func(t *testing.T, s string) {
+		FromCSV(strings.NewReader(s))
+	})
+}
+
+func FuzzPath(f *testing.F) {
+	f.Add("a.0.b", "a.*.b")
+	f.Add("", "**")
+	f.Fuzz(func(t *testing.T, path, pattern string) {
+		v := New(map[string]any{"a": []any{map[string]any{"b": "x"}}, "": 1})
+		if !v.Path(path).IsBlank() && !v.Exists(path) {
+			t.Fatalf("Path(%q) found a value Exists denies", path)
+		}
+		MatchPath(pattern, path)
+		Redact(v, pattern)
+		v.Update(func(tx *Tx) error { return tx.Set(path, 1) })
+	})
+}
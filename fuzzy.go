@@ -0,0 +1,104 @@
+package kit
+
+/* =============================================================================
+   FUZZY MATCHING
+   Distances are computed over runes, so accented and CJK text count one edit
+   per character.
+   ============================================================================= */
+
+// Levenshtein returns the number of single-rune insertions, deletions and
+// substitutions needed to turn a into b.
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) < len(rb) {
+		ra, rb = rb, ra
+	}
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		diag := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			diag, row[j] = row[j], min(row[j]+1, row[j-1]+1, diag+cost)
+		}
+	}
+	return row[len(rb)]
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 (no
+// likeness) to 1 (identical), favouring strings with a common prefix.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	window := max(max(len(ra), len(rb))/2-1, 0)
+	ma, mb := make([]bool, len(ra)), make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !mb[j] && rb[j] == r {
+				ma[i], mb[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !ma[i] {
+			continue
+		}
+		for !mb[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions/2))/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// Similarity returns the Jaro-Winkler similarity of the Text forms of v and
+// other, from 0 to 1. Blank values are similar to nothing.
+func (v Value) Similarity(other Value) float64 {
+	v, other = v.Force(), other.Force()
+	if v.IsBlank() || other.IsBlank() {
+		return 0
+	}
+	return JaroWinkler(v.Text(), other.Text())
+}
+
+// FuzzyContains reports whether an Array holds a String whose Similarity to
+// s is at least threshold; 0.85 is a reasonable starting point for
+// search suggestions. Other kinds report false.
+func (v Value) FuzzyContains(s string, threshold float64) bool {
+	target := Value{K: String, V: s}
+	a, _ := v.elements()
+	for _, e := range a {
+		if e = e.Force(); e.K == String && e.Similarity(target) >= threshold {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package kit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"kitten", "sitting", 3},
+		{"abc", "", 3},
+		{"Hà Nội", "Ha Noi", 2},
+		{"flaw", "lawn", 2},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"MARTHA", "MARHTA", 0.961},
+		{"DWAYNE", "DUANE", 0.840},
+		{"DIXON", "DICKSONX", 0.813},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"", "", 1},
+	}
+	for _, tt := range tests {
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestValue_FuzzyContains(t *testing.T) {
+	names := New([]any{"kubernetes", "postgres", 42})
+	if !names.FuzzyContains("postgers", 0.9) || names.FuzzyContains("mysql", 0.85) {
+		t.Error("FuzzyContains")
+	}
+	if New(42).Similarity(New("42")) != 1 || New(nil).Similarity(New("null")) != 0 {
+		t.Error("Similarity")
+	}
+}
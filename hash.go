@@ -0,0 +1,121 @@
+package kit
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+var hashSeed = maphash.MakeSeed()
+
+// hash returns a structural hash consistent with Equal: Values that are
+// Equal hash alike. Structs hash by their fields; Func and Any kinds hash
+// by kind only and rely on Equal to tell them apart.
+func (v Value) hash() uint64 {
+	var h maphash.Hash
+	h.SetSeed(hashSeed)
+	v.writeHash(&h)
+	return h.Sum64()
+}
+
+func (v Value) writeHash(h *maphash.Hash) {
+	v = v.Force()
+	h.WriteByte(byte(v.K))
+	var buf [8]byte
+	switch v.K {
+	case Number, Bool, Time, Duration:
+		n := v.N
+		switch {
+		case n == 0:
+			n = 0 // fold -0 into +0
+		case n != n:
+			n = math.NaN() // one NaN payload, as Equal treats all NaNs alike
+		}
+		binary.LittleEndian.PutUint64(buf[:], math.Float64bits(n))
+		h.Write(buf[:])
+	case String:
+		h.WriteString(v.String())
+	case Bytes:
+		h.Write(v.Bytes())
+	case Array:
+		a := v.V.([]Value)
+		binary.LittleEndian.PutUint64(buf[:], uint64(len(a)))
+		h.Write(buf[:])
+		for _, e := range a {
+			e.writeHash(h)
+		}
+	case Map:
+		m := v.mapping()
+		for _, k := range sortedKeys(m) {
+			h.WriteString(k)
+			h.WriteByte(0)
+			m[k].writeHash(h)
+		}
+	case Struct:
+		writeReflectHash(h, reflect.ValueOf(v.V), 0)
+	}
+}
+
+// writeReflectHash hashes the strings, integers and bools inside a Go
+// value, following pointers a few levels deep. Floats, maps and funcs are
+// skipped: that weakens the hash but keeps it consistent with Equal.
+func writeReflectHash(h *maphash.Hash, rv reflect.Value, depth int) {
+	if depth > 4 || !rv.IsValid() {
+		return
+	}
+	var buf [8]byte
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !rv.IsNil() {
+			writeReflectHash(h, rv.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			writeReflectHash(h, rv.Field(i), depth)
+		}
+	case reflect.Array, reflect.Slice:
+		for i := 0; i < rv.Len(); i++ {
+			writeReflectHash(h, rv.Index(i), depth+1)
+		}
+	case reflect.String:
+		h.WriteString(rv.String())
+		h.WriteByte(0)
+	case reflect.Bool:
+		if rv.Bool() {
+			h.WriteByte(1)
+		} else {
+			h.WriteByte(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(buf[:], uint64(rv.Int()))
+		h.Write(buf[:])
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(buf[:], rv.Uint())
+		h.Write(buf[:])
+	}
+}
+
+// valueSet is a hash-indexed set of Values compared with Equal.
+type valueSet map[uint64][]Value
+
+// add inserts x and reports whether it was not already present.
+func (s valueSet) add(x Value) bool {
+	k := x.hash()
+	for _, y := range s[k] {
+		if x.Equal(y) {
+			return false
+		}
+	}
+	s[k] = append(s[k], x)
+	return true
+}
+
+func (s valueSet) has(x Value) bool {
+	for _, y := range s[x.hash()] {
+		if x.Equal(y) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,80 @@
+package kit
+
+import (
+	"context"
+	"time"
+)
+
+/* =============================================================================
+   EVALUATION ENVIRONMENT & HOOKS
+   An Env is what user-authored expressions run against: a root Value and a
+   table of named functions. Hooks observe every path read, call made and Go
+   value converted through it, so a pipeline can be traced in production
+   without changing its code. (Env is unrelated to FromEnv, which reads
+   process environment variables.)
+   ============================================================================= */
+
+// Hooks are callbacks invoked by an Env. Any of them may be nil; they run
+// synchronously on the evaluating goroutine and must be safe for
+// concurrent use if the Env is shared.
+type Hooks struct {
+	OnGet     func(path string, v Value)
+	OnCall    func(name string, args []Value, result Value, elapsed time.Duration)
+	OnConvert func(from any, to Value)
+}
+
+// Env is an evaluation environment. The zero Env has a Nil root, no
+// functions and no hooks. Fields must not be modified while the Env is in
+// use.
+type Env struct {
+	Root  Value
+	Funcs map[string]Value
+	Hooks Hooks
+}
+
+// Get reads a dot-separated path of Root, reporting it to OnGet.
+func (e *Env) Get(path string) Value {
+	v := e.Root.Path(path)
+	if e.Hooks.OnGet != nil {
+		e.Hooks.OnGet(path, v)
+	}
+	return v
+}
+
+// Call invokes the named function, reporting it to OnCall. An unknown name
+// gives Invalid, which is reported too.
+func (e *Env) Call(name string, args ...Value) Value {
+	start := time.Now()
+	fn, ok := e.Funcs[name]
+	out := Value{K: Invalid}
+	if ok {
+		out = fn.Call(args...)
+	}
+	if e.Hooks.OnCall != nil {
+		e.Hooks.OnCall(name, args, out, time.Since(start))
+	}
+	return out
+}
+
+// Convert normalizes a Go value with New, reporting it to OnConvert.
+func (e *Env) Convert(x any) Value {
+	v := New(x)
+	if e.Hooks.OnConvert != nil {
+		e.Hooks.OnConvert(x, v)
+	}
+	return v
+}
+
+type envKey struct{}
+
+// WithEnv returns a child context carrying e, so request handlers can reach
+// the traced environment without threading it through every call.
+func WithEnv(ctx context.Context, e *Env) context.Context {
+	return context.WithValue(ctx, envKey{}, e)
+}
+
+// EnvFrom returns the Env carried by ctx, or nil.
+func EnvFrom(ctx context.Context) *Env {
+	e, _ := ctx.Value(envKey{}).(*Env)
+	return e
+}
@@ -0,0 +1,45 @@
+package kit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEnv_Hooks(t *testing.T) {
+	var trace []string
+	env := &Env{
+		Root: New(map[string]any{"user": map[string]any{"name": "ann"}}),
+		Funcs: map[string]Value{
+			"upper": New(func(v Value) Value { return New(strings.ToUpper(v.String())) }),
+		},
+		Hooks: Hooks{
+			OnGet: func(path string, v Value) { trace = append(trace, "get "+path+"="+v.Text()) },
+			OnCall: func(name string, args []Value, out Value, _ time.Duration) {
+				trace = append(trace, "call "+name+"="+out.Text())
+			},
+			OnConvert: func(from any, to Value) { trace = append(trace, "convert "+to.K.String()) },
+		},
+	}
+	ctx := WithEnv(context.Background(), env)
+
+	e := EnvFrom(ctx)
+	if got := e.Call("upper", e.Get("user.name")); got.String() != "ANN" {
+		t.Errorf("Call = %v", got)
+	}
+	e.Convert(3)
+	if !e.Call("missing").IsInvalid() {
+		t.Error("unknown function should be Invalid")
+	}
+
+	want := []string{"get user.name=ann", "call upper=ANN", "convert Number", "call missing="}
+	if strings.Join(trace, "|") != strings.Join(want, "|") {
+		t.Errorf("trace = %q", trace)
+	}
+
+	var zero Env
+	if !zero.Get("x").IsNil() || EnvFrom(context.Background()) != nil {
+		t.Error("zero Env and empty context")
+	}
+}
@@ -0,0 +1,392 @@
+package kit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   HTTP BINDING
+   ============================================================================= */
+
+// Source identifies where a bound request parameter came from.
+type Source uint8
+
+const (
+	SourceQuery Source = iota // URL query string
+	SourceBody                // JSON, urlencoded or multipart body
+	SourcePath                // route wildcards of the matched ServeMux pattern
+)
+
+type bindOptions struct {
+	order   []Source
+	maxBody int64
+}
+
+// BindOption configures Bind.
+type BindOption func(*bindOptions)
+
+// Precedence sets the merge order of sources: later sources override keys
+// set by earlier ones. The default is SourceQuery, SourceBody, SourcePath.
+// Sources left out are not read.
+func Precedence(order ...Source) BindOption {
+	return func(o *bindOptions) { o.order = order }
+}
+
+// MaxBodyBytes limits how much of the request body Bind reads (default 10 MiB).
+func MaxBodyBytes(n int64) BindOption {
+	return func(o *bindOptions) { o.maxBody = n }
+}
+
+// Bind merges route wildcards, query parameters and the request body into a
+// single Map. Parameters are kept as Strings, repeated parameters become
+// Arrays, and multipart files appear as Struct values of *multipart.FileHeader.
+// A JSON body that is not an object is bound under the "body" key.
+func Bind(r *http.Request, opts ...BindOption) (Value, error) {
+	o := bindOptions{
+		order:   []Source{SourceQuery, SourceBody, SourcePath},
+		maxBody: 10 << 20,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	out := make(map[string]Value)
+	for _, src := range o.order {
+		switch src {
+		case SourceQuery:
+			mergeValues(out, r.URL.Query())
+		case SourcePath:
+			for _, name := range patternWildcards(r.Pattern) {
+				out[name] = Value{K: String, V: r.PathValue(name)}
+			}
+		case SourceBody:
+			if err := bindBody(r, out, o.maxBody); err != nil {
+				return Value{K: Invalid}, err
+			}
+		}
+	}
+	return Value{K: Map, V: out}, nil
+}
+
+func bindBody(r *http.Request, out map[string]Value, limit int64) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch {
+	case ct == "application/json" || strings.HasSuffix(ct, "+json"):
+		data, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+		if err != nil {
+			return err
+		}
+		if int64(len(data)) > limit {
+			return fmt.Errorf("kit: request body exceeds %d bytes", limit)
+		}
+		if len(data) == 0 {
+			return nil
+		}
+		body, err := FromJSON(data)
+		if err != nil {
+			return err
+		}
+		if body.K != Map {
+			out["body"] = body
+			return nil
+		}
+		for k, v := range body.mapping() {
+			out[k] = v
+		}
+	case ct == "application/x-www-form-urlencoded":
+		r.Body = http.MaxBytesReader(nil, r.Body, limit)
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
+		mergeValues(out, r.PostForm)
+	case ct == "multipart/form-data":
+		// ParseMultipartForm's limit only bounds memory; files beyond it
+		// spill to disk, so the body itself is capped too.
+		r.Body = http.MaxBytesReader(nil, r.Body, limit)
+		if err := r.ParseMultipartForm(limit); err != nil {
+			return err
+		}
+		mergeValues(out, r.MultipartForm.Value)
+		for name, files := range r.MultipartForm.File {
+			if len(files) == 1 {
+				out[name] = New(files[0])
+				continue
+			}
+			arr := make([]Value, len(files))
+			for i, fh := range files {
+				arr[i] = New(fh)
+			}
+			out[name] = Value{K: Array, V: arr}
+		}
+	}
+	return nil
+}
+
+func mergeValues(out map[string]Value, vals url.Values) {
+	for k, vs := range vals {
+		if len(vs) == 1 {
+			out[k] = Value{K: String, V: vs[0]}
+			continue
+		}
+		arr := make([]Value, len(vs))
+		for i, s := range vs {
+			arr[i] = Value{K: String, V: s}
+		}
+		out[k] = Value{K: Array, V: arr}
+	}
+}
+
+// patternWildcards extracts the wildcard names of a ServeMux pattern such as
+// "GET /users/{id}/files/{path...}".
+func patternWildcards(pattern string) []string {
+	var names []string
+	for {
+		i := strings.IndexByte(pattern, '{')
+		if i < 0 {
+			return names
+		}
+		j := strings.IndexByte(pattern[i:], '}')
+		if j < 0 {
+			return names
+		}
+		name := strings.TrimSuffix(pattern[i+1:i+j], "...")
+		if name != "$" && name != "" {
+			names = append(names, name)
+		}
+		pattern = pattern[i+j+1:]
+	}
+}
+
+/* =============================================================================
+   HTTP RESPONSES
+   ============================================================================= */
+
+type encoding struct {
+	mediaType string
+	aliases   []string
+	encode    func(Value) ([]byte, error)
+	decode    func([]byte) (Value, error)
+}
+
+var encodings = []encoding{
+	{"application/json", nil, func(v Value) ([]byte, error) { return v.AppendJSON(nil) }, FromJSON},
+	{"application/yaml", []string{"application/x-yaml", "text/yaml", "text/x-yaml"},
+		func(v Value) ([]byte, error) { return v.AppendYAML(nil) }, FromYAML},
+	{"application/msgpack", []string{"application/x-msgpack", "application/vnd.msgpack"},
+		func(v Value) ([]byte, error) { return v.AppendMsgPack(nil) }, DecodeMsgPack},
+}
+
+// Respond writes v with status 200 in the format preferred by the request's
+// Accept header: JSON (the default), YAML or MessagePack.
+func Respond(w http.ResponseWriter, r *http.Request, v Value) error {
+	return RespondStatus(w, r, http.StatusOK, v)
+}
+
+// RespondStatus is Respond with an explicit status code. If v cannot be
+// encoded nothing is written to w and the error is returned, leaving the
+// caller free to send an error response.
+func RespondStatus(w http.ResponseWriter, r *http.Request, code int, v Value) error {
+	enc := negotiate(r.Header.Get("Accept"))
+	body, err := enc.encode(v)
+	if err != nil {
+		return err
+	}
+	h := w.Header()
+	h.Set("Content-Type", enc.mediaType)
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	h.Add("Vary", "Accept")
+	w.WriteHeader(code)
+	if r.Method == http.MethodHead {
+		return nil
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// negotiate picks the supported encoding with the highest q-value; ties keep
+// the order of the Accept header.
+func negotiate(accept string) *encoding {
+	best, bestQ := &encodings[0], -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+		if q <= bestQ || q == 0 {
+			continue
+		}
+		for i := range encodings {
+			e := &encodings[i]
+			if mt == "*/*" || mt == e.mediaType || mt == "application/*" && strings.HasPrefix(e.mediaType, "application/") || contains(e.aliases, mt) {
+				best, bestQ = e, q
+				break
+			}
+		}
+	}
+	return best
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+/* =============================================================================
+   HTTP FETCH
+   ============================================================================= */
+
+type fetchOptions struct {
+	method  string
+	header  http.Header
+	body    Value
+	client  *http.Client
+	maxBody int64
+}
+
+// FetchOption configures Fetch.
+type FetchOption func(*fetchOptions)
+
+// FetchMethod sets the request method; the default is GET, or POST when a
+// body is set.
+func FetchMethod(method string) FetchOption {
+	return func(o *fetchOptions) { o.method = method }
+}
+
+// FetchHeader adds a request header.
+func FetchHeader(key, value string) FetchOption {
+	return func(o *fetchOptions) { o.header.Add(key, value) }
+}
+
+// FetchBody sends v as a JSON request body.
+func FetchBody(v Value) FetchOption {
+	return func(o *fetchOptions) { o.body = v }
+}
+
+// FetchClient sets the client used for the request (default
+// http.DefaultClient), for timeouts, transports and cookies.
+func FetchClient(c *http.Client) FetchOption {
+	return func(o *fetchOptions) { o.client = c }
+}
+
+// FetchMaxBytes limits how much of the response body Fetch reads (default
+// 10 MiB); longer bodies are an error.
+func FetchMaxBytes(n int64) FetchOption {
+	return func(o *fetchOptions) { o.maxBody = n }
+}
+
+// Fetch performs an HTTP request and returns the response as a Map:
+//
+//	{status: 200, headers: {Content-Type: "application/json"}, body: ...}
+//
+// The body is decoded by its Content-Type: JSON, YAML, MessagePack, TOML
+// and CSV become Value trees, other text/* types a String and anything
+// else Bytes; an empty body is Nil. Header values are Strings, or Arrays
+// when repeated. A status outside 2xx is not an error, so check "status".
+func Fetch(ctx context.Context, rawURL string, opts ...FetchOption) (Value, error) {
+	o := fetchOptions{
+		header:  http.Header{},
+		body:    Value{K: Invalid},
+		client:  http.DefaultClient,
+		maxBody: 10 << 20,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var body io.Reader
+	if o.body.K != Invalid {
+		data, err := o.body.AppendJSON(nil)
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		body = bytes.NewReader(data)
+		if o.method == "" {
+			o.method = http.MethodPost
+		}
+		if o.header.Get("Content-Type") == "" {
+			o.header.Set("Content-Type", "application/json")
+		}
+	}
+	if o.method == "" {
+		o.method = http.MethodGet
+	}
+	if o.header.Get("Accept") == "" {
+		o.header.Set("Accept", "application/json, application/yaml;q=0.9, application/msgpack;q=0.9, */*;q=0.1")
+	}
+	req, err := http.NewRequestWithContext(ctx, o.method, rawURL, body)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	req.Header = o.header
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, o.maxBody+1))
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	if int64(len(data)) > o.maxBody {
+		return Value{K: Invalid}, fmt.Errorf("kit: response body exceeds %d bytes", o.maxBody)
+	}
+	decoded, err := decodeBody(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return Value{K: Invalid}, fmt.Errorf("kit: %s %s: %w", o.method, rawURL, err)
+	}
+
+	headers := make(map[string]Value, len(resp.Header))
+	mergeValues(headers, url.Values(resp.Header))
+	return Value{K: Map, V: map[string]Value{
+		"status":  {K: Number, N: float64(resp.StatusCode)},
+		"headers": {K: Map, V: headers},
+		"body":    decoded,
+	}}, nil
+}
+
+// decodeBody decodes data by the media type of contentType.
+func decodeBody(contentType string, data []byte) (Value, error) {
+	if len(data) == 0 {
+		return Value{K: Nil}, nil
+	}
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mt, "+json"):
+		return FromJSON(data)
+	case mt == "application/toml":
+		return FromTOML(data)
+	case mt == "text/csv":
+		return FromCSV(bytes.NewReader(data))
+	}
+	for i := range encodings {
+		if e := &encodings[i]; mt == e.mediaType || contains(e.aliases, mt) {
+			return e.decode(data)
+		}
+	}
+	if strings.HasPrefix(mt, "text/") {
+		return Value{K: String, V: string(data)}, nil
+	}
+	return Value{K: Bytes, V: data}, nil
+}
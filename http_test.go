@@ -0,0 +1,144 @@
+package kit
+
+import (
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBind(t *testing.T) {
+	var got Value
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		if got, err = Bind(r); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	req := httptest.NewRequest("POST", "/users/42?id=1&tag=a&tag=b&page=2",
+		strings.NewReader(`{"name": "an", "page": 3}`))
+	req.Header.Set("Content-Type", "application/json")
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.Get("id").String() != "42" {
+		t.Errorf("path should override query: id = %v", got.Get("id"))
+	}
+	if got.Get("page").Int() != 3 {
+		t.Errorf("body should override query: page = %v", got.Get("page"))
+	}
+	if got.Get("tag").Len() != 2 || got.Get("name").String() != "an" {
+		t.Errorf("Bind = %v", got)
+	}
+}
+
+func TestBind_FormPrecedence(t *testing.T) {
+	req := httptest.NewRequest("POST", "/?q=query", strings.NewReader("q=form&x=1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, err := Bind(req, Precedence(SourceBody, SourceQuery))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Get("q").String() != "query" || got.Get("x").String() != "1" {
+		t.Errorf("Bind = %v", got)
+	}
+}
+
+func TestBind_MultipartLimit(t *testing.T) {
+	var body strings.Builder
+	mw := multipart.NewWriter(&body)
+	fw, _ := mw.CreateFormFile("upload", "big.bin")
+	fw.Write(make([]byte, 4096))
+	mw.Close()
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(body.String()))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if _, err := Bind(req, MaxBodyBytes(1024)); err == nil {
+		t.Error("multipart body over MaxBodyBytes accepted")
+	}
+}
+
+func TestRespond_Negotiation(t *testing.T) {
+	v := New(map[string]any{"id": 1, "tags": []string{"a"}})
+
+	tests := []struct {
+		accept, wantType, wantBody string
+	}{
+		{"", "application/json", `{"id":1,"tags":["a"]}`},
+		{"text/html, application/yaml;q=0.9", "application/yaml", "id: 1\ntags:\n  - a\n"},
+		{"application/json;q=0.5, application/x-msgpack", "application/msgpack", "\x82\xa2id\x01\xa4tags\x91\xa1a"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		rec := httptest.NewRecorder()
+		if err := Respond(rec, req, v); err != nil {
+			t.Fatal(err)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != tt.wantType {
+			t.Errorf("Accept %q: Content-Type = %q, want %q", tt.accept, ct, tt.wantType)
+		}
+		if rec.Body.String() != tt.wantBody {
+			t.Errorf("Accept %q: body = %q, want %q", tt.accept, rec.Body.String(), tt.wantBody)
+		}
+	}
+}
+
+func TestFetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo":
+			body, _ := io.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.Header().Add("X-Seen", r.Method)
+			w.Header().Add("X-Seen", r.Header.Get("X-Token"))
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		case "/yaml":
+			Respond(w, r, New(map[string]any{"ok": true}))
+		case "/text":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			io.WriteString(w, "hello")
+		case "/empty":
+			w.WriteHeader(http.StatusNotFound)
+		case "/bad":
+			w.Header().Set("Content-Type", "application/json")
+			io.WriteString(w, "{")
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	got, err := Fetch(ctx, srv.URL+"/echo", FetchBody(New(map[string]any{"a": 1})), FetchHeader("X-Token", "t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Path("status").Int() != 201 || got.Path("body.a").Int() != 1 {
+		t.Errorf("Fetch = %v", got)
+	}
+	if seen := got.Path("headers.X-Seen"); seen.Len() != 2 || seen.Index(0).String() != "POST" || seen.Index(1).String() != "t" {
+		t.Errorf("X-Seen = %v", seen)
+	}
+
+	got, err = Fetch(ctx, srv.URL+"/yaml", FetchHeader("Accept", "application/yaml"))
+	if err != nil || !got.Path("body.ok").IsTrue() || got.Path("headers.Content-Type").String() != "application/yaml" {
+		t.Errorf("Fetch YAML = %v, %v", got, err)
+	}
+	if got, _ := Fetch(ctx, srv.URL+"/text"); got.Path("body").String() != "hello" {
+		t.Errorf("Fetch text = %v", got)
+	}
+	if got, err := Fetch(ctx, srv.URL+"/empty"); err != nil || got.Path("status").Int() != 404 || !got.Path("body").IsNil() {
+		t.Errorf("Fetch 404 = %v, %v", got, err)
+	}
+	if _, err := Fetch(ctx, srv.URL+"/bad"); err == nil {
+		t.Error("malformed JSON should be an error")
+	}
+	if _, err := Fetch(ctx, srv.URL+"/text", FetchMaxBytes(2)); err == nil {
+		t.Error("FetchMaxBytes should reject a longer body")
+	}
+}
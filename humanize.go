@@ -0,0 +1,106 @@
+package kit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+/* =============================================================================
+   HUMANIZE
+   ============================================================================= */
+
+// now is the reference time for relative output; tests replace it.
+var now = time.Now
+
+// Humanize renders v for people, in English:
+//
+//	Time      relative to now: "3 minutes ago", "in 2 days", "just now"
+//	Duration  its largest unit, rounded: "2 hours", "45 seconds"
+//	Number    compact with a metric suffix: "950", "1.5K", "3.2M"
+//	Bytes     its size: "1.5 kB"
+//
+// Other kinds return their Text.
+func (v Value) Humanize() Value { return v.HumanizeLocale("en") }
+
+// HumanizeLocale is Humanize using the relative-time wording of the Locale
+// registered for tag.
+func (v Value) HumanizeLocale(tag string) Value {
+	v = v.Force()
+	loc := lookupLocale(tag)
+	if loc.Span == nil {
+		loc = lookupLocale("en")
+	}
+	switch v.K {
+	case Time:
+		d := v.goTime().Sub(now())
+		if d > -45*time.Second && d < 45*time.Second {
+			return Value{K: String, V: loc.Now}
+		}
+		if d < 0 {
+			return Value{K: String, V: fmt.Sprintf(loc.Past, humanSpan(-d, loc))}
+		}
+		return Value{K: String, V: fmt.Sprintf(loc.Future, humanSpan(d, loc))}
+	case Duration:
+		d := time.Duration(int64(v.N))
+		if d < 0 {
+			return Value{K: String, V: "-" + humanSpan(-d, loc)}
+		}
+		return Value{K: String, V: humanSpan(d, loc)}
+	case Number:
+		return Value{K: String, V: compactNumber(v.N, 1000, []string{"", "K", "M", "B", "T"}, "")}
+	case Bytes:
+		return Value{K: String, V: HumanizeBytes(int64(len(v.Bytes())))}
+	}
+	return Value{K: String, V: v.Text()}
+}
+
+// HumanizeBytes formats a byte count with decimal (SI) units: "512 B",
+// "1.5 kB", "2 GB".
+func HumanizeBytes(n int64) string {
+	return compactNumber(float64(n), 1000, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB"}, " ")
+}
+
+// humanSpan picks the largest sensible unit for d, using thresholds that
+// avoid "0 hours" and "1 months" style output.
+func humanSpan(d time.Duration, loc *Locale) string {
+	const (
+		day   = 24 * time.Hour
+		year  = 31556952 * time.Second // mean Gregorian year
+		month = year / 12
+	)
+	round := func(unit time.Duration) int { return int(math.Round(float64(d) / float64(unit))) }
+	switch {
+	case d < 45*time.Second:
+		return loc.Span(max(round(time.Second), 1), Second)
+	case d < 45*time.Minute:
+		return loc.Span(round(time.Minute), Minute)
+	case d < 22*time.Hour:
+		return loc.Span(round(time.Hour), Hour)
+	case d < 26*day:
+		return loc.Span(round(day), Day)
+	case d < 320*day:
+		return loc.Span(max(round(month), 1), Month)
+	default:
+		return loc.Span(max(round(year), 1), Year)
+	}
+}
+
+// compactNumber scales n by base until it fits below base and formats it
+// with at most one decimal and the matching suffix.
+func compactNumber(n, base float64, suffixes []string, sep string) string {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	}
+	i := 0
+	for math.Abs(math.Round(n*10)/10) >= base && i < len(suffixes)-1 {
+		n /= base
+		i++
+	}
+	s := strconv.FormatFloat(math.Round(n*10)/10, 'f', -1, 64)
+	if suffixes[i] == "" {
+		return s
+	}
+	return s + sep + suffixes[i]
+}
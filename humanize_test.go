@@ -0,0 +1,59 @@
+package kit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValue_Humanize(t *testing.T) {
+	ref := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return ref }
+	defer func() { now = time.Now }()
+
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{New(ref.Add(-3 * time.Minute)).Humanize(), "3 minutes ago"},
+		{New(ref.Add(-time.Hour)).Humanize(), "1 hour ago"},
+		{New(ref.Add(49 * time.Hour)).Humanize(), "in 2 days"},
+		{New(ref.Add(-10 * time.Second)).Humanize(), "just now"},
+		{New(ref.AddDate(0, -3, 0)).Humanize(), "3 months ago"},
+		{New(ref.AddDate(2, 0, 0)).Humanize(), "in 2 years"},
+		{New(ref.Add(-3 * time.Minute)).HumanizeLocale("vi"), "3 phút trước"},
+		{New(ref.Add(49 * time.Hour)).HumanizeLocale("fr-FR"), "dans 2 jours"},
+		{New(ref.Add(-time.Hour)).HumanizeLocale("es"), "hace 1 hora"},
+		{New(ref.Add(-time.Hour)).HumanizeLocale("de"), "1 hour ago"},
+		{New(2 * time.Hour).Humanize(), "2 hours"},
+		{New(45 * time.Second).Humanize(), "1 minute"},
+		{New(-90 * time.Second).Humanize(), "-2 minutes"},
+		{New(950).Humanize(), "950"},
+		{New(1500).Humanize(), "1.5K"},
+		{New(3_240_000).Humanize(), "3.2M"},
+		{New(999_960).Humanize(), "1M"},
+		{New(make([]byte, 1500)).Humanize(), "1.5 kB"},
+		{New("text").Humanize(), "text"},
+	}
+	for i, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got.String(), tt.want)
+		}
+	}
+}
+
+func TestHumanizeBytes(t *testing.T) {
+	tests := []struct {
+		in   int64
+		want string
+	}{
+		{512, "512 B"},
+		{1500, "1.5 kB"},
+		{1_500_000_000, "1.5 GB"},
+		{2_000_000_000_000, "2 TB"},
+	}
+	for _, tt := range tests {
+		if got := HumanizeBytes(tt.in); got != tt.want {
+			t.Errorf("HumanizeBytes(%d) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package kit
+
+import (
+	"math"
+	"sort"
+)
+
+/* =============================================================================
+   SCHEMA INFERENCE
+   ============================================================================= */
+
+// maxEnum is the largest number of distinct Strings reported as an enum.
+const maxEnum = 10
+
+// shape accumulates what was observed at one position of the samples.
+type shape struct {
+	seen     int // observations, including Nil
+	kinds    map[Kind]int
+	nullable bool
+
+	strs     map[string]bool // nil once more than maxEnum were seen
+	strCount int
+
+	min, max float64
+	integer  bool
+
+	maps   int
+	fields map[string]*shape
+	items  *shape
+}
+
+func newShape() *shape {
+	return &shape{kinds: map[Kind]int{}, strs: map[string]bool{}, min: math.Inf(1), max: math.Inf(-1), integer: true}
+}
+
+func (s *shape) observe(v Value) {
+	v = v.Force()
+	s.seen++
+	if v.IsBlank() {
+		s.nullable = true
+		return
+	}
+	s.kinds[v.K]++
+	switch v.K {
+	case Number:
+		s.min, s.max = min(s.min, v.N), max(s.max, v.N)
+		s.integer = s.integer && v.N == math.Trunc(v.N)
+	case String:
+		s.strCount++
+		if s.strs != nil {
+			s.strs[v.String()] = true
+			if len(s.strs) > maxEnum {
+				s.strs = nil
+			}
+		}
+	case Map:
+		s.maps++
+		if s.fields == nil {
+			s.fields = map[string]*shape{}
+		}
+		for k, e := range v.mapping() {
+			f := s.fields[k]
+			if f == nil {
+				f = newShape()
+				s.fields[k] = f
+			}
+			f.observe(e)
+		}
+	case Array:
+		if s.items == nil {
+			s.items = newShape()
+		}
+		for _, e := range v.V.([]Value) {
+			s.items.observe(e)
+		}
+	}
+}
+
+func (s *shape) value() Value {
+	out := map[string]Value{}
+	kinds := make([]Kind, 0, len(s.kinds))
+	for k := range s.kinds {
+		kinds = append(kinds, k)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	switch len(kinds) {
+	case 0:
+		out["type"] = Value{K: String, V: Nil.String()}
+	case 1:
+		out["type"] = Value{K: String, V: kinds[0].String()}
+	default:
+		names := make([]Value, len(kinds))
+		for i, k := range kinds {
+			names[i] = Value{K: String, V: k.String()}
+		}
+		out["type"] = Value{K: Array, V: names}
+	}
+	if s.nullable && len(kinds) > 0 {
+		out["nullable"] = New(true)
+	}
+	if s.kinds[Number] > 0 {
+		out["min"] = New(s.min)
+		out["max"] = New(s.max)
+		out["integer"] = New(s.integer)
+	}
+	if s.strs != nil && len(s.strs) > 0 && len(s.strs) < s.strCount {
+		enum := make([]string, 0, len(s.strs))
+		for str := range s.strs {
+			enum = append(enum, str)
+		}
+		sort.Strings(enum)
+		out["enum"] = New(enum)
+	}
+	if s.fields != nil {
+		fields := make(map[string]Value, len(s.fields))
+		for k, f := range s.fields {
+			fv := f.value()
+			if f.seen < s.maps {
+				fv = fv.Set("optional", true)
+			}
+			fields[k] = fv
+		}
+		out["fields"] = Value{K: Map, V: fields}
+	}
+	if s.items != nil {
+		out["items"] = s.items.value()
+	}
+	return Value{K: Map, V: out}
+}
+
+// InferSchema describes the shape shared by sample documents as a Map:
+//
+//	type      the kind name, or an Array of names when kinds vary
+//	nullable  true when Nil was seen alongside other kinds
+//	min, max  the range of Numbers, and integer when all were integral
+//	enum      the sorted distinct Strings, when there were at most ten
+//	          and at least one repeated
+//	fields    for Maps, a description per key, with optional set on keys
+//	          missing from some samples
+//	items     for Arrays, a description of all elements together
+func InferSchema(samples ...Value) Value {
+	s := newShape()
+	for _, v := range samples {
+		s.observe(v)
+	}
+	return s.value()
+}
@@ -0,0 +1,30 @@
+package kit
+
+import "testing"
+
+func TestInferSchema(t *testing.T) {
+	got := InferSchema(
+		New(map[string]any{"id": 1, "status": "open", "tags": []any{"a"}, "score": 1.5}),
+		New(map[string]any{"id": 2, "status": "closed", "tags": []any{}, "note": nil}),
+		New(map[string]any{"id": 3, "status": "open", "tags": []any{"b", 2}, "note": "x"}),
+	)
+	want := New(map[string]any{
+		"type": "Map",
+		"fields": map[string]any{
+			"id":     map[string]any{"type": "Number", "min": 1, "max": 3, "integer": true},
+			"status": map[string]any{"type": "String", "enum": []any{"closed", "open"}},
+			"tags": map[string]any{"type": "Array", "items": map[string]any{
+				"type": []any{"Number", "String"}, "min": 2, "max": 2, "integer": true,
+			}},
+			"score": map[string]any{"type": "Number", "min": 1.5, "max": 1.5, "integer": false, "optional": true},
+			"note":  map[string]any{"type": "String", "nullable": true, "optional": true},
+		},
+	})
+	if !got.Equal(want) {
+		t.Errorf("InferSchema =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if got := InferSchema(); !got.Equal(New(map[string]any{"type": "Nil"})) {
+		t.Errorf("InferSchema() = %v", got)
+	}
+}
@@ -0,0 +1,149 @@
+// Command gen writes the Unicode tables of package kit from the Unicode
+// Character Database. It is run by go generate in the package directory:
+//
+//	go run ./internal/gen -tables norm -o normtables.go
+//
+// -ucd names the database, a URL or a local directory holding its files,
+// and defaults to the unicode.org copy of -version.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	version = flag.String("version", "14.0.0", "Unicode version")
+	ucd     = flag.String("ucd", "", "Unicode Character Database URL or directory (default unicode.org)")
+	tables  = flag.String("tables", "", "tables to write: norm or width")
+	out     = flag.String("o", "", "output file")
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("gen: ")
+	flag.Parse()
+	if *ucd == "" {
+		*ucd = "https://www.unicode.org/Public/" + *version + "/ucd"
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated from Unicode %s character data; DO NOT EDIT.\n\npackage kit\n", *version)
+	switch *tables {
+	case "norm":
+		writeNorm(&b)
+	case "width":
+		writeWidth(&b)
+	default:
+		log.Fatalf("unknown -tables %q", *tables)
+	}
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open returns the database file name.
+func open(name string) io.ReadCloser {
+	if !strings.HasPrefix(*ucd, "http://") && !strings.HasPrefix(*ucd, "https://") {
+		f, err := os.Open(filepath.Join(*ucd, name))
+		if err != nil {
+			log.Fatal(err)
+		}
+		return f
+	}
+	resp, err := http.Get(*ucd + "/" + name)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Fatalf("%s/%s: %s", *ucd, name, resp.Status)
+	}
+	return resp.Body
+}
+
+// parse calls fn with the semicolon-separated, trimmed fields of each
+// data line of the database file name.
+func parse(name string, fn func(fields []string)) {
+	r := open(name)
+	defer r.Close()
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fn(fields)
+	}
+	if err := sc.Err(); err != nil {
+		log.Fatalf("%s: %v", name, err)
+	}
+}
+
+// codeRange parses a code point or a "lo..hi" range.
+func codeRange(s string) (lo, hi rune) {
+	a, b, ok := strings.Cut(s, "..")
+	lo = code(a)
+	if !ok {
+		return lo, lo
+	}
+	return lo, code(b)
+}
+
+func code(s string) rune {
+	n, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		log.Fatalf("bad code point %q", s)
+	}
+	return rune(n)
+}
+
+// char is one UnicodeData.txt entry.
+type char struct {
+	category string
+	ccc      uint8
+	decomp   []rune // canonical decomposition mapping, if any
+}
+
+// unicodeData reads UnicodeData.txt, expanding its First/Last ranges.
+func unicodeData() map[rune]char {
+	chars := map[rune]char{}
+	var first rune = -1
+	parse("UnicodeData.txt", func(f []string) {
+		cp := code(f[0])
+		ccc, _ := strconv.Atoi(f[3])
+		c := char{category: f[2], ccc: uint8(ccc)}
+		if f[5] != "" && !strings.HasPrefix(f[5], "<") {
+			for _, d := range strings.Fields(f[5]) {
+				c.decomp = append(c.decomp, code(d))
+			}
+		}
+		switch {
+		case strings.HasSuffix(f[1], ", First>"):
+			first = cp
+		case strings.HasSuffix(f[1], ", Last>"):
+			for r := first; r <= cp; r++ {
+				chars[r] = char{category: c.category, ccc: c.ccc}
+			}
+		default:
+			chars[cp] = c
+		}
+	})
+	return chars
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// writeNorm writes the tables of normalize.go. Hangul syllables are left
+// out, as kit decomposes and composes them algorithmically.
+func writeNorm(w io.Writer) {
+	chars := unicodeData()
+	excluded := map[rune]bool{}
+	parse("DerivedNormalizationProps.txt", func(f []string) {
+		if f[1] == "Full_Composition_Exclusion" {
+			lo, hi := codeRange(f[0])
+			for r := lo; r <= hi; r++ {
+				excluded[r] = true
+			}
+		}
+	})
+
+	var runes []rune
+	for r := range chars {
+		if r < 0xAC00 || r > 0xD7A3 {
+			runes = append(runes, r)
+		}
+	}
+	slices.Sort(runes)
+
+	fmt.Fprintln(w, "// canonicalDecomp maps runes to their full canonical decomposition.")
+	fmt.Fprintln(w, "var canonicalDecomp = map[rune]string{")
+	for _, r := range runes {
+		if chars[r].decomp != nil {
+			fmt.Fprintf(w, "\t0x%04x: %s,\n", r, literal(decompose(chars, r)))
+		}
+	}
+	fmt.Fprintln(w, "}")
+
+	fmt.Fprintln(w, "\n// combiningClass holds the non-zero canonical combining classes.")
+	fmt.Fprintln(w, "var combiningClass = map[rune]uint8{")
+	for _, r := range runes {
+		if c := chars[r].ccc; c != 0 {
+			fmt.Fprintf(w, "\t0x%04x: %d,\n", r, c)
+		}
+	}
+	fmt.Fprintln(w, "}")
+
+	type pair struct {
+		a, b, r rune
+	}
+	var pairs []pair
+	for _, r := range runes {
+		if d := chars[r].decomp; len(d) == 2 && !excluded[r] {
+			pairs = append(pairs, pair{d[0], d[1], r})
+		}
+	}
+	slices.SortFunc(pairs, func(x, y pair) int {
+		if x.a != y.a {
+			return int(x.a - y.a)
+		}
+		return int(x.b - y.b)
+	})
+	fmt.Fprintln(w, "\n// canonicalComp maps starter and combining rune pairs to primary composites.")
+	fmt.Fprintln(w, "var canonicalComp = map[[2]rune]rune{")
+	for _, p := range pairs {
+		fmt.Fprintf(w, "\t{0x%04x, 0x%04x}: 0x%04x,\n", p.a, p.b, p.r)
+	}
+	fmt.Fprintln(w, "}")
+}
+
+// decompose returns the full canonical decomposition of r in canonical
+// order.
+func decompose(chars map[rune]char, r rune) []rune {
+	d := chars[r].decomp
+	if d == nil {
+		return []rune{r}
+	}
+	var out []rune
+	for _, x := range d {
+		out = append(out, decompose(chars, x)...)
+	}
+	// Stable-sort each run of non-starters by combining class.
+	for i := 0; i < len(out); {
+		j := i
+		for j < len(out) && chars[out[j]].ccc != 0 {
+			j++
+		}
+		slices.SortStableFunc(out[i:j], func(x, y rune) int { return int(chars[x].ccc) - int(chars[y].ccc) })
+		i = j + 1
+	}
+	return out
+}
+
+// literal quotes runes as a Go string of escapes.
+func literal(rs []rune) string {
+	s := `"`
+	for _, r := range rs {
+		if r > 0xffff {
+			s += fmt.Sprintf(`\U%08x`, r)
+		} else {
+			s += fmt.Sprintf(`\u%04x`, r)
+		}
+	}
+	return s + `"`
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"unicode"
+)
+
+// writeWidth writes the tables of RuneWidth in strings.go. Code points
+// EastAsianWidth.txt leaves out are narrow, as are unassigned ones outside
+// the ideograph ranges it lists as wide.
+func writeWidth(w io.Writer) {
+	chars := unicodeData()
+	eaw := map[rune]string{}
+	parse("EastAsianWidth.txt", func(f []string) {
+		lo, hi := codeRange(f[0])
+		for r := lo; r <= hi; r++ {
+			eaw[r] = f[1]
+		}
+	})
+	mark := func(r rune) bool {
+		switch chars[r].category {
+		case "Mn", "Me", "Cf":
+			return true
+		}
+		return false
+	}
+
+	fmt.Fprintln(w, "// zeroWidth lists rune ranges that occupy no terminal cell: combining")
+	fmt.Fprintln(w, "// marks, format characters and Hangul medial and final jamo.")
+	fmt.Fprintln(w, "var zeroWidth = [][2]rune{")
+	writeRanges(w, func(r rune) bool {
+		return r != 0xad && (mark(r) || r >= 0x1160 && r <= 0x11ff || r == 0x200b)
+	})
+	fmt.Fprintln(w, "}")
+
+	fmt.Fprintln(w, "\n// doubleWidth lists East Asian Wide and Fullwidth rune ranges.")
+	fmt.Fprintln(w, "var doubleWidth = [][2]rune{")
+	writeRanges(w, func(r rune) bool {
+		return (eaw[r] == "W" || eaw[r] == "F") && !mark(r)
+	})
+	fmt.Fprintln(w, "}")
+}
+
+// writeRanges writes the runs of code points in is as {lo, hi} pairs.
+func writeRanges(w io.Writer, is func(rune) bool) {
+	start := rune(-1)
+	for r := rune(0); r <= unicode.MaxRune+1; r++ {
+		in := r <= unicode.MaxRune && is(r)
+		switch {
+		case in && start < 0:
+			start = r
+		case !in && start >= 0:
+			fmt.Fprintf(w, "\t{0x%04x, 0x%04x},\n", start, r-1)
+			start = -1
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package websocket implements the server side of RFC 6455, enough to push
+// text messages: clients' messages are read and discarded, pings are
+// answered and a close ends the connection.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Opcodes of the frames a Conn sends and handles.
+const (
+	OpText  = 0x1
+	OpClose = 0x8
+	OpPing  = 0x9
+	OpPong  = 0xa
+)
+
+const (
+	maxControl = 125
+	maxMessage = 1 << 20 // client messages beyond this close the connection
+)
+
+// IsUpgrade reports whether r asks to switch to WebSocket.
+func IsUpgrade(r *http.Request) bool {
+	return headerHas(r.Header, "Connection", "upgrade") && headerHas(r.Header, "Upgrade", "websocket")
+}
+
+// headerHas reports whether a comma-separated header lists token.
+func headerHas(h http.Header, key, token string) bool {
+	for _, v := range h.Values(key) {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Conn is an upgraded connection. Its methods may be called concurrently.
+type Conn struct {
+	mu   sync.Mutex // serializes frames from writers and the read loop
+	conn net.Conn
+	w    *bufio.Writer
+	done chan struct{}
+}
+
+// Upgrade completes the handshake of r and starts reading client frames.
+// On a bad handshake it replies with an HTTP error and returns it.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if r.Method != http.MethodGet || key == "" || r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "bad WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("websocket: bad handshake")
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket unsupported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response cannot be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha1.Sum([]byte(key + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	c := &Conn{conn: conn, w: rw.Writer, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		c.readLoop(rw.Reader)
+	}()
+	return c, nil
+}
+
+// Done is closed once the client closes the connection or it fails.
+func (c *Conn) Done() <-chan struct{} { return c.done }
+
+// Close closes the underlying connection.
+func (c *Conn) Close() error { return c.conn.Close() }
+
+// Write sends payload in one frame with opcode op.
+func (c *Conn) Write(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hdr := []byte{0x80 | op}
+	switch n := len(payload); {
+	case n < 126:
+		hdr = append(hdr, byte(n))
+	case n <= 0xffff:
+		hdr = binary.BigEndian.AppendUint16(append(hdr, 126), uint16(n))
+	default:
+		hdr = binary.BigEndian.AppendUint64(append(hdr, 127), uint64(n))
+	}
+	c.w.Write(hdr)
+	c.w.Write(payload)
+	return c.w.Flush()
+}
+
+// readLoop consumes client frames until the connection closes or fails.
+func (c *Conn) readLoop(r *bufio.Reader) {
+	var hdr [14]byte
+	for {
+		if _, err := io.ReadFull(r, hdr[:2]); err != nil {
+			return
+		}
+		op, masked := hdr[0]&0x0f, hdr[1]&0x80 != 0
+		n := uint64(hdr[1] & 0x7f)
+		switch n {
+		case 126:
+			if _, err := io.ReadFull(r, hdr[2:4]); err != nil {
+				return
+			}
+			n = uint64(binary.BigEndian.Uint16(hdr[2:4]))
+		case 127:
+			if _, err := io.ReadFull(r, hdr[2:10]); err != nil {
+				return
+			}
+			n = binary.BigEndian.Uint64(hdr[2:10])
+		}
+		if !masked || n > maxMessage || op >= OpClose && n > maxControl {
+			c.Write(OpClose, binary.BigEndian.AppendUint16(nil, 1002)) // protocol error
+			return
+		}
+		var mask [4]byte
+		if _, err := io.ReadFull(r, mask[:]); err != nil {
+			return
+		}
+		payload := make([]byte, n)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return
+		}
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+		switch op {
+		case OpClose:
+			c.Write(OpClose, payload[:min(len(payload), 2)])
+			return
+		case OpPing:
+			if c.Write(OpPong, payload) != nil {
+				return
+			}
+		}
+	}
+}
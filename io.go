@@ -0,0 +1,72 @@
+package kit
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Reader returns an io.Reader over the content of v without copying for
+// String and Bytes kinds; other kinds are read through their Text form.
+func (v Value) Reader() io.Reader {
+	v = v.Force()
+	switch v.K {
+	case String:
+		return strings.NewReader(v.String())
+	case Bytes:
+		return bytes.NewReader(v.Bytes())
+	default:
+		return strings.NewReader(v.Text())
+	}
+}
+
+// WriteTo implements io.WriterTo, writing String and Bytes content directly
+// and other kinds through their Text form.
+func (v Value) WriteTo(w io.Writer) (int64, error) {
+	v = v.Force()
+	var n int
+	var err error
+	switch v.K {
+	case String:
+		n, err = io.WriteString(w, v.String())
+	case Bytes:
+		n, err = w.Write(v.Bytes())
+	default:
+		n, err = w.Write(v.Append(nil))
+	}
+	return int64(n), err
+}
+
+// Writer accumulates written data into a Bytes Value. The zero value is
+// ready to use.
+type Writer struct {
+	buf []byte
+}
+
+// NewWriter returns a Writer with the given initial capacity.
+func NewWriter(capacity int) *Writer {
+	return &Writer{buf: make([]byte, 0, capacity)}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *Writer) WriteString(s string) (int, error) {
+	w.buf = append(w.buf, s...)
+	return len(s), nil
+}
+
+func (w *Writer) WriteByte(c byte) error {
+	w.buf = append(w.buf, c)
+	return nil
+}
+
+func (w *Writer) Len() int { return len(w.buf) }
+
+// Value returns the bytes written so far as a Bytes Value without copying.
+// Later writes only append past its end, so the returned Value never changes.
+func (w *Writer) Value() Value {
+	return Value{K: Bytes, V: w.buf[:len(w.buf):len(w.buf)]}
+}
@@ -0,0 +1,39 @@
+package kit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestValue_Reader(t *testing.T) {
+	h := sha256.New()
+	if _, err := io.Copy(h, New("payload").Reader()); err != nil {
+		t.Fatal(err)
+	}
+	if want := sha256.Sum256([]byte("payload")); !bytes.Equal(h.Sum(nil), want[:]) {
+		t.Error("Reader streamed wrong content")
+	}
+
+	var buf bytes.Buffer
+	if _, err := New([]byte{1, 2}).WriteTo(&buf); err != nil || !bytes.Equal(buf.Bytes(), []byte{1, 2}) {
+		t.Errorf("WriteTo = %v, %v", buf.Bytes(), err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var w Writer
+	fmt.Fprintf(&w, "a=%d", 1)
+	first := w.Value()
+	w.WriteString(";b")
+	w.WriteByte('!')
+
+	if first.K != Bytes || string(first.Bytes()) != "a=1" {
+		t.Errorf("first = %q", first.Bytes())
+	}
+	if got := string(w.Value().Bytes()); got != "a=1;b!" {
+		t.Errorf("Value() = %q", got)
+	}
+}
@@ -0,0 +1,324 @@
+package kit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   JSON
+   Encoding is reflection-free for native kinds and deterministic: Map keys are
+   written in sorted order. Time is RFC 3339, Duration its Go string form and
+   Bytes base64, matching encoding/json conventions. NaN and ±Inf fail;
+   ReplaceNonFinite swaps them for null or strings first.
+   ============================================================================= */
+
+// NonFinite selects how ReplaceNonFinite represents NaN and ±Inf, which
+// JSON has no syntax for.
+type NonFinite uint8
+
+const (
+	NonFiniteError  NonFinite = iota // fail
+	NonFiniteNull                    // Nil
+	NonFiniteString                  // "NaN", "Infinity" or "-Infinity"
+)
+
+// ReplaceNonFinite returns a copy of v in which every NaN and ±Inf Number
+// is replaced as p says, or an error under NonFiniteError. JSON encoding
+// fails on such Numbers while YAML and MessagePack write them natively, so
+// apply it before whichever encoder needs a policy:
+//
+//	safe, err := v.ReplaceNonFinite(kit.NonFiniteNull)
+//	data, err := safe.MarshalJSON()
+func (v Value) ReplaceNonFinite(p NonFinite) (Value, error) {
+	v = v.Force()
+	switch v.K {
+	case Number:
+		if !math.IsNaN(v.N) && !math.IsInf(v.N, 0) {
+			return v, nil
+		}
+		switch p {
+		case NonFiniteNull:
+			return Value{K: Nil}, nil
+		case NonFiniteString:
+			return Value{K: String, V: nonFiniteString(v.N)}, nil
+		}
+		return Value{K: Invalid}, fmt.Errorf("kit: cannot encode %v", v.N)
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return v, nil
+		}
+		out := make([]Value, len(a))
+		for i, e := range a {
+			x, err := e.ReplaceNonFinite(p)
+			if err != nil {
+				return Value{K: Invalid}, err
+			}
+			out[i] = x
+		}
+		return Value{K: Array, V: out}, nil
+	case Map:
+		m := v.mapping()
+		if m == nil {
+			return v, nil
+		}
+		out := make(map[string]Value, len(m))
+		for k, e := range m {
+			x, err := e.ReplaceNonFinite(p)
+			if err != nil {
+				return Value{K: Invalid}, err
+			}
+			out[k] = x
+		}
+		return Value{K: Map, V: out}, nil
+	}
+	return v, nil
+}
+
+func nonFiniteString(n float64) string {
+	switch {
+	case math.IsNaN(n):
+		return "NaN"
+	case n > 0:
+		return "Infinity"
+	}
+	return "-Infinity"
+}
+
+// maxNesting bounds how deeply the decoders nest containers, as
+// encoding/json does, so hostile input cannot exhaust the stack.
+const maxNesting = 10000
+
+// FromJSON decodes a JSON document into a Value.
+func FromJSON(data []byte) (Value, error) {
+	var x any
+	if err := json.Unmarshal(data, &x); err != nil {
+		return Value{K: Invalid}, err
+	}
+	return fromJSONAny(x), nil
+}
+
+// fromJSONAny converts the output of encoding/json without reflection.
+func fromJSONAny(x any) Value {
+	switch t := x.(type) {
+	case nil:
+		return Value{K: Nil}
+	case bool:
+		return New(t)
+	case float64:
+		return Value{K: Number, N: t}
+	case string:
+		return Value{K: String, V: t}
+	case []any:
+		out := make([]Value, len(t))
+		for i, e := range t {
+			out[i] = fromJSONAny(e)
+		}
+		return Value{K: Array, V: out}
+	case map[string]any:
+		out := make(map[string]Value, len(t))
+		for k, e := range t {
+			out[k] = fromJSONAny(e)
+		}
+		return Value{K: Map, V: out}
+	default:
+		return New(x)
+	}
+}
+
+var (
+	jsonNumberType = reflect.TypeOf(json.Number(""))
+	rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+)
+
+// fromJSONNumber converts a json.Number, as produced by Decoder.UseNumber,
+// to a Number. An integer beyond ±2^53, which float64 would round, keeps
+// its digits alongside, so Int, Text and the encoders reproduce it
+// exactly. Text that does not parse as a float64 is a String.
+func fromJSONNumber(n json.Number) Value {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return Value{K: String, V: string(n)}
+	}
+	if math.Abs(f) > maxSafeInt {
+		if _, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+			return Value{K: Number, N: f, V: n}
+		}
+	}
+	return Value{K: Number, N: f}
+}
+
+// literal returns the exact digits kept by fromJSONNumber, if any.
+func (v Value) literal() (json.Number, bool) {
+	n, ok := v.V.(json.Number)
+	return n, ok && v.K == Number
+}
+
+// fromRawMessage decodes a json.RawMessage lazily, on first access, so
+// subtrees that are never read are never parsed. Malformed JSON gives an
+// Error Value; an empty message gives Nil.
+func fromRawMessage(raw []byte) Value {
+	if len(raw) == 0 {
+		return Value{K: Nil}
+	}
+	return Lazy(func() Value {
+		v, err := FromJSON(raw)
+		if err != nil {
+			return NewError(err)
+		}
+		return v
+	})
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v Value) MarshalJSON() ([]byte, error) {
+	return v.AppendJSON(nil)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	out, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+// AppendJSON appends the JSON encoding of v to b.
+func (v Value) AppendJSON(b []byte) ([]byte, error) {
+	if v.IsNilCollection() {
+		return append(b, "null"...), nil
+	}
+	switch v.K {
+	case Nil:
+		return append(b, "null"...), nil
+	case Number:
+		if n, ok := v.literal(); ok {
+			return append(b, n...), nil
+		}
+		return appendJSONNumber(b, v.N)
+	case Bool:
+		return strconv.AppendBool(b, v.N > 0), nil
+	case Time:
+		b = append(b, '"')
+		b = v.goTime().AppendFormat(b, time.RFC3339Nano)
+		return append(b, '"'), nil
+	case Duration:
+		return appendJSONString(b, time.Duration(int64(v.N)).String()), nil
+	case String:
+		return appendJSONString(b, v.String()), nil
+	case Bytes:
+		b = append(b, '"')
+		b = base64.StdEncoding.AppendEncode(b, v.Bytes())
+		return append(b, '"'), nil
+	case Array:
+		var err error
+		b = append(b, '[')
+		for i, e := range v.V.([]Value) {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = e.Force().AppendJSON(b); err != nil {
+				return b, err
+			}
+		}
+		return append(b, ']'), nil
+	case Map:
+		var err error
+		m := v.mapping()
+		b = append(b, '{')
+		for i, k := range sortedKeys(m) {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = appendJSONString(b, k)
+			b = append(b, ':')
+			if b, err = m[k].Force().AppendJSON(b); err != nil {
+				return b, err
+			}
+		}
+		return append(b, '}'), nil
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().AppendJSON(b)
+		}
+		return b, fmt.Errorf("kit: cannot encode %s as JSON", v.K)
+	case Invalid:
+		return b, fmt.Errorf("kit: cannot encode %s as JSON", v.K)
+	case Error:
+		return b, fmt.Errorf("kit: cannot encode Error as JSON: %w", v.Err())
+	default:
+		data, err := json.Marshal(v.V)
+		return append(b, data...), err
+	}
+}
+
+func appendJSONNumber(b []byte, n float64) ([]byte, error) {
+	if math.IsNaN(n) || math.IsInf(n, 0) {
+		return b, fmt.Errorf("kit: cannot encode %v as JSON", n)
+	}
+	if i := int64(n); n == float64(i) {
+		return strconv.AppendInt(b, i, 10), nil
+	}
+	if abs := math.Abs(n); abs < 1e-6 || abs >= 1e21 {
+		b = strconv.AppendFloat(b, n, 'e', -1, 64)
+		// Shorten a two-digit exponent like e-07 to e-7, as encoding/json does.
+		if l := len(b); l >= 4 && b[l-4] == 'e' && b[l-3] == '-' && b[l-2] == '0' {
+			b[l-2] = b[l-1]
+			b = b[:l-1]
+		}
+		return b, nil
+	}
+	return strconv.AppendFloat(b, n, 'f', -1, 64), nil
+}
+
+const hexDigits = "0123456789abcdef"
+
+func appendJSONString(b []byte, s string) []byte {
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			b = append(b, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				b = append(b, '\\', c)
+			case '\n':
+				b = append(b, '\\', 'n')
+			case '\r':
+				b = append(b, '\\', 'r')
+			case '\t':
+				b = append(b, '\\', 't')
+			default:
+				b = append(b, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			b = append(b, s[start:i]...)
+			b = append(b, `\ufffd`...)
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"')
+}
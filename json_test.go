@@ -0,0 +1,191 @@
+package kit
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValue_MarshalJSON(t *testing.T) {
+	v := New(map[string]any{
+		"b":    []any{1, 2.5, "x\n", nil},
+		"a":    true,
+		"when": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"raw":  []byte("hi"),
+	})
+	got, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"a":true,"b":[1,2.5,"x\n",null],"raw":"aGk=","when":"2024-01-02T03:04:05Z"}`
+	if string(got) != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+
+	if _, err := New(math.NaN()).MarshalJSON(); err == nil {
+		t.Error("NaN should not encode as JSON")
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	v, err := FromJSON([]byte(`{"id": 7, "tags": ["a", "b"], "meta": {"ok": false}, "none": null}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Get("id").Int() != 7 || v.At("tags", 1).String() != "b" || v.Path("meta.ok").IsTrue() || !v.Get("none").IsNil() {
+		t.Errorf("FromJSON decoded %v", v)
+	}
+
+	var w struct{ V Value }
+	if err := json.Unmarshal([]byte(`{"V": [1, {"x": "y"}]}`), &w); err != nil {
+		t.Fatal(err)
+	}
+	if w.V.At(1, "x").String() != "y" {
+		t.Errorf("UnmarshalJSON decoded %v", w.V)
+	}
+
+	if _, err := FromJSON([]byte(`{`)); err == nil {
+		t.Error("FromJSON should reject malformed input")
+	}
+}
+
+func TestJSONNumberAndRawMessage(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"id": 9007199254740993, "n": 42, "f": 1.5, "big": 1e400, "rest": {"x": [1, 2]}}`))
+	dec.UseNumber()
+	var doc struct {
+		ID   json.Number
+		N    json.Number
+		F    json.Number
+		Big  json.Number
+		Rest json.RawMessage
+	}
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatal(err)
+	}
+	v := New(&doc)
+	for path, want := range map[string]Value{
+		"ID":  New(9007199254740992), // compares as the rounded float64
+		"N":   New(42),
+		"F":   New(1.5),
+		"Big": New("1e400"),
+	} {
+		if got := v.Get(path); got.K != want.K || !got.Equal(want) {
+			t.Errorf("Get(%q) = %s, want %s", path, got, want)
+		}
+	}
+	id := v.Get("ID")
+	if b, err := id.MarshalJSON(); err != nil || string(b) != "9007199254740993" ||
+		id.Text() != "9007199254740993" || id.Int() != 9007199254740993 {
+		t.Errorf("ID = %s %v, %q, %d: want the exact digits", b, err, id.Text(), id.Int())
+	}
+	if sum := id.Add(New(0)); sum.Text() != "9007199254740992" {
+		t.Errorf("ID + 0 = %s, want the rounded float64", sum.Text())
+	}
+	rest := v.Get("Rest")
+	if !rest.IsLazy() || rest.Path("x.1").Int() != 2 {
+		t.Errorf("Rest = %s, want a lazy Map", rest)
+	}
+	if got := New(json.RawMessage(`{`)).Force(); !got.IsError() {
+		t.Errorf("malformed RawMessage = %s, want an Error", got)
+	}
+	if got := New(json.RawMessage(nil)); got.K != Nil {
+		t.Errorf("empty RawMessage = %s, want Nil", got)
+	}
+
+	var x any
+	dec = json.NewDecoder(strings.NewReader(`[7, 0.25]`))
+	dec.UseNumber()
+	if err := dec.Decode(&x); err != nil {
+		t.Fatal(err)
+	}
+	if got := New(x); !got.Equal(New([]any{7, 0.25})) {
+		t.Errorf("New(UseNumber output) = %s", got)
+	}
+
+	var back struct {
+		N    json.Number
+		Rest json.RawMessage
+	}
+	if err := New(map[string]any{"N": 3, "Rest": map[string]any{"a": true}}).Bind(&back); err != nil ||
+		back.N != "3" || string(back.Rest) != `{"a":true}` {
+		t.Errorf("Bind = %+v, %v", back, err)
+	}
+}
+
+func TestNonFinite(t *testing.T) {
+	v := New([]any{math.NaN(), math.Inf(1), math.Inf(-1), 1})
+
+	if _, err := v.MarshalJSON(); err == nil {
+		t.Error("JSON should fail on NaN")
+	}
+	if _, err := v.ReplaceNonFinite(NonFiniteError); err == nil {
+		t.Error("NonFiniteError should fail on NaN")
+	}
+	for p, want := range map[NonFinite]string{
+		NonFiniteNull:   "[null,null,null,1]",
+		NonFiniteString: `["NaN","Infinity","-Infinity",1]`,
+	} {
+		safe, err := v.ReplaceNonFinite(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err := safe.MarshalJSON(); err != nil || string(data) != want {
+			t.Errorf("policy %d: JSON = %s, %v", p, data, err)
+		}
+		if _, err := safe.AppendMsgPack(nil); err != nil {
+			t.Errorf("policy %d: MessagePack: %v", p, err)
+		}
+	}
+	if y, _ := v.AppendYAML(nil); string(y) != "- .nan\n- .inf\n- -.inf\n- 1\n" {
+		t.Errorf("YAML = %q", y)
+	}
+	if err := New(math.NaN()).EncodeTokens(func(json.Token) error { return nil }); err == nil {
+		t.Error("EncodeTokens should fail on NaN")
+	}
+
+	nan := New(math.NaN())
+	if !nan.Equal(New(math.NaN())) || nan.Truthy() || !nan.Less(New(math.Inf(-1))) || New(0).Less(nan) {
+		t.Error("NaN comparison semantics")
+	}
+	if got := New([]any{math.NaN(), 1, math.NaN()}).Unique().Len(); got != 2 {
+		t.Errorf("Unique with NaN = %d elements", got)
+	}
+}
+
+func TestNilCollections(t *testing.T) {
+	type payload struct{ Tags []string }
+	var nilSlice []string
+	var nilMap map[string]int
+	v := New(map[string]any{
+		"nil_slice":   nilSlice,
+		"empty_slice": []string{},
+		"nil_map":     nilMap,
+		"empty_map":   map[string]int{},
+		"nil_bytes":   []byte(nil),
+	})
+	data, err := v.MarshalJSON()
+	want := `{"empty_map":{},"empty_slice":[],"nil_bytes":null,"nil_map":null,"nil_slice":null}`
+	if err != nil || string(data) != want {
+		t.Errorf("MarshalJSON = %s, %v", data, err)
+	}
+	if !v.Get("nil_slice").IsNilCollection() || v.Get("empty_slice").IsNilCollection() || New(nil).IsNilCollection() {
+		t.Error("IsNilCollection")
+	}
+	if v.Get("nil_slice").Len() != 0 || !v.Get("nil_slice").Equal(v.Get("empty_slice")) {
+		t.Error("a nil Array should behave as empty")
+	}
+	if got := v.Get("nil_map").Export().(map[string]any); got != nil {
+		t.Errorf("Export of nil Map = %#v", got)
+	}
+	if y, _ := v.Get("nil_slice").AppendYAML(nil); string(y) != "null\n" {
+		t.Errorf("YAML = %q", y)
+	}
+	if m, _ := v.Get("nil_map").AppendMsgPack(nil); len(m) != 1 || m[0] != 0xc0 {
+		t.Errorf("MessagePack = %x", m)
+	}
+	if data, _ := New(payload{}).Get("Tags").MarshalJSON(); string(data) != "null" {
+		t.Errorf("struct field = %s", data)
+	}
+}
@@ -0,0 +1,78 @@
+package kittest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kitwork/kit"
+)
+
+// update is namespaced so that test binaries defining their own -update
+// flag can still import kittest.
+var update = flag.Bool("kittest.update", false, "rewrite kittest golden files instead of comparing against them")
+
+// AssertGolden compares got with the golden file testdata/<name>.golden,
+// which holds its canonical JSON (kit.Value.CanonicalJSON) indented for
+// review. Run the tests with -kittest.update to write the files instead; new
+// golden files are only ever created that way. Slashes in name, as in
+// t.Name() for subtests, become directories.
+//
+//	kittest.AssertGolden(t, t.Name(), pipeline.Run(input))
+func AssertGolden(t testing.TB, name string, got kit.Value) bool {
+	t.Helper()
+	data, err := got.CanonicalJSON()
+	if err != nil {
+		t.Errorf("golden %s: %v", name, err)
+		return false
+	}
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		t.Errorf("golden %s: %v", name, err)
+		return false
+	}
+	buf.WriteByte('\n')
+
+	path := filepath.Join("testdata", filepath.FromSlash(name)+".golden")
+	if *update {
+		err := os.MkdirAll(filepath.Dir(path), 0o755)
+		if err == nil {
+			err = os.WriteFile(path, buf.Bytes(), 0o644)
+		}
+		if err != nil {
+			t.Errorf("golden %s: %v", name, err)
+			return false
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("golden %s: %s does not exist; run the tests with -kittest.update to create it", name, path)
+		return false
+	}
+	if err != nil {
+		t.Errorf("golden %s: %v", name, err)
+		return false
+	}
+	if bytes.Equal(want, buf.Bytes()) {
+		return true
+	}
+
+	// Compare decoded documents so the report lists changed paths; fall
+	// back to the raw text when the golden file is not valid JSON.
+	wv, werr := kit.FromJSON(want)
+	gv, _ := kit.FromJSON(buf.Bytes())
+	if diff := kit.DiffString(wv, gv); werr == nil && diff != "" {
+		t.Errorf("golden %s differs from %s (-want +got):\n%s", name, path, diff)
+	} else {
+		t.Errorf("golden %s differs from %s:\nwant:\n%s\ngot:\n%s", name, path, strings.TrimSpace(string(want)), strings.TrimSpace(buf.String()))
+	}
+	return false
+}
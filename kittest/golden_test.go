@@ -0,0 +1,44 @@
+package kittest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kitwork/kit"
+)
+
+func TestAssertGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	v := kit.New(map[string]any{"b": []any{1, "x"}, "a": true})
+
+	r := &recorder{}
+	if AssertGolden(r, "pipeline/out", v) || !strings.Contains(r.output(), "-kittest.update") {
+		t.Fatalf("missing golden file: %s", r.output())
+	}
+
+	*update = true
+	r = &recorder{}
+	ok := AssertGolden(r, "pipeline/out", v)
+	*update = false
+	if !ok {
+		t.Fatalf("update failed: %s", r.output())
+	}
+	data, err := os.ReadFile("testdata/pipeline/out.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"a\": true,\n  \"b\": [\n    1,\n    \"x\"\n  ]\n}\n"; string(data) != want {
+		t.Errorf("golden file =\n%s", data)
+	}
+
+	r = &recorder{}
+	if !AssertGolden(r, "pipeline/out", v) {
+		t.Errorf("unchanged value failed: %s", r.output())
+	}
+
+	r = &recorder{}
+	if AssertGolden(r, "pipeline/out", v.Set("a", false)) || !strings.Contains(r.output(), "+ a: false") {
+		t.Errorf("changed value: %s", r.output())
+	}
+}
@@ -0,0 +1,163 @@
+// Package kittest provides test assertions for kit Values that report
+// failures as structural diffs instead of two unreadable dumps.
+//
+//	kittest.AssertEqual(t, want, got)
+//	kittest.AssertSubset(t, kit.New(map[string]any{"status": "ok"}), resp)
+//	kittest.Assert(t, resp,
+//		kittest.Path("items", kittest.Len(3)),
+//		kittest.Path("items.0.id", kittest.Kind(kit.Number)),
+//	)
+//
+// AssertGolden compares a Value with a snapshot under testdata that the
+// -kittest.update flag rewrites.
+//
+// Assertions mark the test failed and continue; they report whether they
+// passed, so a caller can stop early with if !AssertEqual(...) { return }.
+package kittest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kitwork/kit"
+)
+
+// AssertEqual checks that got is Equal to want and otherwise reports the
+// differences, "-" lines from want and "+" lines from got.
+func AssertEqual(t testing.TB, want, got kit.Value) bool {
+	t.Helper()
+	if want.Equal(got) {
+		return true
+	}
+	t.Errorf("values differ (-want +got):\n%s", kit.DiffString(want, got))
+	return false
+}
+
+// AssertSubset checks that every entry of want is present in got with an
+// Equal value. Maps in got may carry extra keys at any depth; Arrays must
+// match element for element.
+func AssertSubset(t testing.TB, want, got kit.Value) bool {
+	t.Helper()
+	if want.EqualApprox(got, 0, kit.AllowExtra()) {
+		return true
+	}
+	var sb strings.Builder
+	for _, c := range kit.Diff(want, got) {
+		if c.Op == kit.Added && !isArrayIndex(c.Path, want) {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s: want %s, got %s\n", pathName(c.Path), describe(c.Old), describe(c.New))
+	}
+	t.Errorf("value is not a superset of want:\n%s", sb.String())
+	return false
+}
+
+// isArrayIndex reports whether path ends in an index of an Array in want,
+// where an added element is a mismatch rather than an extra key.
+func isArrayIndex(path string, want kit.Value) bool {
+	i := strings.LastIndexByte(path, '.')
+	if i < 0 {
+		return want.K == kit.Array
+	}
+	return want.Path(path[:i]).K == kit.Array
+}
+
+// Matcher checks one property of a Value and describes the mismatch.
+type Matcher func(v kit.Value) error
+
+// Assert applies every matcher to v and reports each one that fails.
+func Assert(t testing.TB, v kit.Value, matchers ...Matcher) bool {
+	t.Helper()
+	ok := true
+	for _, m := range matchers {
+		if err := m(v); err != nil {
+			t.Error(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// Kind matches Values of kind k.
+func Kind(k kit.Kind) Matcher {
+	return func(v kit.Value) error {
+		if v.K != k {
+			return fmt.Errorf("kind is %s, want %s", v.K, k)
+		}
+		return nil
+	}
+}
+
+// Equals matches Values Equal to kit.New(x).
+func Equals(x any) Matcher {
+	want := kit.New(x)
+	return func(v kit.Value) error {
+		if !want.Equal(v) {
+			return fmt.Errorf("got %s, want %s", describe(v), describe(want))
+		}
+		return nil
+	}
+}
+
+// Len matches Strings, Bytes, Arrays and Maps of length n.
+func Len(n int) Matcher {
+	return func(v kit.Value) error {
+		if v.Len() != n {
+			return fmt.Errorf("length is %d, want %d", v.Len(), n)
+		}
+		return nil
+	}
+}
+
+// Exists matches Values in which path is present, even as null.
+func Exists(path string) Matcher {
+	return func(v kit.Value) error {
+		if !v.Exists(path) {
+			return fmt.Errorf("%s: missing", pathName(path))
+		}
+		return nil
+	}
+}
+
+// Path applies every matcher to the Value at path. A missing path fails
+// without running them.
+func Path(path string, matchers ...Matcher) Matcher {
+	return func(v kit.Value) error {
+		if !v.Exists(path) {
+			return fmt.Errorf("%s: missing", pathName(path))
+		}
+		x := v.Path(path)
+		var msgs []string
+		for _, m := range matchers {
+			if err := m(x); err != nil {
+				msgs = append(msgs, err.Error())
+			}
+		}
+		if msgs != nil {
+			return fmt.Errorf("%s: %s", pathName(path), strings.Join(msgs, "; "))
+		}
+		return nil
+	}
+}
+
+func pathName(path string) string {
+	if path == "" {
+		return "(root)"
+	}
+	return path
+}
+
+func describe(v kit.Value) string {
+	switch v.K {
+	case kit.Invalid:
+		return "nothing"
+	case kit.String:
+		return fmt.Sprintf("%q", v.Text())
+	case kit.Array, kit.Map:
+		if b, err := v.MarshalJSON(); err == nil {
+			return string(b)
+		}
+	}
+	return fmt.Sprintf("%s(%v)", v.K, v)
+}
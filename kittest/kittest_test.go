@@ -0,0 +1,89 @@
+package kittest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/kitwork/kit"
+)
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	msgs []string
+}
+
+func (r *recorder) Helper()           {}
+func (r *recorder) Error(args ...any) { r.msgs = append(r.msgs, fmt.Sprint(args...)) }
+func (r *recorder) Errorf(format string, a ...any) {
+	r.msgs = append(r.msgs, fmt.Sprintf(format, a...))
+}
+
+func (r *recorder) output() string { return strings.Join(r.msgs, "\n") }
+
+func TestAssertEqual(t *testing.T) {
+	want := kit.New(map[string]any{"id": 1, "tags": []any{"a", "b"}})
+
+	r := &recorder{}
+	if !AssertEqual(r, want, kit.New(map[string]any{"id": 1, "tags": []any{"a", "b"}})) || len(r.msgs) != 0 {
+		t.Errorf("equal values failed: %s", r.output())
+	}
+
+	r = &recorder{}
+	if AssertEqual(r, want, kit.New(map[string]any{"id": 2, "tags": []any{"a"}})) {
+		t.Fatal("different values passed")
+	}
+	for _, line := range []string{"- id: 1", "+ id: 2", `- tags.1: "b"`} {
+		if !strings.Contains(r.output(), line) {
+			t.Errorf("report lacks %q:\n%s", line, r.output())
+		}
+	}
+}
+
+func TestAssertSubset(t *testing.T) {
+	got := kit.New(map[string]any{"status": "ok", "user": map[string]any{"id": 7, "name": "ann"}, "list": []any{1, 2}})
+
+	r := &recorder{}
+	if !AssertSubset(r, kit.New(map[string]any{"user": map[string]any{"id": 7}}), got) {
+		t.Errorf("subset failed: %s", r.output())
+	}
+
+	r = &recorder{}
+	want := kit.New(map[string]any{"status": "error", "user": map[string]any{"email": "x"}, "list": []any{1}})
+	if AssertSubset(r, want, got) {
+		t.Fatal("mismatch passed")
+	}
+	out := r.output()
+	for _, line := range []string{`status: want "error", got "ok"`, `user.email: want "x", got nothing`, "list.1: want nothing, got Number(2)"} {
+		if !strings.Contains(out, line) {
+			t.Errorf("report lacks %q:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, "user.name") {
+		t.Errorf("extra key reported:\n%s", out)
+	}
+}
+
+func TestAssert(t *testing.T) {
+	v := kit.New(map[string]any{"items": []any{map[string]any{"id": 1}}, "name": "x"})
+
+	r := &recorder{}
+	if !Assert(r, v, Kind(kit.Map), Exists("name"), Path("items", Len(1)), Path("items.0.id", Kind(kit.Number), Equals(1))) {
+		t.Errorf("matchers failed: %s", r.output())
+	}
+
+	r = &recorder{}
+	if Assert(r, v, Kind(kit.Array), Path("items.0.id", Equals(2)), Path("nope", Len(0)), Exists("a.b")) {
+		t.Fatal("mismatches passed")
+	}
+	want := []string{"kind is Map, want Array", "items.0.id: got Number(1), want Number(2)", "nope: missing", "a.b: missing"}
+	if len(r.msgs) != len(want) {
+		t.Fatalf("reports = %q", r.msgs)
+	}
+	for i := range want {
+		if r.msgs[i] != want[i] {
+			t.Errorf("report %d = %q, want %q", i, r.msgs[i], want[i])
+		}
+	}
+}
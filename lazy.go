@@ -0,0 +1,48 @@
+package kit
+
+import "sync"
+
+/* =============================================================================
+   LAZY VALUES
+   ============================================================================= */
+
+type lazy struct {
+	once sync.Once
+	fn   func() Value
+	v    Value
+}
+
+func (l *lazy) get() Value {
+	l.once.Do(func() {
+		l.v = l.fn().Force()
+		l.fn = nil
+	})
+	return l.v
+}
+
+// Lazy returns a Value whose content is computed by fn on first access and
+// cached afterwards; concurrent first accesses run fn exactly once.
+//
+// The lazy Value has kind Func until forced. Navigation (Get, Index, At,
+// Path), Len, Text, Equal and Truthy resolve it transparently, including
+// when it sits inside a Map or Array; call Force before reading K or N
+// directly.
+func Lazy(fn func() Value) Value {
+	return Value{K: Func, V: &lazy{fn: fn}}
+}
+
+// Force resolves a lazy Value. Any other Value is returned unchanged.
+func (v Value) Force() Value {
+	if v.K == Func {
+		if l, ok := v.V.(*lazy); ok {
+			return l.get()
+		}
+	}
+	return v
+}
+
+// IsLazy reports whether v is a lazy Value, forced or not.
+func (v Value) IsLazy() bool {
+	_, ok := v.V.(*lazy)
+	return v.K == Func && ok
+}
@@ -0,0 +1,49 @@
+package kit
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLazy_ComputesOnce(t *testing.T) {
+	var calls atomic.Int32
+	v := Lazy(func() Value {
+		calls.Add(1)
+		return New(map[string]any{"host": "db", "ports": []int{1, 2}})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = v.Get("host")
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn called %d times, want 1", n)
+	}
+	if v.Path("ports.1").Int() != 2 || v.Len() != 2 {
+		t.Error("navigation did not resolve lazy Value")
+	}
+}
+
+func TestLazy_NestedInMap(t *testing.T) {
+	m := New(map[string]any{"n": Lazy(func() Value { return New(42) })})
+
+	if got := m.Get("n"); got.K != Number || got.Int() != 42 {
+		t.Errorf("Get returned %v/%v, want resolved Number 42", got.K, got.N)
+	}
+	if m.At("n").Text() != "42" || !m.Equal(New(map[string]any{"n": 42})) {
+		t.Error("lazy entry not resolved transparently")
+	}
+	if !Lazy(func() Value { return New(false) }).IsLazy() {
+		t.Error("IsLazy() = false")
+	}
+	if Lazy(func() Value { return New(false) }).Truthy() {
+		t.Error("Truthy should reflect the resolved Value")
+	}
+}
@@ -0,0 +1,213 @@
+package kit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   LOADING AND SAVING
+   One-line decoding of files in any format kit reads, from disk or from an
+   fs.FS such as embed.FS, and crash-safe writes of whole trees.
+   ============================================================================= */
+
+// Format is a serialization format known to Decode, Load and Save.
+type Format uint8
+
+const (
+	FormatAuto Format = iota // detect from the content
+	FormatJSON
+	FormatYAML
+	FormatTOML
+	FormatCSV
+	FormatMsgPack
+)
+
+var formatNames = [...]string{"auto", "json", "yaml", "toml", "csv", "msgpack"}
+
+func (f Format) String() string {
+	if int(f) < len(formatNames) {
+		return formatNames[f]
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// FormatOf returns the Format for the extension of a file name: .json,
+// .yaml or .yml, .toml, .csv, .msgpack or .mpk. Other names give
+// FormatAuto.
+func FormatOf(name string) Format {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".toml":
+		return FormatTOML
+	case ".csv":
+		return FormatCSV
+	case ".msgpack", ".mpk":
+		return FormatMsgPack
+	}
+	return FormatAuto
+}
+
+// Decode decodes data in format f. FormatAuto sniffs the content: invalid
+// UTF-8 is MessagePack, a document starting with { or [ that parses as JSON
+// is JSON, one whose first line is a [table] header or key = value is TOML,
+// and anything else YAML. CSV is never detected.
+func Decode(data []byte, f Format) (Value, error) {
+	if f == FormatAuto {
+		f = sniffFormat(data)
+	}
+	switch f {
+	case FormatJSON:
+		return FromJSON(data)
+	case FormatYAML:
+		return FromYAML(data)
+	case FormatTOML:
+		return FromTOML(data)
+	case FormatCSV:
+		return FromCSV(bytes.NewReader(data))
+	case FormatMsgPack:
+		return DecodeMsgPack(data)
+	}
+	return Value{K: Invalid}, fmt.Errorf("kit: unknown format %s", f)
+}
+
+func sniffFormat(data []byte) Format {
+	if !utf8.Valid(data) {
+		return FormatMsgPack
+	}
+	text := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\ufeff")))
+	if len(text) > 0 && (text[0] == '{' || text[0] == '[') && json.Valid(text) {
+		return FormatJSON
+	}
+	for _, line := range strings.Split(string(text), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' && strings.HasSuffix(line, "]") && !strings.ContainsAny(line, ",{") {
+			return FormatTOML
+		}
+		if key, _, ok := strings.Cut(line, "="); ok && line[0] != '-' && !strings.ContainsAny(key, ":{[") && strings.TrimSpace(key) != "" {
+			return FormatTOML
+		}
+		break
+	}
+	return FormatYAML
+}
+
+// Load reads and decodes the file at name, choosing the format from its
+// extension and sniffing the content when the extension is unknown.
+func Load(name string) (Value, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	return decodeFile(name, data)
+}
+
+// LoadFS is Load reading from fsys, such as an embed.FS:
+//
+//	//go:embed testdata
+//	var fixtures embed.FS
+//
+//	users, err := kit.LoadFS(fixtures, "testdata/users.yaml")
+func LoadFS(fsys fs.FS, name string) (Value, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	return decodeFile(name, data)
+}
+
+func decodeFile(name string, data []byte) (Value, error) {
+	v, err := Decode(data, FormatOf(name))
+	if err != nil {
+		return Value{K: Invalid}, &fs.PathError{Op: "decode", Path: name, Err: err}
+	}
+	return v, nil
+}
+
+// Encode encodes v in format f: indented JSON, YAML or MessagePack. TOML,
+// CSV and FormatAuto cannot be written.
+func (v Value) Encode(f Format) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		data, err := v.AppendJSON(nil)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, data, "", "  "); err != nil {
+			return nil, err
+		}
+		return append(buf.Bytes(), '\n'), nil
+	case FormatYAML:
+		return v.AppendYAML(nil)
+	case FormatMsgPack:
+		return v.AppendMsgPack(nil)
+	}
+	return nil, fmt.Errorf("kit: cannot encode %s", f)
+}
+
+// Save writes v to the file at name atomically: it is encoded into a
+// temporary file in the same directory, synced, and renamed over name, so
+// a crash leaves either the old file or the new one, never a torn write.
+// FormatAuto picks the format from the extension and falls back to JSON.
+// An existing file keeps its permissions; a new one gets 0644. Load reads
+// the file back; use MessagePack to keep Time, Duration and Bytes kinds
+// across the round trip.
+func (v Value) Save(name string, f Format) error {
+	if f == FormatAuto {
+		if f = FormatOf(name); f == FormatAuto {
+			f = FormatJSON
+		}
+	}
+	data, err := v.Encode(f)
+	if err != nil {
+		return err
+	}
+	mode := fs.FileMode(0o644)
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(name)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly once renamed
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return err
+	}
+	// Persist the rename itself; not every platform can sync a directory.
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package kit
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadFS(t *testing.T) {
+	want := New(map[string]any{"name": "kit", "port": 8080})
+	mp, _ := want.AppendMsgPack(nil)
+	fsys := fstest.MapFS{
+		"a.json":    {Data: []byte(`{"name": "kit", "port": 8080}`)},
+		"a.yml":     {Data: []byte("name: kit\nport: 8080\n")},
+		"a.toml":    {Data: []byte("name = \"kit\"\nport = 8080\n")},
+		"a.msgpack": {Data: mp},
+		"json.txt":  {Data: []byte(` {"name": "kit", "port": 8080}`)},
+		"yaml.txt":  {Data: []byte("# settings\nname: kit\nport: 8080\n")},
+		"toml.txt":  {Data: []byte("# settings\nname = \"kit\"\nport = 8080\n")},
+		"table.txt": {Data: []byte("[server]\nport = 1\n")},
+		"mp.bin":    {Data: mp},
+		"rows.csv":  {Data: []byte("name,port\nkit,8080\n")},
+		"bad.json":  {Data: []byte("{")},
+	}
+	for _, name := range []string{"a.json", "a.yml", "a.toml", "a.msgpack", "json.txt", "yaml.txt", "toml.txt", "mp.bin"} {
+		got, err := LoadFS(fsys, name)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("LoadFS(%q) = %v, %v", name, got, err)
+		}
+	}
+	if got, _ := LoadFS(fsys, "table.txt"); got.Path("server.port").Int() != 1 {
+		t.Errorf("TOML table sniffing: %v", got)
+	}
+	if got, _ := LoadFS(fsys, "rows.csv"); !got.Index(0).Equal(New(map[string]any{"name": "kit", "port": "8080"})) {
+		t.Errorf("CSV: %v", got)
+	}
+
+	var pe *fs.PathError
+	if _, err := LoadFS(fsys, "bad.json"); !errors.As(err, &pe) || pe.Path != "bad.json" {
+		t.Errorf("decode error should name the file: %v", err)
+	}
+	if _, err := LoadFS(fsys, "missing.json"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "cfg.YAML")
+	if err := os.WriteFile(name, []byte("a: [1, 2]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(name)
+	if err != nil || got.Path("a.1").Int() != 2 {
+		t.Errorf("Load = %v, %v", got, err)
+	}
+	if FormatOf("x.Yml") != FormatYAML || FormatOf("x") != FormatAuto || FormatMsgPack.String() != "msgpack" {
+		t.Error("FormatOf or Format.String is wrong")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	v := New(map[string]any{"at": time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "ttl": time.Minute, "n": []int{1, 2}})
+
+	for _, name := range []string{"s.json", "s.yaml", "s.msgpack", "s.state"} {
+		p := filepath.Join(dir, name)
+		if err := v.Save(p, FormatAuto); err != nil {
+			t.Fatalf("Save(%q): %v", name, err)
+		}
+		got, err := Load(p)
+		if err != nil || got.Path("n.1").Int() != 2 {
+			t.Errorf("Load(%q) = %v, %v", name, got, err)
+		}
+	}
+	if got, _ := Load(filepath.Join(dir, "s.msgpack")); !got.Equal(v) {
+		t.Errorf("MessagePack should round-trip exactly: %v", got)
+	}
+
+	p := filepath.Join(dir, "s.json")
+	if err := os.Chmod(p, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := New(1).Save(p, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ := os.Stat(p); info.Mode().Perm() != 0o600 {
+		t.Errorf("Save changed the mode to %v", info.Mode())
+	}
+	if err := v.Save(filepath.Join(dir, "s.toml"), FormatAuto); err == nil {
+		t.Error("TOML cannot be written")
+	}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".tmp") {
+			t.Errorf("temporary file left behind: %s", e.Name())
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package kit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/* =============================================================================
+   LOCALES
+   Month and weekday names used by FormatLocale, relative-time wording
+   used by HumanizeLocale, number marks used by FormatNumber and
+   FormatCurrency, the alphabets used by Collator and the plural rules
+   used by Message. Tags are matched exactly first, then by base language
+   ("pt-BR" falls back to "pt"), then English.
+   ============================================================================= */
+
+// Locale holds the calendar names of a language. Days start on Sunday, as
+// time.Weekday does.
+type Locale struct {
+	Months      [12]string
+	ShortMonths [12]string
+	Days        [7]string
+	ShortDays   [7]string
+
+	// Span renders a count of units such as "3 minutes"; Past and Future are
+	// fmt patterns wrapping a span ("%s ago", "in %s") and Now is used for
+	// times within a minute of the present. A Locale without Span humanizes
+	// in English.
+	Span         func(n int, unit Unit) string
+	Past, Future string
+	Now          string
+
+	// Decimal and Group are the decimal mark and the thousands separator;
+	// a Locale without Decimal formats numbers in English. Currency places
+	// the symbol "¤" around the number "#", as in "¤#" or "#\u00a0¤".
+	Decimal, Group string
+	Currency       string
+
+	// Collation lists the letters that sort as letters of their own rather
+	// than as accented variants, in groups of space-separated letters in
+	// alphabetical order around one unaccented letter, such as "o ô ơ" or
+	// "ı i". Marks orders the combining marks that only break ties between
+	// otherwise equal strings, and Case lowercases before comparing. Without
+	// them marks sort by code point and Unicode case mapping applies.
+	Collation []string
+	Marks     string
+	Case      unicode.SpecialCase
+
+	// Plural and Ordinal give the CLDR plural category of n >= 0 ("zero",
+	// "one", "two", "few", "many" or "other") for cardinal and ordinal
+	// numbers, as used by Message. A Locale without them uses English rules.
+	Plural, Ordinal func(n float64) string
+}
+
+var locales = struct {
+	sync.RWMutex
+	m map[string]*Locale
+}{m: map[string]*Locale{
+	"en": {
+		Months:      [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		ShortMonths: [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		Days:        [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		ShortDays:   [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		Span: pluralSpan([...]string{"second", "minute", "hour", "day", "week", "month", "year"},
+			[...]string{"seconds", "minutes", "hours", "days", "weeks", "months", "years"}),
+		Past: "%s ago", Future: "in %s", Now: "just now",
+		Decimal: ".", Group: ",", Currency: "¤#",
+		Plural: pluralOne, Ordinal: ordinalEnglish,
+	},
+	"vi": {
+		Months:      [12]string{"tháng 1", "tháng 2", "tháng 3", "tháng 4", "tháng 5", "tháng 6", "tháng 7", "tháng 8", "tháng 9", "tháng 10", "tháng 11", "tháng 12"},
+		ShortMonths: [12]string{"thg 1", "thg 2", "thg 3", "thg 4", "thg 5", "thg 6", "thg 7", "thg 8", "thg 9", "thg 10", "thg 11", "thg 12"},
+		Days:        [7]string{"Chủ Nhật", "Thứ Hai", "Thứ Ba", "Thứ Tư", "Thứ Năm", "Thứ Sáu", "Thứ Bảy"},
+		ShortDays:   [7]string{"CN", "T2", "T3", "T4", "T5", "T6", "T7"},
+		Span: pluralSpan([...]string{"giây", "phút", "giờ", "ngày", "tuần", "tháng", "năm"},
+			[...]string{"giây", "phút", "giờ", "ngày", "tuần", "tháng", "năm"}),
+		Past: "%s trước", Future: "%s nữa", Now: "vừa xong",
+		Decimal: ",", Group: ".", Currency: "#\u00a0¤",
+		Collation: []string{"a ă â", "d đ", "e ê", "o ô ơ", "u ư"},
+		// Tones: huyền, hỏi, ngã, sắc, nặng.
+		Marks:  "\u0300\u0309\u0303\u0301\u0323",
+		Plural: pluralOther, Ordinal: ordinalOne,
+	},
+	"fr": {
+		Months:      [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		ShortMonths: [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		Days:        [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		ShortDays:   [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		Span: pluralSpan([...]string{"seconde", "minute", "heure", "jour", "semaine", "mois", "an"},
+			[...]string{"secondes", "minutes", "heures", "jours", "semaines", "mois", "ans"}),
+		Past: "il y a %s", Future: "dans %s", Now: "à l'instant",
+		Decimal: ",", Group: "\u202f", Currency: "#\u00a0¤",
+		Plural: pluralFrench, Ordinal: ordinalOne,
+	},
+	"de": {
+		Months:      [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		ShortMonths: [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		Days:        [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		ShortDays:   [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		Decimal:     ",", Group: ".", Currency: "#\u00a0¤",
+		Plural: pluralOne, Ordinal: pluralOther,
+	},
+	"es": {
+		Months:      [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		ShortMonths: [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		Days:        [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		ShortDays:   [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		Span: pluralSpan([...]string{"segundo", "minuto", "hora", "día", "semana", "mes", "año"},
+			[...]string{"segundos", "minutos", "horas", "días", "semanas", "meses", "años"}),
+		Past: "hace %s", Future: "en %s", Now: "ahora mismo",
+		Decimal: ",", Group: ".", Currency: "#\u00a0¤",
+		Collation: []string{"n ñ"},
+		Plural:    pluralSpanish, Ordinal: pluralOther,
+	},
+	"tr": {
+		Months:      [12]string{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran", "Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"},
+		ShortMonths: [12]string{"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"},
+		Days:        [7]string{"Pazar", "Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi"},
+		ShortDays:   [7]string{"Paz", "Pzt", "Sal", "Çar", "Per", "Cum", "Cmt"},
+		Span: pluralSpan([...]string{"saniye", "dakika", "saat", "gün", "hafta", "ay", "yıl"},
+			[...]string{"saniye", "dakika", "saat", "gün", "hafta", "ay", "yıl"}),
+		Past: "%s önce", Future: "%s sonra", Now: "şimdi",
+		Decimal: ",", Group: ".", Currency: "¤#",
+		Collation: []string{"c ç", "g ğ", "ı i", "o ö", "s ş", "u ü"},
+		Case:      unicode.TurkishCase,
+		Plural:    pluralOne, Ordinal: pluralOther,
+	},
+}}
+
+// RegisterLocale adds or replaces the Locale for a language tag such as
+// "pt" or "pt-BR". It is safe to call concurrently with formatting.
+func RegisterLocale(tag string, l *Locale) {
+	locales.Lock()
+	locales.m[strings.ToLower(tag)] = l
+	locales.Unlock()
+}
+
+// lookupLocale resolves tag to a registered Locale, falling back to the base
+// language and then to English.
+func lookupLocale(tag string) *Locale {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	locales.RLock()
+	defer locales.RUnlock()
+	if l, ok := locales.m[tag]; ok {
+		return l
+	}
+	if i := strings.IndexByte(tag, '-'); i > 0 {
+		if l, ok := locales.m[tag[:i]]; ok {
+			return l
+		}
+	}
+	return locales.m["en"]
+}
+
+// pluralSpan builds a Span for languages that only distinguish one from
+// many, indexed by Unit.
+func pluralSpan(one, many [7]string) func(int, Unit) string {
+	return func(n int, unit Unit) string {
+		if n == 1 {
+			return "1 " + one[unit]
+		}
+		return strconv.Itoa(n) + " " + many[unit]
+	}
+}
+
+// Plural rules of the built-in locales, after CLDR. Numbers are floats, so
+// "1.0" counts as the integer 1.
+
+func pluralOne(n float64) string {
+	if n == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func pluralOther(float64) string { return "other" }
+
+func pluralFrench(n float64) string {
+	switch {
+	case n < 2:
+		return "one"
+	case isMillions(n):
+		return "many"
+	}
+	return "other"
+}
+
+func pluralSpanish(n float64) string {
+	switch {
+	case n == 1:
+		return "one"
+	case isMillions(n):
+		return "many"
+	}
+	return "other"
+}
+
+// isMillions reports whether n is a non-zero whole number of millions,
+// which French and Spanish count with "de".
+func isMillions(n float64) bool { return n != 0 && math.Mod(n, 1e6) == 0 }
+
+func ordinalOne(n float64) string { return pluralOne(n) }
+
+func ordinalEnglish(n float64) string {
+	switch i := int64(n); {
+	case float64(i) != n:
+		return "other"
+	case i%10 == 1 && i%100 != 11:
+		return "one"
+	case i%10 == 2 && i%100 != 12:
+		return "two"
+	case i%10 == 3 && i%100 != 13:
+		return "few"
+	}
+	return "other"
+}
@@ -0,0 +1,63 @@
+package kit
+
+import (
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// LogValue implements slog.LogValuer: Maps become groups with keys in sorted
+// order and scalars map onto the matching slog kinds. Arrays of scalars
+// become slices; an Array holding a Map or an Array becomes a group keyed
+// by index ("0", "1", ...), so nested Maps are groups wherever they sit.
+func (v Value) LogValue() slog.Value {
+	switch v.K {
+	case Nil:
+		return slog.AnyValue(nil)
+	case Number:
+		if i := int64(v.N); v.N == float64(i) {
+			return slog.Int64Value(i)
+		}
+		return slog.Float64Value(v.N)
+	case Bool:
+		return slog.BoolValue(v.N > 0)
+	case Time:
+		return slog.TimeValue(v.goTime())
+	case Duration:
+		return slog.DurationValue(time.Duration(int64(v.N)))
+	case String:
+		return slog.StringValue(v.String())
+	case Map:
+		m := v.mapping()
+		keys := sortedKeys(m)
+		attrs := make([]slog.Attr, len(keys))
+		for i, k := range keys {
+			attrs[i] = slog.Attr{Key: k, Value: m[k].LogValue()}
+		}
+		return slog.GroupValue(attrs...)
+	case Array:
+		a := v.V.([]Value)
+		nested := slices.ContainsFunc(a, func(e Value) bool {
+			k := e.Force().K
+			return k == Map || k == Array
+		})
+		if !nested {
+			return slog.AnyValue(v.Export())
+		}
+		attrs := make([]slog.Attr, len(a))
+		for i, e := range a {
+			attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: e.Force().LogValue()}
+		}
+		return slog.GroupValue(attrs...)
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().LogValue()
+		}
+		return slog.AnyValue(v.V)
+	case Invalid:
+		return slog.StringValue("!INVALID")
+	default:
+		return slog.AnyValue(v.Export())
+	}
+}
@@ -0,0 +1,42 @@
+package kit
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestValue_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	v := New(map[string]any{
+		"id":   7,
+		"user": map[string]any{"name": "an"},
+		"tags": []string{"a", "b"},
+	})
+	logger.Info("req", "payload", v)
+
+	out := buf.String()
+	for _, want := range []string{
+		`"payload":{`,
+		`"id":7`,
+		`"user":{"name":"an"}`,
+		`"tags":["a","b"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output %s missing %s", out, want)
+		}
+	}
+	if strings.Contains(out, `"K":`) {
+		t.Error("internal fields leaked into log output")
+	}
+
+	// Maps inside Arrays are groups too, keyed by index.
+	buf.Reset()
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("req", "items", New([]any{map[string]any{"id": 1}, "x"}))
+	if out := buf.String(); !strings.Contains(out, "items.0.id=1 items.1=x") {
+		t.Errorf("text log output %s, want the Map in the Array as a group", out)
+	}
+}
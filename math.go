@@ -0,0 +1,258 @@
+package kit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   MATH
+   Number functions in the lenient style of Value.Div: other kinds give
+   Invalid, and results that are not real numbers, such as the square root
+   of a negative, give Nil.
+   ============================================================================= */
+
+// number returns a Number result, or Nil for NaN from non-NaN inputs.
+func number(n float64, in ...float64) Value {
+	if math.IsNaN(n) {
+		for _, x := range in {
+			if math.IsNaN(x) {
+				return Value{K: Number, N: n}
+			}
+		}
+		return Value{K: Nil}
+	}
+	return Value{K: Number, N: n}
+}
+
+// Abs returns |v| for Numbers and Durations.
+func (v Value) Abs() Value {
+	v = v.Force()
+	switch v.K {
+	case Number, Duration:
+		return Value{K: v.K, N: math.Abs(v.N)}
+	}
+	return Value{K: Invalid}
+}
+
+// Floor rounds a Number down to an integer.
+func (v Value) Floor() Value {
+	if v = v.Force(); v.K != Number {
+		return Value{K: Invalid}
+	}
+	return Value{K: Number, N: math.Floor(v.N)}
+}
+
+// Ceil rounds a Number up to an integer.
+func (v Value) Ceil() Value {
+	if v = v.Force(); v.K != Number {
+		return Value{K: Invalid}
+	}
+	return Value{K: Number, N: math.Ceil(v.N)}
+}
+
+// RoundMode selects how Round treats the digits it drops.
+type RoundMode uint8
+
+const (
+	RoundHalfUp   RoundMode = iota // halves away from zero: 2.5 → 3, -2.5 → -3
+	RoundHalfEven                  // halves to the even neighbor: 2.5 → 2, 3.5 → 4
+	RoundFloor                     // toward -Inf: -2.1 → -3
+	RoundCeil                      // toward +Inf: 2.1 → 3
+)
+
+// Round rounds a Number to places decimal places under mode; negative
+// places round to tens, hundreds and so on. Rounding works on the shortest
+// decimal form of the Number, so 1.005 is a half and rounds up to 1.01
+// even though its binary value is slightly below.
+func (v Value) Round(places int, mode RoundMode) Value {
+	if v = v.Force(); v.K != Number {
+		return Value{K: Invalid}
+	}
+	return Value{K: Number, N: roundDecimal(v.N, places, mode)}
+}
+
+func roundDecimal(n float64, places int, mode RoundMode) float64 {
+	if n == 0 || math.IsNaN(n) || math.IsInf(n, 0) {
+		return n
+	}
+	neg := n < 0
+	// d.dddde±x: the shortest digits that parse back to n, never with
+	// trailing zeros, so any dropped digits are nonzero.
+	s := strconv.FormatFloat(math.Abs(n), 'e', -1, 64)
+	mant, exp := s, 0
+	for i := range s {
+		if s[i] == 'e' {
+			mant = s[:i]
+			exp, _ = strconv.Atoi(s[i+1:])
+			break
+		}
+	}
+	digits := mant[:1]
+	if len(mant) > 2 {
+		digits += mant[2:]
+	}
+
+	keep := exp + 1 + places
+	if keep >= len(digits) {
+		return n
+	}
+	if keep < 0 {
+		digits, keep = strings.Repeat("0", -keep)+digits, 0
+	}
+	k, _ := strconv.ParseUint("0"+digits[:keep], 10, 64)
+	rest := digits[keep:]
+	var up bool // away from zero
+	switch mode {
+	case RoundHalfEven:
+		up = rest > "5" || rest == "5" && k%2 == 1
+	case RoundFloor:
+		up = neg
+	case RoundCeil:
+		up = !neg
+	default:
+		up = rest[0] >= '5'
+	}
+	if up {
+		k++
+	}
+	r, _ := strconv.ParseFloat(strconv.FormatUint(k, 10)+"e"+strconv.Itoa(-places), 64)
+	if neg {
+		r = -r
+	}
+	return r
+}
+
+// Pow returns v raised to the Number x.
+func (v Value) Pow(x Value) Value {
+	v, x = v.Force(), x.Force()
+	if v.K != Number || x.K != Number {
+		return Value{K: Invalid}
+	}
+	return number(math.Pow(v.N, x.N), v.N, x.N)
+}
+
+// Sqrt returns the square root of a Number; negatives give Nil.
+func (v Value) Sqrt() Value {
+	if v = v.Force(); v.K != Number {
+		return Value{K: Invalid}
+	}
+	return number(math.Sqrt(v.N), v.N)
+}
+
+// Mod returns the remainder of v / x truncated toward zero, so it takes
+// the sign of v (-7 mod 3 is -1), as Go's % and math.Mod do. Numbers and
+// Durations are supported; a zero divisor gives Nil, as with Div.
+func (v Value) Mod(x Value) Value {
+	v, x = v.Force(), x.Force()
+	switch {
+	case v.K == Number && x.K == Number, v.K == Duration && x.K == Duration:
+	default:
+		return Value{K: Invalid}
+	}
+	if x.N == 0 {
+		return Value{K: Nil}
+	}
+	r := number(math.Mod(v.N, x.N), v.N, x.N)
+	if r.K == Number {
+		r.K = v.K
+	}
+	return r
+}
+
+// Clamp limits v to the range [lo, hi]. v, lo and hi must share a kind
+// among Number, Time and Duration; Invalid is returned otherwise or when
+// lo > hi.
+func (v Value) Clamp(lo, hi Value) Value {
+	v, lo, hi = v.Force(), lo.Force(), hi.Force()
+	if v.K != lo.K || v.K != hi.K || v.K != Number && v.K != Time && v.K != Duration || lo.N > hi.N {
+		return Value{K: Invalid}
+	}
+	switch {
+	case v.N < lo.N && v.K == Number:
+		return lo
+	case v.N > hi.N && v.K == Number:
+		return hi
+	case v.N < lo.N:
+		v.N = lo.N
+	case v.N > hi.N:
+		v.N = hi.N
+	}
+	return v
+}
+
+/* =============================================================================
+   INTEGER OPERATIONS
+   IntDiv and the bitwise operators take integral Numbers within ±(2^53-1),
+   the range float64 holds exactly, and compute on int64 as Go does. Any
+   other operand gives Invalid.
+   ============================================================================= */
+
+// safeInt returns v as an int64 when it is an integral Number in the safe
+// range.
+func (v Value) safeInt() (int64, bool) {
+	if v = v.Force(); v.K != Number || v.N != math.Trunc(v.N) || math.Abs(v.N) > maxSafeInt {
+		return 0, false
+	}
+	return int64(v.N), true
+}
+
+func intOp(a, b Value, fn func(x, y int64) Value) Value {
+	x, ok := a.safeInt()
+	y, ok2 := b.safeInt()
+	if !ok || !ok2 {
+		return Value{K: Invalid}
+	}
+	return fn(x, y)
+}
+
+// IntDiv returns the quotient of v / x truncated toward zero, so that
+// v == v.IntDiv(x)*x + v.Mod(x). A zero divisor gives Nil, as with Div.
+func (v Value) IntDiv(x Value) Value {
+	return intOp(v, x, func(a, b int64) Value {
+		if b == 0 {
+			return Value{K: Nil}
+		}
+		return Value{K: Number, N: float64(a / b)}
+	})
+}
+
+// And returns the bitwise AND of v and x; negative Numbers are in two's
+// complement.
+func (v Value) And(x Value) Value {
+	return intOp(v, x, func(a, b int64) Value { return Value{K: Number, N: float64(a & b)} })
+}
+
+// Or returns the bitwise OR of v and x.
+func (v Value) Or(x Value) Value {
+	return intOp(v, x, func(a, b int64) Value { return Value{K: Number, N: float64(a | b)} })
+}
+
+// Xor returns the bitwise XOR of v and x.
+func (v Value) Xor(x Value) Value {
+	return intOp(v, x, func(a, b int64) Value { return Value{K: Number, N: float64(a ^ b)} })
+}
+
+// Shl shifts v left by n bits; n must not be negative. Bits shifted past
+// the 64th are lost, and results beyond ±(2^53-1) are rounded to the
+// nearest float64, which Arith with Overflow reports as an error.
+func (v Value) Shl(n Value) Value {
+	return intOp(v, n, func(a, s int64) Value {
+		if s < 0 {
+			return Value{K: Invalid}
+		}
+		return Value{K: Number, N: float64(a << s)}
+	})
+}
+
+// Shr shifts v right by n bits, keeping its sign: -8 >> 1 is -4, and -1
+// stays -1 however far it is shifted. n must not be negative.
+func (v Value) Shr(n Value) Value {
+	return intOp(v, n, func(a, s int64) Value {
+		if s < 0 {
+			return Value{K: Invalid}
+		}
+		return Value{K: Number, N: float64(a >> s)}
+	})
+}
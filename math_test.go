@@ -0,0 +1,129 @@
+package kit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMath(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want Value
+	}{
+		{"abs", New(-2.5).Abs(), New(2.5)},
+		{"abs duration", New(-time.Second).Abs(), New(time.Second)},
+		{"floor", New(-1.5).Floor(), New(-2)},
+		{"ceil", New(1.2).Ceil(), New(2)},
+		{"pow", New(2).Pow(New(10)), New(1024)},
+		{"sqrt", New(9).Sqrt(), New(3)},
+		{"mod", New(7).Mod(New(3)), New(1)},
+		{"mod sign", New(-7).Mod(New(3)), New(-1)},
+		{"mod duration", New(90 * time.Second).Mod(New(time.Minute)), New(30 * time.Second)},
+		{"clamp low", New(-1).Clamp(New(0), New(10)), New(0)},
+		{"clamp high", New(11).Clamp(New(0), New(10)), New(10)},
+		{"clamp inside", New(5).Clamp(New(0), New(10)), New(5)},
+		{"clamp duration", New(time.Hour).Clamp(New(time.Second), New(time.Minute)), New(time.Minute)},
+		{"mod by zero", New(1).Mod(New(0)), Value{K: Nil}},
+		{"sqrt negative", New(-1).Sqrt(), Value{K: Nil}},
+		{"pow undefined", New(-8).Pow(New(1.0 / 3)), Value{K: Nil}},
+		{"not a number", New("1").Abs(), Value{K: Invalid}},
+		{"mixed kinds", New(1).Mod(New(time.Second)), Value{K: Invalid}},
+		{"clamp kinds", New(1).Clamp(New(0), New("9")), Value{K: Invalid}},
+		{"clamp empty range", New(1).Clamp(New(2), New(0)), Value{K: Invalid}},
+	}
+	for _, tt := range tests {
+		if tt.got.K != tt.want.K || !tt.got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got.DumpVerbose(), tt.want.DumpVerbose())
+		}
+	}
+	if r := New(math.NaN()).Sqrt(); r.K != Number || !math.IsNaN(r.N) {
+		t.Errorf("NaN input should stay NaN, got %v", r.DumpVerbose())
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in     float64
+		places int
+		mode   RoundMode
+		want   float64
+	}{
+		{1.005, 2, RoundHalfUp, 1.01},
+		{2.675, 2, RoundHalfUp, 2.68},
+		{-2.5, 0, RoundHalfUp, -3},
+		{0.5, 0, RoundHalfUp, 1},
+		{0.4, 0, RoundHalfUp, 0},
+		{1234.5678, -2, RoundHalfUp, 1200},
+		{1250, -2, RoundHalfUp, 1300},
+		{0.0004, 2, RoundHalfUp, 0},
+		{0.006, 2, RoundHalfUp, 0.01},
+		{1.23, 5, RoundHalfUp, 1.23},
+		{1e300, 2, RoundHalfUp, 1e300},
+		{99.95, 1, RoundHalfUp, 100},
+
+		{2.5, 0, RoundHalfEven, 2},
+		{3.5, 0, RoundHalfEven, 4},
+		{-2.5, 0, RoundHalfEven, -2},
+		{1.005, 2, RoundHalfEven, 1},
+		{1.015, 2, RoundHalfEven, 1.02},
+		{2.5001, 0, RoundHalfEven, 3},
+		{1250, -2, RoundHalfEven, 1200},
+
+		{2.19, 1, RoundFloor, 2.1},
+		{-2.11, 1, RoundFloor, -2.2},
+		{0.0004, 2, RoundFloor, 0},
+		{-0.0004, 2, RoundFloor, -0.01},
+
+		{2.11, 1, RoundCeil, 2.2},
+		{-2.19, 1, RoundCeil, -2.1},
+		{0.0004, 2, RoundCeil, 0.01},
+		{2, 0, RoundCeil, 2},
+	}
+	for _, tt := range tests {
+		if got := New(tt.in).Round(tt.places, tt.mode); got.N != tt.want {
+			t.Errorf("Round(%v, %d, %d) = %v, want %v", tt.in, tt.places, tt.mode, got.N, tt.want)
+		}
+	}
+	if r := New(math.Inf(1)).Round(2, RoundFloor); !math.IsInf(r.N, 1) {
+		t.Errorf("Round(+Inf) = %v", r.N)
+	}
+	if r := New("1.5").Round(0, RoundCeil); !r.IsInvalid() {
+		t.Errorf("Round(String) = %v, want Invalid", r)
+	}
+}
+
+func TestIntegerOps(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want Value
+	}{
+		{"intdiv", New(7).IntDiv(New(2)), New(3)},
+		{"intdiv truncates", New(-7).IntDiv(New(2)), New(-3)},
+		{"and", New(0b1100).And(New(0b1010)), New(0b1000)},
+		{"or", New(0b1100).Or(New(0b1010)), New(0b1110)},
+		{"xor", New(0b1100).Xor(New(0b1010)), New(0b0110)},
+		{"and negative", New(-1).And(New(0xff)), New(0xff)},
+		{"shl", New(1).Shl(New(10)), New(1024)},
+		{"shl past 64", New(1).Shl(New(64)), New(0)},
+		{"shr", New(1024).Shr(New(3)), New(128)},
+		{"shr sign", New(-8).Shr(New(1)), New(-4)},
+		{"shr negative one", New(-1).Shr(New(70)), New(-1)},
+		{"intdiv by zero", New(1).IntDiv(New(0)), Value{K: Nil}},
+		{"fraction", New(1.5).And(New(1)), Value{K: Invalid}},
+		{"unsafe", New(1 << 60).Or(New(1)), Value{K: Invalid}},
+		{"negative shift", New(1).Shl(New(-1)), Value{K: Invalid}},
+		{"kind", New("3").Xor(New(1)), Value{K: Invalid}},
+	}
+	for _, tt := range tests {
+		if tt.got.K != tt.want.K || !tt.got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got.DumpVerbose(), tt.want.DumpVerbose())
+		}
+	}
+	for _, p := range [][2]int{{7, 2}, {-7, 2}, {7, -2}, {-7, -2}} {
+		a, b := New(p[0]), New(p[1])
+		if got := a.IntDiv(b).Mul(b).Add(a.Mod(b)); !got.Equal(a) {
+			t.Errorf("%d div/mod %d does not recombine: %v", p[0], p[1], got)
+		}
+	}
+}
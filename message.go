@@ -0,0 +1,309 @@
+package kit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   MESSAGE FORMAT
+   The ICU MessageFormat subset that notification templates need:
+
+	{name}                                  the argument, Numbers localized
+	{name, number}                          a localized Number
+	{name, plural, =0{…} one{# item} other{# items}}
+	{name, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}
+	{name, select, admin{…} other{…}}
+
+   Arguments are dot-separated paths into the environment. Inside plural
+   cases # is the number less any "offset:n", and an apostrophe quotes
+   syntax characters: '{' is a literal brace and '' an apostrophe.
+   ============================================================================= */
+
+// Message formats an ICU-style pattern with arguments from env, using
+// English plural rules:
+//
+//	kit.Message("You have {count, plural, one{# item} other{# items}}", env)
+//
+// Malformed patterns, missing arguments and non-numeric plurals give an
+// Error Value.
+func Message(pattern string, env Value) Value { return MessageLocale("en", pattern, env) }
+
+// MessageLocale is Message with the number format and plural rules of the
+// Locale registered for tag.
+func MessageLocale(tag, pattern string, env Value) Value {
+	p := msgParser{src: pattern}
+	nodes, err := p.parse(0, false)
+	if err != nil {
+		return NewError(err)
+	}
+	var b strings.Builder
+	r := msgRenderer{tag: tag, loc: lookupLocale(tag), env: env, b: &b}
+	if err := r.render(nodes, 0); err != nil {
+		return NewError(err)
+	}
+	return Value{K: String, V: b.String()}
+}
+
+type msgNode struct {
+	text   string // literal text when arg and typ are empty
+	hash   bool   // # in a plural case
+	arg    string
+	typ    string // "", "number", "plural", "selectordinal" or "select"
+	offset float64
+	cases  []msgCase
+}
+
+type msgCase struct {
+	key  string // "=3", a plural category or a select key
+	body []msgNode
+}
+
+type msgParser struct {
+	src string
+	pos int
+}
+
+func (p *msgParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("kit: message at offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parse reads nodes up to an unmatched } or the end of the pattern; depth
+// counts the enclosing arguments and plural whether # is the plural number.
+func (p *msgParser) parse(depth int, plural bool) ([]msgNode, error) {
+	var nodes []msgNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, msgNode{text: text.String()})
+			text.Reset()
+		}
+	}
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '{':
+			flush()
+			n, err := p.argument(depth, plural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case c == '}':
+			if depth == 0 {
+				return nil, p.errorf("unmatched }")
+			}
+			flush()
+			return nodes, nil
+		case c == '#' && plural:
+			flush()
+			nodes = append(nodes, msgNode{hash: true})
+			p.pos++
+		case c == '\'':
+			p.pos++
+			switch {
+			case strings.HasPrefix(p.src[p.pos:], "'"):
+				text.WriteByte('\'')
+				p.pos++
+			case p.pos < len(p.src) && strings.IndexByte("{}#", p.src[p.pos]) >= 0:
+				end := strings.IndexByte(p.src[p.pos:], '\'')
+				if end < 0 {
+					end = len(p.src) - p.pos
+				}
+				text.WriteString(p.src[p.pos : p.pos+end])
+				p.pos = min(p.pos+end+1, len(p.src))
+			default:
+				text.WriteByte('\'')
+			}
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	if depth > 0 {
+		return nil, p.errorf("unclosed {")
+	}
+	flush()
+	return nodes, nil
+}
+
+// token returns the next run of characters up to a space or one of stop.
+func (p *msgParser) token(stop string) string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ' ' && strings.IndexByte(stop, p.src[p.pos]) < 0 {
+		p.pos++
+	}
+	return strings.TrimSpace(p.src[start:p.pos])
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// expect consumes c after optional spaces.
+func (p *msgParser) expect(c byte) error {
+	p.skipSpace()
+	if p.pos >= len(p.src) || p.src[p.pos] != c {
+		return p.errorf("expected %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// argument parses a {…} argument starting at its opening brace.
+func (p *msgParser) argument(depth int, plural bool) (msgNode, error) {
+	p.pos++
+	n := msgNode{arg: p.token(",}")}
+	if n.arg == "" {
+		return n, p.errorf("missing argument name")
+	}
+	if p.skipSpace(); p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return n, nil
+	}
+	if err := p.expect(','); err != nil {
+		return n, err
+	}
+	n.typ = p.token(",}")
+	switch n.typ {
+	case "number":
+		return n, p.expect('}')
+	case "plural", "selectordinal":
+		plural = true
+	case "select":
+	default:
+		return n, p.errorf("unknown argument type %q", n.typ)
+	}
+	if err := p.expect(','); err != nil {
+		return n, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos < len(p.src) && p.src[p.pos] == '}' {
+			p.pos++
+			break
+		}
+		key := p.token("{}")
+		if rest, ok := strings.CutPrefix(key, "offset:"); ok && n.typ == "plural" && len(n.cases) == 0 {
+			off, err := strconv.ParseFloat(rest, 64)
+			if err != nil {
+				return n, p.errorf("bad offset %q", rest)
+			}
+			n.offset = off
+			continue
+		}
+		if key == "" {
+			return n, p.errorf("missing case selector")
+		}
+		if err := p.expect('{'); err != nil {
+			return n, err
+		}
+		body, err := p.parse(depth+1, plural)
+		if err != nil {
+			return n, err
+		}
+		p.pos++ // the closing brace parse stopped at
+		n.cases = append(n.cases, msgCase{key: key, body: body})
+	}
+	for _, c := range n.cases {
+		if c.key == "other" {
+			return n, nil
+		}
+	}
+	return n, p.errorf("%s argument %q has no other case", n.typ, n.arg)
+}
+
+type msgRenderer struct {
+	tag string
+	loc *Locale
+	env Value
+	b   *strings.Builder
+}
+
+// render writes nodes; num is the value of # in the innermost plural.
+func (r *msgRenderer) render(nodes []msgNode, num float64) error {
+	for _, n := range nodes {
+		switch {
+		case n.hash:
+			r.b.WriteString(r.number(num))
+			continue
+		case n.arg == "":
+			r.b.WriteString(n.text)
+			continue
+		}
+		v := r.env.Path(n.arg)
+		if !r.env.Exists(n.arg) {
+			return fmt.Errorf("kit: message argument %q missing", n.arg)
+		}
+		switch n.typ {
+		case "":
+			if v.K == Number {
+				r.b.WriteString(r.number(v.N))
+			} else {
+				r.b.WriteString(v.Text())
+			}
+		case "select":
+			body, _ := pickCase(n.cases, v.Text())
+			if err := r.render(body, num); err != nil {
+				return err
+			}
+		default:
+			x, ok := v.number()
+			if !ok {
+				return fmt.Errorf("kit: message argument %q is %s, not a number", n.arg, v.K)
+			}
+			if n.typ == "number" {
+				r.b.WriteString(r.number(x))
+				continue
+			}
+			body, ok := pickCase(n.cases, "="+strconv.FormatFloat(x, 'f', -1, 64))
+			if !ok {
+				body, _ = pickCase(n.cases, r.category(n.typ, x-n.offset))
+			}
+			if err := r.render(body, x-n.offset); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *msgRenderer) number(n float64) string {
+	return Value{K: Number, N: n}.FormatNumber(r.tag).Text()
+}
+
+func (r *msgRenderer) category(typ string, n float64) string {
+	rule, en := r.loc.Plural, pluralOne
+	if typ == "selectordinal" {
+		rule, en = r.loc.Ordinal, ordinalEnglish
+	}
+	if rule == nil {
+		rule = en
+	}
+	if n < 0 {
+		n = -n
+	}
+	return rule(n)
+}
+
+// pickCase returns the body of the case matching key, falling back to
+// "other" unless key is an exact "=n" selector.
+func pickCase(cases []msgCase, key string) ([]msgNode, bool) {
+	for _, c := range cases {
+		if c.key == key {
+			return c.body, true
+		}
+	}
+	if strings.HasPrefix(key, "=") {
+		return nil, false
+	}
+	for _, c := range cases {
+		if c.key == "other" {
+			return c.body, true
+		}
+	}
+	return nil, false
+}
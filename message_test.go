@@ -0,0 +1,38 @@
+package kit
+
+import "testing"
+
+func TestMessage(t *testing.T) {
+	items := "You have {count, plural, =0{no items} one{# item} other{# items}}."
+	cases := []struct {
+		tag, pattern string
+		env          any
+		want         string
+	}{
+		{"en", items, map[string]any{"count": 0}, "You have no items."},
+		{"en", items, map[string]any{"count": 1}, "You have 1 item."},
+		{"en", items, map[string]any{"count": 1234}, "You have 1,234 items."},
+		{"en", items, map[string]any{"count": "2"}, "You have 2 items."},
+		{"fr", "{n, plural, one{# fichier} many{# de fichiers} other{# fichiers}}", map[string]any{"n": 1.5}, "1,5 fichier"},
+		{"fr", "{n, plural, one{# fichier} many{# de fichiers} other{# fichiers}}", map[string]any{"n": 2e6}, "2 000 000 de fichiers"},
+		{"vi", "Bạn có {n, plural, one{# mục} other{# mục}}", map[string]any{"n": 1}, "Bạn có 1 mục"},
+		{"en", "{place, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}", map[string]any{"place": 22}, "22nd"},
+		{"en", "{place, selectordinal, one{#st} two{#nd} few{#rd} other{#th}}", map[string]any{"place": 13}, "13th"},
+		{"en", "{user.name} and {others, plural, offset:1 =0{nobody else} =1{one other} other{# others}}",
+			map[string]any{"user": map[string]any{"name": "An"}, "others": 3}, "An and 2 others"},
+		{"en", "{g, select, female{She} male{He} other{They}} replied", map[string]any{"g": "x"}, "They replied"},
+		{"en", "{n, plural, other{{g, select, a{# A} other{# B}}}}", map[string]any{"n": 3, "g": "a"}, "3 A"},
+		{"en", "It''s '{literal}' and '#' {n, number}", map[string]any{"n": 1e4}, "It's {literal} and # 10,000"},
+	}
+	for _, c := range cases {
+		if got := MessageLocale(c.tag, c.pattern, New(c.env)); got.K != String || got.String() != c.want {
+			t.Errorf("MessageLocale(%q, %q) = %s, want %q", c.tag, c.pattern, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{"{", "}", "{n, plural, one{x}}", "{n, date}", "{n, plural, other{x}", "{missing}", "{s, plural, other{x}}"} {
+		if got := Message(bad, New(map[string]any{"n": 1, "s": "x"})); !got.IsError() {
+			t.Errorf("Message(%q) = %s, want an Error", bad, got)
+		}
+	}
+}
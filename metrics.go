@@ -0,0 +1,60 @@
+package kit
+
+import (
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+/* =============================================================================
+   METRICS
+   Instrumentation of the reflection slow paths, so a host can find which
+   payload shapes miss the fast paths. Nothing is measured until SetMetrics
+   installs a sink; the cost when disabled is one atomic load per slow-path
+   call.
+   ============================================================================= */
+
+// Metrics receives slow-path events. Implementations must be safe for
+// concurrent use and should only update counters or histograms, typically
+// labelled by type. Embed NopMetrics to stay compatible as events are added.
+type Metrics interface {
+	// Parse reports a reflection-based conversion of a value of type t.
+	// Nested elements are not reported separately: elapsed covers them, and
+	// allocs counts the slices and maps built for the whole tree, a proxy
+	// for its heap allocations.
+	Parse(t reflect.Type, allocs int, elapsed time.Duration)
+
+	// ReflectGet reports a field lookup on a Struct Value.
+	ReflectGet(t reflect.Type, field string)
+
+	// ReflectCall reports a Func call that went through reflection because
+	// the function has no fast-path signature.
+	ReflectCall(t reflect.Type, elapsed time.Duration)
+}
+
+// NopMetrics ignores every event.
+type NopMetrics struct{}
+
+func (NopMetrics) Parse(reflect.Type, int, time.Duration)  {}
+func (NopMetrics) ReflectGet(reflect.Type, string)         {}
+func (NopMetrics) ReflectCall(reflect.Type, time.Duration) {}
+
+type metricsBox struct{ m Metrics }
+
+var metrics atomic.Pointer[metricsBox]
+
+// SetMetrics installs m as the process-wide sink; nil disables metrics.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		metrics.Store(nil)
+		return
+	}
+	metrics.Store(&metricsBox{m})
+}
+
+func loadMetrics() Metrics {
+	if b := metrics.Load(); b != nil {
+		return b.m
+	}
+	return nil
+}
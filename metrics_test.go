@@ -0,0 +1,66 @@
+package kit
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingMetrics struct {
+	NopMetrics
+	mu     sync.Mutex
+	parse  map[string]int
+	allocs map[string]int
+	gets   int
+	calls  int
+}
+
+func (m *countingMetrics) Parse(t reflect.Type, allocs int, _ time.Duration) {
+	m.mu.Lock()
+	m.parse[t.String()]++
+	m.allocs[t.String()] += allocs
+	m.mu.Unlock()
+}
+
+func (m *countingMetrics) ReflectGet(reflect.Type, string) {
+	m.mu.Lock()
+	m.gets++
+	m.mu.Unlock()
+}
+
+func (m *countingMetrics) ReflectCall(reflect.Type, time.Duration) {
+	m.mu.Lock()
+	m.calls++
+	m.mu.Unlock()
+}
+
+func TestSetMetrics(t *testing.T) {
+	type point struct{ X int }
+	m := &countingMetrics{parse: map[string]int{}, allocs: map[string]int{}}
+	SetMetrics(m)
+	defer SetMetrics(nil)
+
+	New("fast path")
+	New([]int{1, 2})
+	New(map[string][]int{"a": {1}, "b": {2}})
+	New(point{X: 1}).Get("X")
+	New(func(a, b int) int { return a + b }).Call(New(1), New(2))
+
+	// The nested []int of the map are not reported on their own.
+	if m.parse["[]int"] != 1 || m.parse["map[string][]int"] != 1 || m.parse["kit.point"] != 1 || len(m.parse) != 4 {
+		t.Errorf("Parse events = %v", m.parse)
+	}
+	if m.allocs["[]int"] != 1 || m.allocs["map[string][]int"] != 3 || m.allocs["kit.point"] != 0 {
+		t.Errorf("Parse allocs = %v", m.allocs)
+	}
+	if m.gets != 1 || m.calls != 1 {
+		t.Errorf("gets = %d, calls = %d", m.gets, m.calls)
+	}
+
+	SetMetrics(nil)
+	New([]int{1})
+	if m.parse["[]int"] != 1 {
+		t.Error("events after SetMetrics(nil)")
+	}
+}
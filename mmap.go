@@ -0,0 +1,34 @@
+package kit
+
+/* =============================================================================
+   MEMORY MAPPING
+   Read-only file mappings for DecodeMsgPackView. Platforms without mmap
+   read the file into memory instead, with the same API.
+   ============================================================================= */
+
+// Mapping is a read-only view of a file's content, memory-mapped where
+// the platform supports it.
+type Mapping struct {
+	data  []byte
+	unmap func([]byte) error
+}
+
+// Mmap maps the file at name read-only. The mapping reflects the file as
+// it was opened; writing to the file while it is mapped is undefined.
+func Mmap(name string) (*Mapping, error) {
+	return mmap(name)
+}
+
+// Bytes returns the mapped content. Writing to it faults.
+func (m *Mapping) Bytes() []byte { return m.data }
+
+// Close releases the mapping. Values decoded from it with
+// DecodeMsgPackView must not be used afterwards.
+func (m *Mapping) Close() error {
+	data := m.data
+	m.data = nil
+	if m.unmap == nil || data == nil {
+		return nil
+	}
+	return m.unmap(data)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package kit
+
+import "os"
+
+func mmap(name string) (*Mapping, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &Mapping{data: data}, nil
+}
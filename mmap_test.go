@@ -0,0 +1,49 @@
+package kit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"unsafe"
+)
+
+func TestMmap(t *testing.T) {
+	v := New(map[string]any{"name": "kit", "blob": []byte{1, 2, 3}, "tags": []string{"a", ""}})
+	name := filepath.Join(t.TempDir(), "data.msgpack")
+	if err := v.Save(name, FormatMsgPack); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := Mmap(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DecodeMsgPackView(m.Bytes())
+	if err != nil || !got.Equal(v) {
+		t.Fatalf("DecodeMsgPackView = %v, %v", got, err)
+	}
+	data := m.Bytes()
+	inside := func(p *byte) bool {
+		a := uintptr(unsafe.Pointer(p))
+		start := uintptr(unsafe.Pointer(&data[0]))
+		return a >= start && a < start+uintptr(len(data))
+	}
+	if !inside(unsafe.StringData(got.Get("name").String())) || !inside(&got.Get("blob").Bytes()[0]) {
+		t.Error("Strings and Bytes should alias the mapping")
+	}
+	if b := got.Get("blob").Bytes(); cap(b) != len(b) {
+		t.Errorf("Bytes view has spare capacity %d", cap(b))
+	}
+	if err := m.Close(); err != nil || m.Bytes() != nil {
+		t.Errorf("Close = %v", err)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty")
+	os.WriteFile(empty, nil, 0o644)
+	if m, err := Mmap(empty); err != nil || len(m.Bytes()) != 0 || m.Close() != nil {
+		t.Errorf("empty file: %v", err)
+	}
+	if _, err := Mmap(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("missing file should fail")
+	}
+}
@@ -0,0 +1,391 @@
+package kit
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"unsafe"
+)
+
+/* =============================================================================
+   MESSAGEPACK
+   Integral Numbers use the smallest integer encoding, Time uses the standard
+   timestamp extension (-1) and Duration a kit extension (1) holding int64
+   nanoseconds. Map keys are written in sorted order.
+   ============================================================================= */
+
+const (
+	extTimestamp byte = 0xff // -1 as a signed ext type
+	extDuration  byte = 1
+)
+
+// AppendMsgPack appends the MessagePack encoding of v to b.
+func (v Value) AppendMsgPack(b []byte) ([]byte, error) {
+	if v.IsNilCollection() {
+		return append(b, 0xc0), nil
+	}
+	switch v.K {
+	case Nil:
+		return append(b, 0xc0), nil
+	case Bool:
+		if v.N > 0 {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case Number:
+		if i := int64(v.N); v.N == float64(i) && !(i == 0 && math.Signbit(v.N)) {
+			return appendMsgPackInt(b, i), nil
+		}
+		b = append(b, 0xcb)
+		return binary.BigEndian.AppendUint64(b, math.Float64bits(v.N)), nil
+	case Time:
+		ns := int64(v.N)
+		sec, nsec := ns/1e9, ns%1e9
+		if nsec < 0 {
+			sec, nsec = sec-1, nsec+1e9
+		}
+		b = append(b, 0xc7, 12, extTimestamp)
+		b = binary.BigEndian.AppendUint32(b, uint32(nsec))
+		return binary.BigEndian.AppendUint64(b, uint64(sec)), nil
+	case Duration:
+		b = append(b, 0xd7, extDuration)
+		return binary.BigEndian.AppendUint64(b, uint64(int64(v.N))), nil
+	case String:
+		s := v.String()
+		b = appendMsgPackLen(b, len(s), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		return append(b, s...), nil
+	case Bytes:
+		p := v.Bytes()
+		b = appendMsgPackLen(b, len(p), 0, 0, 0xc4, 0xc5, 0xc6)
+		return append(b, p...), nil
+	case Array:
+		var err error
+		a := v.V.([]Value)
+		b = appendMsgPackLen(b, len(a), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, e := range a {
+			if b, err = e.Force().AppendMsgPack(b); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case Map:
+		var err error
+		m := v.mapping()
+		b = appendMsgPackLen(b, len(m), 0x80, 16, 0, 0xde, 0xdf)
+		for _, k := range sortedKeys(m) {
+			b = appendMsgPackLen(b, len(k), 0xa0, 32, 0xd9, 0xda, 0xdb)
+			b = append(b, k...)
+			if b, err = m[k].Force().AppendMsgPack(b); err != nil {
+				return b, err
+			}
+		}
+		return b, nil
+	case Func:
+		if l, ok := v.V.(*lazy); ok {
+			return l.get().AppendMsgPack(b)
+		}
+	case Struct, Any:
+		// Go values are encoded through their JSON form.
+		data, err := json.Marshal(v.V)
+		if err != nil {
+			return b, err
+		}
+		x, err := FromJSON(data)
+		if err != nil {
+			return b, err
+		}
+		return x.AppendMsgPack(b)
+	}
+	return b, fmt.Errorf("kit: cannot encode %s as MessagePack", v.K)
+}
+
+func appendMsgPackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i < 128:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(int8(i)))
+	case i >= 0 && i <= math.MaxUint8:
+		return append(b, 0xcc, byte(i))
+	case i >= 0 && i <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, 0xcd), uint16(i))
+	case i >= 0 && i <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(b, 0xce), uint32(i))
+	case i >= 0:
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), uint64(i))
+	case i >= math.MinInt8:
+		return append(b, 0xd0, byte(int8(i)))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(b, 0xd1), uint16(int16(i)))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(int32(i)))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(i))
+	}
+}
+
+// appendMsgPackLen writes a length header. fix is the fix-format base with
+// fixMax exclusive capacity; c8, c16 and c32 are the sized variants, where
+// c8 == 0 means the family has no 8-bit form.
+func appendMsgPackLen(b []byte, n int, fix byte, fixMax int, c8, c16, c32 byte) []byte {
+	switch {
+	case n < fixMax:
+		return append(b, fix|byte(n))
+	case c8 != 0 && n <= math.MaxUint8:
+		return append(b, c8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, c16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, c32), uint32(n))
+	}
+}
+
+// DecodeMsgPack decodes a single MessagePack value occupying all of data.
+func DecodeMsgPack(data []byte) (Value, error) {
+	d := msgpackDecoder{data: data}
+	return d.decode()
+}
+
+// DecodeMsgPackView is DecodeMsgPack without copying: Strings, Map keys
+// and Bytes in the result refer to data itself, so decoding a large
+// dataset from a Mapping adds little beyond its containers. data must not
+// be modified, or unmapped, while anything decoded from it is in use, and
+// the Bytes are read-only.
+//
+//	m, err := kit.Mmap("geoip.msgpack")
+//	...
+//	defer m.Close()
+//	db, err := kit.DecodeMsgPackView(m.Bytes())
+func DecodeMsgPackView(data []byte) (Value, error) {
+	d := msgpackDecoder{data: data, view: true}
+	return d.decode()
+}
+
+func (d *msgpackDecoder) decode() (Value, error) {
+	v, err := d.value()
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	if d.pos != len(d.data) {
+		return Value{K: Invalid}, fmt.Errorf("kit: %d trailing bytes after MessagePack value", len(d.data)-d.pos)
+	}
+	return v, nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using MessagePack.
+func (v Value) MarshalBinary() ([]byte, error) {
+	return v.AppendMsgPack(nil)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using MessagePack.
+func (v *Value) UnmarshalBinary(data []byte) error {
+	out, err := DecodeMsgPack(data)
+	if err != nil {
+		return err
+	}
+	*v = out
+	return nil
+}
+
+var errShortMsgPack = errors.New("kit: unexpected end of MessagePack data")
+
+type msgpackDecoder struct {
+	data  []byte
+	pos   int
+	depth int
+	view  bool // alias data instead of copying
+}
+
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data)-d.pos {
+		return nil, errShortMsgPack
+	}
+	p := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return p, nil
+}
+
+func (d *msgpackDecoder) uint(n int) (uint64, error) {
+	p, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range p {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+func (d *msgpackDecoder) value() (Value, error) {
+	p, err := d.read(1)
+	if err != nil {
+		return Value{}, err
+	}
+	c := p[0]
+	switch {
+	case c <= 0x7f:
+		return Value{K: Number, N: float64(c)}, nil
+	case c >= 0xe0:
+		return Value{K: Number, N: float64(int8(c))}, nil
+	case c&0xf0 == 0x80:
+		return d.mapping(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.array(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		return d.str(int(c & 0x1f))
+	}
+
+	switch c {
+	case 0xc0:
+		return Value{K: Nil}, nil
+	case 0xc2:
+		return Value{K: Bool}, nil
+	case 0xc3:
+		return Value{K: Bool, N: 1}, nil
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return Value{}, err
+		}
+		p, err := d.read(int(n))
+		if err != nil {
+			return Value{}, err
+		}
+		if d.view && len(p) > 0 {
+			// Cap the view so appending to it cannot write into data.
+			return Value{K: Bytes, V: p[:len(p):len(p)]}, nil
+		}
+		// Copy into a non-nil slice: a nil one would encode as null.
+		return Value{K: Bytes, V: append(make([]byte, 0, len(p)), p...)}, nil
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return Value{}, err
+		}
+		return d.ext(int(n))
+	case 0xca:
+		u, err := d.uint(4)
+		return Value{K: Number, N: float64(math.Float32frombits(uint32(u)))}, err
+	case 0xcb:
+		u, err := d.uint(8)
+		return Value{K: Number, N: math.Float64frombits(u)}, err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.uint(1 << (c - 0xcc))
+		return Value{K: Number, N: float64(u)}, err
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		u, err := d.uint(size)
+		shift := 64 - 8*size
+		return Value{K: Number, N: float64(int64(u<<shift) >> shift)}, err
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		return d.ext(1 << (c - 0xd4))
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return Value{}, err
+		}
+		return d.str(int(n))
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return Value{}, err
+		}
+		return d.array(int(n))
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return Value{}, err
+		}
+		return d.mapping(int(n))
+	}
+	return Value{}, fmt.Errorf("kit: invalid MessagePack byte 0x%02x", c)
+}
+
+func (d *msgpackDecoder) nest() error {
+	if d.depth++; d.depth > maxNesting {
+		return fmt.Errorf("kit: MessagePack nesting exceeds %d levels", maxNesting)
+	}
+	return nil
+}
+
+func (d *msgpackDecoder) str(n int) (Value, error) {
+	p, err := d.read(n)
+	if err != nil {
+		return Value{}, err
+	}
+	if d.view && len(p) > 0 {
+		return Value{K: String, V: unsafe.String(&p[0], len(p))}, nil
+	}
+	return Value{K: String, V: string(p)}, nil
+}
+
+func (d *msgpackDecoder) array(n int) (Value, error) {
+	// Every element takes at least one byte; reject counts the input cannot hold.
+	if n > len(d.data)-d.pos {
+		return Value{}, errShortMsgPack
+	}
+	if err := d.nest(); err != nil {
+		return Value{}, err
+	}
+	defer func() { d.depth-- }()
+	out := make([]Value, n)
+	for i := range out {
+		e, err := d.value()
+		if err != nil {
+			return Value{}, err
+		}
+		out[i] = e
+	}
+	return Value{K: Array, V: out}, nil
+}
+
+func (d *msgpackDecoder) mapping(n int) (Value, error) {
+	if 2*n > len(d.data)-d.pos {
+		return Value{}, errShortMsgPack
+	}
+	if err := d.nest(); err != nil {
+		return Value{}, err
+	}
+	defer func() { d.depth-- }()
+	out := make(map[string]Value, n)
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return Value{}, err
+		}
+		e, err := d.value()
+		if err != nil {
+			return Value{}, err
+		}
+		out[k.Text()] = e
+	}
+	return Value{K: Map, V: out}, nil
+}
+
+func (d *msgpackDecoder) ext(n int) (Value, error) {
+	typ, err := d.read(1)
+	if err != nil {
+		return Value{}, err
+	}
+	p, err := d.read(n)
+	if err != nil {
+		return Value{}, err
+	}
+	switch {
+	case typ[0] == extTimestamp && n == 4:
+		return Value{K: Time, N: float64(int64(binary.BigEndian.Uint32(p)) * 1e9)}, nil
+	case typ[0] == extTimestamp && n == 8:
+		u := binary.BigEndian.Uint64(p)
+		return Value{K: Time, N: float64(int64(u&(1<<34-1))*1e9 + int64(u>>34))}, nil
+	case typ[0] == extTimestamp && n == 12:
+		nsec := int64(binary.BigEndian.Uint32(p))
+		sec := int64(binary.BigEndian.Uint64(p[4:]))
+		return Value{K: Time, N: float64(sec*1e9 + nsec)}, nil
+	case typ[0] == extDuration && n == 8:
+		return Value{K: Duration, N: float64(int64(binary.BigEndian.Uint64(p)))}, nil
+	}
+	return Value{}, fmt.Errorf("kit: unsupported MessagePack extension %d", int8(typ[0]))
+}
@@ -0,0 +1,249 @@
+package kit
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+/* =============================================================================
+   STRICT ACCESS
+   The accessors on Value return zero values on a kind mismatch. The Try*
+   variants report it as an *AccessError instead, Must* panics with one, and
+   Strict carries the same behavior through whole paths.
+   ============================================================================= */
+
+// AccessError reports a missing path or a kind mismatch.
+type AccessError struct {
+	Path string // dot-separated, "" for the accessed Value itself
+	Want Kind
+	Got  Kind // Invalid when the path is missing
+	Msg  string
+}
+
+func (e *AccessError) Error() string {
+	s := "kit: "
+	if e.Path != "" {
+		s += e.Path + ": "
+	}
+	switch {
+	case e.Msg != "":
+		return s + e.Msg
+	case e.Got == Invalid:
+		return s + "missing"
+	}
+	return s + "want " + e.Want.String() + ", got " + e.Got.String()
+}
+
+func (v Value) want(k Kind) error {
+	if v.K != k {
+		return &AccessError{Want: k, Got: v.K}
+	}
+	return nil
+}
+
+// TryString returns the String held by v.
+func (v Value) TryString() (string, error) {
+	v = v.Force()
+	return v.String(), v.want(String)
+}
+
+// TryFloat returns the Number held by v.
+func (v Value) TryFloat() (float64, error) {
+	v = v.Force()
+	return v.N, v.want(Number)
+}
+
+// TryInt returns the Number held by v, which must be integral and within
+// the int64 range.
+func (v Value) TryInt() (int64, error) {
+	v = v.Force()
+	if err := v.want(Number); err != nil {
+		return 0, err
+	}
+	if v.N != math.Trunc(v.N) || v.N < math.MinInt64 || v.N >= math.MaxInt64 {
+		return 0, &AccessError{Want: Number, Got: Number, Msg: "not an int64: " + strconv.FormatFloat(v.N, 'g', -1, 64)}
+	}
+	return int64(v.N), nil
+}
+
+// TryBool returns the Bool held by v.
+func (v Value) TryBool() (bool, error) {
+	v = v.Force()
+	return v.N > 0, v.want(Bool)
+}
+
+// TryTime returns the Time held by v.
+func (v Value) TryTime() (time.Time, error) {
+	v = v.Force()
+	if err := v.want(Time); err != nil {
+		return time.Time{}, err
+	}
+	return v.goTime(), nil
+}
+
+// TryDuration returns the Duration held by v.
+func (v Value) TryDuration() (time.Duration, error) {
+	v = v.Force()
+	return time.Duration(int64(v.N)), v.want(Duration)
+}
+
+// TryMap returns the entries of a Map; sharded Maps are flattened.
+func (v Value) TryMap() (map[string]Value, error) {
+	v = v.Force()
+	if err := v.want(Map); err != nil {
+		return nil, err
+	}
+	return v.mapping(), nil
+}
+
+// TryArray returns the elements of an Array.
+func (v Value) TryArray() ([]Value, error) {
+	v = v.Force()
+	if err := v.want(Array); err != nil {
+		return nil, err
+	}
+	return v.V.([]Value), nil
+}
+
+func must[T any](x T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// MustString is TryString, panicking with an *AccessError on failure.
+func (v Value) MustString() string { return must(v.TryString()) }
+
+// MustFloat is TryFloat, panicking with an *AccessError on failure.
+func (v Value) MustFloat() float64 { return must(v.TryFloat()) }
+
+// MustInt is TryInt, panicking with an *AccessError on failure.
+func (v Value) MustInt() int64 { return must(v.TryInt()) }
+
+// MustBool is TryBool, panicking with an *AccessError on failure.
+func (v Value) MustBool() bool { return must(v.TryBool()) }
+
+// MustTime is TryTime, panicking with an *AccessError on failure.
+func (v Value) MustTime() time.Time { return must(v.TryTime()) }
+
+// MustDuration is TryDuration, panicking with an *AccessError on failure.
+func (v Value) MustDuration() time.Duration { return must(v.TryDuration()) }
+
+// MustMap is TryMap, panicking with an *AccessError on failure.
+func (v Value) MustMap() map[string]Value { return must(v.TryMap()) }
+
+// MustArray is TryArray, panicking with an *AccessError on failure.
+func (v Value) MustArray() []Value { return must(v.TryArray()) }
+
+// Strict is a view of a Value in which every access fails loudly: a missing
+// key or index, or a kind mismatch, panics with an *AccessError naming the
+// full path from the root. Use it in tests and initialization code:
+//
+//	port := cfg.Strict().Path("server.port").Int()
+type Strict struct {
+	v    Value
+	path string
+}
+
+// Strict returns a strict view of v.
+func (v Value) Strict() Strict { return Strict{v: v.Force()} }
+
+// Value returns the Value behind the view.
+func (s Strict) Value() Value { return s.v }
+
+// Get returns the entry of a Map or the field of a Struct at key.
+func (s Strict) Get(key string) Strict {
+	if s.v.K != Map && s.v.K != Struct {
+		s.fail(&AccessError{Want: Map, Got: s.v.K})
+	}
+	return s.child(key, key)
+}
+
+// Index returns the element of an Array at i.
+func (s Strict) Index(i int) Strict {
+	if s.v.K != Array {
+		s.fail(&AccessError{Want: Array, Got: s.v.K})
+	}
+	return s.child(strconv.Itoa(i), i)
+}
+
+// Path walks a dot-separated path as Value.Path does.
+func (s Strict) Path(path string) Strict {
+	for _, seg := range splitPath(path) {
+		if s.v.K == Array {
+			i, err := strconv.Atoi(seg)
+			if err != nil {
+				s.fail(&AccessError{Path: seg, Want: Array, Got: Array, Msg: "not an index: " + seg})
+			}
+			s = s.Index(i)
+			continue
+		}
+		s = s.Get(seg)
+	}
+	return s
+}
+
+func (s Strict) child(name string, key any) Strict {
+	x, ok := s.v.lookup(key)
+	out := Strict{v: x, path: name}
+	if s.path != "" {
+		out.path = s.path + "." + name
+	}
+	if !ok {
+		out.fail(&AccessError{})
+	}
+	return out
+}
+
+// fail panics with err, located at the path of s.
+func (s Strict) fail(err error) {
+	if ae, ok := err.(*AccessError); ok {
+		ae.Path = joinPath(s.path, ae.Path)
+	}
+	panic(err)
+}
+
+func joinPath(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "." + b
+}
+
+func strictGet[T any](s Strict, fn func(Value) (T, error)) T {
+	x, err := fn(s.v)
+	if err != nil {
+		s.fail(err)
+	}
+	return x
+}
+
+// MustString returns the String at s. It is not named String so that
+// printing a Strict with fmt cannot panic.
+func (s Strict) MustString() string { return strictGet(s, Value.TryString) }
+
+// Float returns the Number at s.
+func (s Strict) Float() float64 { return strictGet(s, Value.TryFloat) }
+
+// Int returns the integral Number at s.
+func (s Strict) Int() int64 { return strictGet(s, Value.TryInt) }
+
+// Bool returns the Bool at s.
+func (s Strict) Bool() bool { return strictGet(s, Value.TryBool) }
+
+// Time returns the Time at s.
+func (s Strict) Time() time.Time { return strictGet(s, Value.TryTime) }
+
+// Duration returns the Duration at s.
+func (s Strict) Duration() time.Duration { return strictGet(s, Value.TryDuration) }
+
+// Map returns the entries of the Map at s.
+func (s Strict) Map() map[string]Value { return strictGet(s, Value.TryMap) }
+
+// Array returns the elements of the Array at s.
+func (s Strict) Array() []Value { return strictGet(s, Value.TryArray) }
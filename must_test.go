@@ -0,0 +1,70 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValue_Try(t *testing.T) {
+	if s, err := New("x").TryString(); s != "x" || err != nil {
+		t.Errorf("TryString = %q, %v", s, err)
+	}
+	_, err := New(1).TryString()
+	var ae *AccessError
+	if !errors.As(err, &ae) || ae.Want != String || ae.Got != Number || err.Error() != "kit: want String, got Number" {
+		t.Errorf("TryString(Number) err = %v", err)
+	}
+	if _, err := New(1.5).TryInt(); err == nil {
+		t.Error("TryInt(1.5) should fail")
+	}
+	if n, err := New(42).TryInt(); n != 42 || err != nil {
+		t.Errorf("TryInt = %d, %v", n, err)
+	}
+	if d, err := New(time.Second).TryDuration(); d != time.Second || err != nil {
+		t.Errorf("TryDuration = %v, %v", d, err)
+	}
+	if New(map[string]any{"a": 1}).MustMap()["a"].Int() != 1 || len(New([]int{1, 2}).MustArray()) != 2 {
+		t.Error("MustMap/MustArray")
+	}
+}
+
+func TestValue_Strict(t *testing.T) {
+	cfg := New(map[string]any{
+		"server": map[string]any{"port": 8080, "hosts": []any{"a", "b"}},
+		"debug":  "yes",
+	})
+	s := cfg.Strict()
+	if s.Path("server.port").Int() != 8080 || s.Get("server").Get("hosts").Index(1).MustString() != "b" {
+		t.Error("Strict happy path")
+	}
+	if s.Path("server.hosts.0").MustString() != "a" {
+		t.Error("Strict.Path through Array")
+	}
+	_ = fmt.Sprint(s.Get("server").Get("port")) // not a Stringer: must not panic
+
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{"missing key", func() { s.Path("server.timeout") }, "kit: server.timeout: missing"},
+		{"out of range", func() { s.Path("server.hosts").Index(5) }, "kit: server.hosts.5: missing"},
+		{"wrong kind", func() { s.Get("debug").Bool() }, "kit: debug: want Bool, got String"},
+		{"not a map", func() { s.Path("server.port.x") }, "kit: server.port: want Map, got Number"},
+		{"bad index", func() { s.Path("server.hosts.x") }, "kit: server.hosts.x: not an index: x"},
+		{"Must", func() { New(nil).MustString() }, "kit: want String, got Nil"},
+	}
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				err, _ := recover().(*AccessError)
+				if err == nil || err.Error() != tt.want {
+					t.Errorf("%s: panic = %v, want %q", tt.name, err, tt.want)
+				}
+			}()
+			tt.fn()
+		}()
+	}
+}
@@ -0,0 +1,426 @@
+package kit
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   COPY-ON-WRITE MUTATION
+   Values stay immutable: every write copies the containers along the path
+   and returns a new root, so readers holding the old root never see a
+   partially applied change.
+   ============================================================================= */
+
+// Path resolves a dot-separated path such as "user.tags.0".
+// Numeric segments index into Arrays, Strings and Bytes.
+func (v Value) Path(path string) Value {
+	cur := v.Force()
+	for _, seg := range splitPath(path) {
+		cur = cur.step(seg)
+		if cur.IsBlank() {
+			return cur
+		}
+	}
+	return cur
+}
+
+// Has reports whether every segment of path is present, using the same
+// segments as At: string keys for Maps and Struct fields, int indexes for
+// Arrays, Strings and Bytes. Unlike At(...).IsNil(), a key bound to Nil is
+// present, which is what PATCH handlers need to tell "clear this field"
+// from "leave it alone".
+func (v Value) Has(path ...any) bool {
+	cur := v
+	for _, seg := range path {
+		next, ok := cur.lookup(seg)
+		if !ok {
+			return false
+		}
+		cur = next
+	}
+	return true
+}
+
+// Exists is Has for a dot-separated path in the syntax of Path.
+func (v Value) Exists(path string) bool {
+	cur := v
+	for _, seg := range splitPath(path) {
+		var key any = seg
+		if k := cur.Force().K; k == Array || k == String || k == Bytes {
+			i, err := strconv.Atoi(seg)
+			if err != nil {
+				return false
+			}
+			key = i
+		}
+		next, ok := cur.lookup(key)
+		if !ok {
+			return false
+		}
+		cur = next
+	}
+	return true
+}
+
+// lookup returns the child of v at a string key or int index and whether
+// it is present.
+func (v Value) lookup(seg any) (Value, bool) {
+	v = v.Force()
+	switch key := seg.(type) {
+	case string:
+		switch v.K {
+		case Map:
+			if s, ok := v.V.(*sharded); ok {
+				x, ok := s.get(key)
+				return x.Force(), ok
+			}
+			x, ok := v.V.(map[string]Value)[key]
+			return x.Force(), ok
+		case Struct:
+			rv := reflect.Indirect(reflect.ValueOf(v.V))
+			if rv.Kind() == reflect.Struct {
+				if _, ok := structFieldByKey(rv, key); ok {
+					return v.Get(key), true
+				}
+			}
+		}
+	case int:
+		if v.K == Array || v.K == String || v.K == Bytes {
+			if key >= 0 && key < v.Len() {
+				return v.Index(key), true
+			}
+		}
+	}
+	return Value{K: Nil}, false
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+func (v Value) step(seg string) Value {
+	switch v.K {
+	case Array, String, Bytes:
+		if i, err := strconv.Atoi(seg); err == nil {
+			return v.Index(i)
+		}
+		return Value{K: Nil}
+	default:
+		return v.Get(seg)
+	}
+}
+
+// Set returns a copy of v with key bound to x. Nil and Invalid receivers
+// start a new Map; Arrays accept an in-range numeric key. Sharded Maps are
+// written in place and returned as is, and so are Structs wrapping a
+// pointer: the field named key, resolved as Get does, is assigned with the
+// conversions of Bind, so Set patches the caller's object. A Struct held by
+// value is copied and the copy returned.
+func (v Value) Set(key string, x any) Value {
+	out, err := v.setIn([]string{key}, New(x))
+	if err != nil {
+		return Value{K: Invalid}
+	}
+	return out
+}
+
+// Delete returns a copy of v without key. Missing keys are not an error.
+func (v Value) Delete(key string) Value {
+	out, err := v.deleteIn([]string{key})
+	if err != nil {
+		return Value{K: Invalid}
+	}
+	return out
+}
+
+func (v Value) setIn(path []string, x Value) (Value, error) {
+	if len(path) == 0 {
+		return x, nil
+	}
+	key, rest := path[0], path[1:]
+	v = v.Force()
+	switch v.K {
+	case Invalid, Nil:
+		child, err := Value{K: Nil}.setIn(rest, x)
+		if err != nil {
+			return v, err
+		}
+		return Value{K: Map, V: map[string]Value{key: child}}, nil
+	case Map:
+		if s, ok := v.V.(*sharded); ok {
+			if s.frozen {
+				return Value{K: Map, V: s.flatten()}.setIn(path, x)
+			}
+			return v, s.setIn(key, rest, x)
+		}
+		src := v.V.(map[string]Value)
+		child, err := src[key].setIn(rest, x)
+		if err != nil {
+			return v, err
+		}
+		out := make(map[string]Value, len(src)+1)
+		for k, e := range src {
+			out[k] = e
+		}
+		out[key] = child
+		return Value{K: Map, V: out}, nil
+	case Array:
+		src := v.V.([]Value)
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(src) {
+			return v, fmt.Errorf("kit: index %q out of range for Array of %d", key, len(src))
+		}
+		child, err := src[i].setIn(rest, x)
+		if err != nil {
+			return v, err
+		}
+		out := make([]Value, len(src))
+		copy(out, src)
+		out[i] = child
+		return Value{K: Array, V: out}, nil
+	case Struct:
+		rv := reflect.ValueOf(v.V)
+		if rv.Kind() == reflect.Pointer {
+			if rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+				return v, fmt.Errorf("kit: cannot set %q on %s", key, rv.Type())
+			}
+			return v, setStructIn(rv.Elem(), path, x, false)
+		}
+		if rv.Kind() != reflect.Struct {
+			return v, fmt.Errorf("kit: cannot set %q on %s", key, rv.Type())
+		}
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		if err := setStructIn(cp, path, x, true); err != nil {
+			return v, err
+		}
+		return Value{K: Struct, V: cp.Interface()}, nil
+	default:
+		return v, fmt.Errorf("kit: cannot set %q on %s", key, v.K)
+	}
+}
+
+func (v Value) deleteIn(path []string) (Value, error) {
+	if len(path) == 0 {
+		return v, nil
+	}
+	key, rest := path[0], path[1:]
+	v = v.Force()
+	switch v.K {
+	case Invalid, Nil:
+		return v, nil
+	case Map:
+		if s, ok := v.V.(*sharded); ok {
+			if s.frozen {
+				return Value{K: Map, V: s.flatten()}.deleteIn(path)
+			}
+			return v, s.deleteIn(key, rest)
+		}
+		src := v.V.(map[string]Value)
+		old, ok := src[key]
+		if !ok {
+			return v, nil
+		}
+		out := make(map[string]Value, len(src))
+		for k, e := range src {
+			out[k] = e
+		}
+		if len(rest) == 0 {
+			delete(out, key)
+			return Value{K: Map, V: out}, nil
+		}
+		child, err := old.deleteIn(rest)
+		if err != nil {
+			return v, err
+		}
+		out[key] = child
+		return Value{K: Map, V: out}, nil
+	case Array:
+		src := v.V.([]Value)
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(src) {
+			return v, nil
+		}
+		if len(rest) == 0 {
+			out := make([]Value, 0, len(src)-1)
+			out = append(out, src[:i]...)
+			return Value{K: Array, V: append(out, src[i+1:]...)}, nil
+		}
+		child, err := src[i].deleteIn(rest)
+		if err != nil {
+			return v, err
+		}
+		out := make([]Value, len(src))
+		copy(out, src)
+		out[i] = child
+		return Value{K: Array, V: out}, nil
+	default:
+		return v, fmt.Errorf("kit: cannot delete %q on %s", key, v.K)
+	}
+}
+
+/* =============================================================================
+   TRANSACTIONS
+   ============================================================================= */
+
+// Tx stages a batch of writes against a private copy of a Value tree.
+type Tx struct {
+	root Value
+	err  error
+}
+
+// Get reads path from the staged tree, including writes made earlier in the
+// same transaction.
+func (tx *Tx) Get(path string) Value { return tx.root.Path(path) }
+
+// Set stages x at the dot-separated path, creating intermediate Maps.
+func (tx *Tx) Set(path string, x any) error {
+	if tx.err != nil {
+		return tx.err
+	}
+	out, err := tx.root.setIn(splitPath(path), New(x))
+	if err != nil {
+		tx.err = err
+		return err
+	}
+	tx.root = out
+	return nil
+}
+
+// Delete stages the removal of path.
+func (tx *Tx) Delete(path string) error {
+	if tx.err != nil {
+		return tx.err
+	}
+	out, err := tx.root.deleteIn(splitPath(path))
+	if err != nil {
+		tx.err = err
+		return err
+	}
+	tx.root = out
+	return nil
+}
+
+// Update runs fn against a transaction and returns the resulting tree.
+// If fn returns an error, or any staged write failed, every write is
+// discarded and the receiver is returned unchanged alongside the error.
+// Sharded Maps are updated in place while all shards are locked.
+func (v Value) Update(fn func(tx *Tx) error) (Value, error) {
+	if s, ok := v.V.(*sharded); ok && v.K == Map && !s.frozen {
+		return v, s.update(fn)
+	}
+	tx := &Tx{root: v}
+	if err := fn(tx); err != nil {
+		return v, err
+	}
+	if tx.err != nil {
+		return v, tx.err
+	}
+	return tx.root, nil
+}
+
+type pruneOptions struct {
+	zero bool
+}
+
+// PruneOption configures Prune.
+type PruneOption func(*pruneOptions)
+
+// PruneZero makes Prune also drop zero scalars: 0, false, "", empty Bytes,
+// the zero Duration and the Unix epoch Time.
+func PruneZero() PruneOption {
+	return func(o *pruneOptions) { o.zero = true }
+}
+
+// Prune returns a copy of v with "omitempty" semantics applied recursively:
+// Map entries holding Nil, Invalid, or a Map or Array that is empty after
+// pruning are removed. Array elements are pruned inside but never removed,
+// since their positions carry meaning. Sharded Maps come back as plain Maps.
+func (v Value) Prune(opts ...PruneOption) Value {
+	var o pruneOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return v.prune(&o)
+}
+
+func (v Value) prune(o *pruneOptions) Value {
+	v = v.Force()
+	switch v.K {
+	case Map:
+		m := v.mapping()
+		out := make(map[string]Value, len(m))
+		for k, e := range m {
+			if e = e.prune(o); !e.prunable(o) {
+				out[k] = e
+			}
+		}
+		return Value{K: Map, V: out}
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return v
+		}
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = e.prune(o)
+		}
+		return Value{K: Array, V: out}
+	}
+	return v
+}
+
+func (v Value) prunable(o *pruneOptions) bool {
+	switch v.K {
+	case Invalid, Nil:
+		return true
+	case Map, Array:
+		return v.Len() == 0
+	case Number, Bool, Time, Duration:
+		return o.zero && v.N == 0
+	case String, Bytes:
+		return o.zero && v.Len() == 0
+	}
+	return false
+}
+
+// Merge deep-merges layers from left to right, so later layers take
+// precedence: Maps merge key by key, any other kind replaces what came
+// before, and Invalid layers or entries are skipped. A Nil entry is kept,
+// which lets a layer clear a key set by an earlier one. No input is
+// modified; sharded Maps come back as plain Maps.
+func Merge(layers ...Value) Value {
+	out := Value{K: Invalid}
+	for _, l := range layers {
+		out = merge(out, l.Force())
+	}
+	return out
+}
+
+func merge(base, over Value) Value {
+	switch {
+	case over.K == Invalid:
+		return base
+	case over.K != Map || base.K != Map:
+		return over
+	}
+	bm, om := base.mapping(), over.mapping()
+	out := make(map[string]Value, len(bm)+len(om))
+	for k, e := range bm {
+		out[k] = e
+	}
+	for k, e := range om {
+		if prev, ok := out[k]; ok {
+			out[k] = merge(prev.Force(), e.Force())
+		} else if e.K != Invalid {
+			out[k] = e
+		}
+	}
+	return Value{K: Map, V: out}
+}
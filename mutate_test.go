@@ -0,0 +1,166 @@
+package kit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValue_SetCopyOnWrite(t *testing.T) {
+	orig := New(map[string]any{"a": 1})
+	next := orig.Set("b", 2)
+
+	if orig.Get("b").IsValid() {
+		t.Error("Set modified the receiver")
+	}
+	if next.Get("a").Int() != 1 || next.Get("b").Int() != 2 {
+		t.Errorf("Set result = %v, %v", next.Get("a").N, next.Get("b").N)
+	}
+	if next.Delete("a").Get("a").IsValid() {
+		t.Error("Delete did not remove key")
+	}
+	if !New(3).Set("x", 1).IsInvalid() {
+		t.Error("Set on Number should be Invalid")
+	}
+}
+
+func TestValue_UpdateCommit(t *testing.T) {
+	cfg := New(map[string]any{"db": map[string]any{"host": "a", "port": 1}})
+
+	out, err := cfg.Update(func(tx *Tx) error {
+		tx.Set("db.host", "b")
+		tx.Delete("db.port")
+		return tx.Set("cache.ttl", 30)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Path("db.host").String() != "b" || out.Path("db.port").IsValid() {
+		t.Errorf("staged writes not applied: %v", out.Path("db"))
+	}
+	if out.Path("cache.ttl").Int() != 30 {
+		t.Error("intermediate Map not created")
+	}
+	if cfg.Path("db.host").String() != "a" {
+		t.Error("Update modified the receiver")
+	}
+}
+
+func TestValue_UpdateRollback(t *testing.T) {
+	cfg := New(map[string]any{"name": "x", "list": []int{1}})
+	boom := errors.New("boom")
+
+	out, err := cfg.Update(func(tx *Tx) error {
+		tx.Set("name", "y")
+		return boom
+	})
+	if err != boom || out.Get("name").String() != "x" {
+		t.Errorf("rollback on fn error failed: %v %q", err, out.Get("name").String())
+	}
+
+	out, err = cfg.Update(func(tx *Tx) error {
+		tx.Set("name", "y")
+		tx.Set("list.5", 1) // out of range, ignored by fn
+		return nil
+	})
+	if err == nil || out.Get("name").String() != "x" {
+		t.Error("rollback on failed staged write did not happen")
+	}
+}
+
+func TestValue_Has(t *testing.T) {
+	type profile struct{ Bio *string }
+	v := New(map[string]any{
+		"name":    nil,
+		"tags":    []any{"a", nil},
+		"profile": profile{},
+		"nested":  map[string]any{"x": 1},
+	})
+	tests := []struct {
+		path []any
+		want bool
+	}{
+		{[]any{"name"}, true},
+		{[]any{"missing"}, false},
+		{[]any{"tags", 1}, true},
+		{[]any{"tags", 2}, false},
+		{[]any{"tags", "0"}, false},
+		{[]any{"profile", "Bio"}, true},
+		{[]any{"profile", "Age"}, false},
+		{[]any{"nested", "x"}, true},
+		{[]any{"nested", "x", "y"}, false},
+		{nil, true},
+	}
+	for _, tt := range tests {
+		if got := v.Has(tt.path...); got != tt.want {
+			t.Errorf("Has(%v) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+
+	if !v.Exists("tags.1") || v.Exists("tags.x") || !v.Exists("nested.x") || v.Exists("name.x") {
+		t.Error("Exists")
+	}
+	if !v.Get("name").IsNil() || v.At("name").IsInvalid() {
+		t.Error("At and Get are unchanged")
+	}
+}
+
+func TestValue_Prune(t *testing.T) {
+	v := New(map[string]any{
+		"id":    1,
+		"note":  nil,
+		"tags":  []any{},
+		"meta":  map[string]any{"a": nil, "b": map[string]any{}},
+		"items": []any{map[string]any{"x": nil, "y": 0}, nil},
+		"count": 0,
+		"name":  "",
+	})
+	want := New(map[string]any{
+		"id":    1,
+		"items": []any{map[string]any{"y": 0}, nil},
+		"count": 0,
+		"name":  "",
+	})
+	if got := v.Prune(); !got.Equal(want) {
+		t.Errorf("Prune() = %v, want %v", got, want)
+	}
+
+	wantZero := New(map[string]any{
+		"id":    1,
+		"items": []any{map[string]any{}, nil},
+	})
+	if got := v.Prune(PruneZero()); !got.Equal(wantZero) {
+		t.Errorf("Prune(PruneZero()) = %v, want %v", got, wantZero)
+	}
+	if got := New(map[string]any{"a": nil}).Prune(); got.K != Map || got.Len() != 0 {
+		t.Errorf("top level = %v", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	base := New(map[string]any{
+		"db":   map[string]any{"host": "localhost", "port": 5432},
+		"tags": []any{"a"},
+		"keep": true,
+	})
+	over := New(map[string]any{
+		"db":   map[string]any{"port": 6543, "user": nil},
+		"tags": []any{"b", "c"},
+	})
+	got := Merge(base, Value{K: Invalid}, over)
+
+	if got.Path("db.host").Text() != "localhost" || got.Path("db.port").Int() != 6543 {
+		t.Errorf("db = %v", got.Get("db"))
+	}
+	if !got.Exists("db.user") || !got.Path("db.user").IsNil() {
+		t.Error("Nil entry not kept")
+	}
+	if got.Get("tags").Len() != 2 || !got.Get("keep").Truthy() {
+		t.Errorf("merged = %v", got)
+	}
+	if base.Path("db.port").Int() != 5432 || base.Exists("db.user") {
+		t.Error("Merge modified its input")
+	}
+	if Merge().K != Invalid || Merge(New(1), New("x")).Text() != "x" {
+		t.Error("Merge of scalars")
+	}
+}
@@ -0,0 +1,158 @@
+package kit
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   UNICODE NORMALIZATION
+   Canonical decomposition (NFD) and composition (NFC) per UAX #15, using the
+   tables in normtables.go plus the algorithmic Hangul syllable mapping.
+   ============================================================================= */
+
+//go:generate go run ./internal/gen -tables norm -o normtables.go
+
+const (
+	hangulBase  = 0xAC00
+	hangulL     = 0x1100
+	hangulV     = 0x1161
+	hangulT     = 0x11A7
+	hangulLN    = 19
+	hangulVN    = 21
+	hangulTN    = 28
+	hangulCount = hangulLN * hangulVN * hangulTN
+)
+
+// NFD returns the canonical decomposition of s.
+func NFD(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(decompose(s))
+}
+
+// NFC returns the canonical composition of s, the form most text is stored
+// and compared in.
+func NFC(s string) string {
+	if isASCII(s) {
+		return s
+	}
+	return string(compose(decompose(s)))
+}
+
+// NFC returns a String in canonical composed form, so that "é" typed as one
+// rune and as "e" plus a combining accent become identical.
+func (v Value) NFC() Value { return v.mapString(NFC) }
+
+// NFD returns a String in canonical decomposed form.
+func (v Value) NFD() Value { return v.mapString(NFD) }
+
+// EqualFold reports whether two Strings are equal after NFC normalization
+// and Unicode case folding. Values of other kinds are compared with Equal.
+func (a Value) EqualFold(b Value) bool {
+	a, b = a.Force(), b.Force()
+	if a.K != String || b.K != String {
+		return a.Equal(b)
+	}
+	return strings.EqualFold(NFC(a.String()), NFC(b.String()))
+}
+
+// GetFold looks up key in a Map, falling back to the first key in sorted
+// order that matches under EqualFold when there is no exact entry.
+func (v Value) GetFold(key string) Value {
+	v = v.Force()
+	if v.K != Map {
+		return Value{K: Nil}
+	}
+	m := v.mapping()
+	if x, ok := m[key]; ok {
+		return x.Force()
+	}
+	key = NFC(key)
+	for _, k := range sortedKeys(m) {
+		if strings.EqualFold(NFC(k), key) {
+			return m[k].Force()
+		}
+	}
+	return Value{K: Nil}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+func decompose(s string) []rune {
+	out := make([]rune, 0, len(s))
+	for _, r := range s {
+		switch d, ok := canonicalDecomp[r]; {
+		case r >= hangulBase && r < hangulBase+hangulCount:
+			i := r - hangulBase
+			out = append(out, hangulL+i/(hangulVN*hangulTN), hangulV+i%(hangulVN*hangulTN)/hangulTN)
+			if t := i % hangulTN; t != 0 {
+				out = append(out, hangulT+t)
+			}
+		case ok:
+			out = append(out, []rune(d)...)
+		default:
+			out = append(out, r)
+		}
+	}
+	// Canonical ordering: stable-sort each run of non-starters by class.
+	for i := 0; i < len(out); {
+		if combiningClass[out[i]] == 0 {
+			i++
+			continue
+		}
+		j := i + 1
+		for j < len(out) && combiningClass[out[j]] != 0 {
+			j++
+		}
+		slices.SortStableFunc(out[i:j], func(a, b rune) int {
+			return int(combiningClass[a]) - int(combiningClass[b])
+		})
+		i = j
+	}
+	return out
+}
+
+func compose(rs []rune) []rune {
+	if len(rs) == 0 {
+		return rs
+	}
+	starter, last := -1, uint8(0)
+	out := rs[:0]
+	for _, r := range rs {
+		cc := combiningClass[r]
+		if starter >= 0 && (last < cc || last == 0 && len(out)-1 == starter) {
+			if c, ok := composePair(out[starter], r); ok {
+				out[starter] = c
+				continue
+			}
+		}
+		if cc == 0 {
+			starter = len(out)
+		}
+		last = cc
+		out = append(out, r)
+	}
+	return out
+}
+
+func composePair(a, b rune) (rune, bool) {
+	switch {
+	case a >= hangulL && a < hangulL+hangulLN && b >= hangulV && b < hangulV+hangulVN:
+		return hangulBase + ((a-hangulL)*hangulVN+b-hangulV)*hangulTN, true
+	case a >= hangulBase && a < hangulBase+hangulCount && (a-hangulBase)%hangulTN == 0 &&
+		b > hangulT && b < hangulT+hangulTN:
+		return a + b - hangulT, true
+	}
+	c, ok := canonicalComp[[2]rune{a, b}]
+	return c, ok
+}
@@ -0,0 +1,39 @@
+package kit
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	composed, decomposed := "Việt", "Việt"
+	if NFC(decomposed) != composed || NFD(composed) != decomposed {
+		t.Errorf("NFC = %q, NFD = %q", NFC(decomposed), NFD(composed))
+	}
+	if got := NFD("ḍ̇"); got != "ḍ̇" {
+		t.Errorf("canonical ordering: NFD = %q", got)
+	}
+	if got := NFC("각"); got != "각" {
+		t.Errorf("Hangul NFC = %q", got)
+	}
+	if got := NFD("각"); got != "각" {
+		t.Errorf("Hangul NFD = %q", got)
+	}
+	if got := NFC("Ω"); got != "Ω" {
+		t.Errorf("singleton NFC = %q", got)
+	}
+	if !New(decomposed).NFC().Equal(New(composed)) || New(decomposed).Equal(New(composed)) {
+		t.Error("Value.NFC")
+	}
+}
+
+func TestValue_EqualFold(t *testing.T) {
+	if !New("VIỆT").EqualFold(New("việt")) {
+		t.Error("EqualFold should ignore case and normalization")
+	}
+	if New("a").EqualFold(New("b")) || !New(1).EqualFold(New(1.0)) {
+		t.Error("EqualFold")
+	}
+
+	m := New(map[string]any{"Tên": 1, "id": 2})
+	if m.GetFold("tên").Int() != 1 || m.GetFold("id").Int() != 2 || !m.GetFold("x").IsNil() {
+		t.Error("GetFold")
+	}
+}
@@ -0,0 +1,163 @@
+package kit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   NUMBER FORMATTING
+   Numbers for people: grouped digits and the decimal mark of a Locale, and
+   currency amounts with the symbol where the Locale puts it.
+   ============================================================================= */
+
+type numberOptions struct {
+	places  int // -1 for the shortest form
+	mode    RoundMode
+	noGroup bool
+}
+
+// NumberOption configures FormatNumber and FormatCurrency.
+type NumberOption func(*numberOptions)
+
+// NumberDecimals rounds to exactly n decimal places, padding with zeros.
+// FormatNumber otherwise prints the shortest form, and FormatCurrency the
+// minor units of the currency.
+func NumberDecimals(n int) NumberOption {
+	return func(o *numberOptions) { o.places = max(n, 0) }
+}
+
+// NumberRounding sets the RoundMode used with NumberDecimals; the default
+// is RoundHalfUp.
+func NumberRounding(mode RoundMode) NumberOption {
+	return func(o *numberOptions) { o.mode = mode }
+}
+
+// NumberNoGrouping leaves out the thousands separator.
+func NumberNoGrouping() NumberOption {
+	return func(o *numberOptions) { o.noGroup = true }
+}
+
+// FormatNumber renders a Number with the decimal mark and thousands
+// separator of the Locale registered for tag: 1234567.5 is "1,234,567.5"
+// in "en", "1.234.567,5" in "de" and "vi". Other kinds give Invalid.
+func (v Value) FormatNumber(tag string, opts ...NumberOption) Value {
+	o := numberOptions{places: -1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return v.formatNumber(tag, &o)
+}
+
+// FormatCurrency renders a Number as an amount of the ISO 4217 currency
+// code, rounded to its minor units and placed as the Locale registered for
+// tag places symbols: 1234.5 "USD" is "$1,234.50" in "en" and 1234.5 "EUR"
+// is "1.234,50 €" in "de". Codes without a registered symbol print as
+// themselves. Other kinds give Invalid.
+func (v Value) FormatCurrency(tag, code string, opts ...NumberOption) Value {
+	c := lookupCurrency(code)
+	o := numberOptions{places: c.Digits}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	num := v.formatNumber(tag, &o)
+	if num.K != String {
+		return num
+	}
+	pattern := lookupLocale(tag).Currency
+	if pattern == "" {
+		pattern = lookupLocale("en").Currency
+	}
+	s, neg := strings.CutPrefix(num.String(), "-")
+	sym := c.Symbol
+	// A symbol that is a word, such as "CHF", needs a space next to digits.
+	if r, _ := utf8.DecodeLastRuneInString(sym); strings.HasPrefix(pattern, "¤#") && unicode.IsLetter(r) {
+		sym += "\u00a0"
+	}
+	s = strings.NewReplacer("¤", sym, "#", s).Replace(pattern)
+	if neg {
+		s = "-" + s
+	}
+	return Value{K: String, V: s}
+}
+
+func (v Value) formatNumber(tag string, o *numberOptions) Value {
+	if v = v.Force(); v.K != Number {
+		return Value{K: Invalid}
+	}
+	if math.IsNaN(v.N) || math.IsInf(v.N, 0) {
+		return Value{K: String, V: strconv.FormatFloat(v.N, 'g', -1, 64)}
+	}
+	loc := lookupLocale(tag)
+	if loc.Decimal == "" {
+		loc = lookupLocale("en")
+	}
+
+	n := v.N
+	if o.places >= 0 {
+		n = roundDecimal(n, o.places, o.mode)
+	}
+	s := strconv.FormatFloat(math.Abs(n), 'f', o.places, 64)
+	intPart, frac, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	if n < 0 {
+		sb.WriteByte('-')
+	}
+	for i := range len(intPart) {
+		if i > 0 && (len(intPart)-i)%3 == 0 && !o.noGroup {
+			sb.WriteString(loc.Group)
+		}
+		sb.WriteByte(intPart[i])
+	}
+	if frac != "" {
+		sb.WriteString(loc.Decimal)
+		sb.WriteString(frac)
+	}
+	return Value{K: String, V: sb.String()}
+}
+
+// Currency describes an ISO 4217 currency for FormatCurrency.
+type Currency struct {
+	Symbol string
+	Digits int // minor units: 2 for USD, 0 for JPY and VND
+}
+
+var currencies = struct {
+	sync.RWMutex
+	m map[string]Currency
+}{m: map[string]Currency{
+	"USD": {"$", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"JPY": {"¥", 0},
+	"CNY": {"¥", 2},
+	"KRW": {"₩", 0},
+	"INR": {"₹", 2},
+	"VND": {"₫", 0},
+	"CHF": {"CHF", 2},
+	"AUD": {"A$", 2},
+	"CAD": {"CA$", 2},
+}}
+
+// RegisterCurrency adds or replaces the Currency for an ISO 4217 code. It
+// is safe to call concurrently with formatting.
+func RegisterCurrency(code string, c Currency) {
+	currencies.Lock()
+	currencies.m[strings.ToUpper(code)] = c
+	currencies.Unlock()
+}
+
+func lookupCurrency(code string) Currency {
+	code = strings.ToUpper(code)
+	currencies.RLock()
+	defer currencies.RUnlock()
+	if c, ok := currencies.m[code]; ok {
+		return c
+	}
+	return Currency{Symbol: code, Digits: 2}
+}
@@ -0,0 +1,61 @@
+package kit
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{New(1234567.5).FormatNumber("en"), "1,234,567.5"},
+		{New(1234567.5).FormatNumber("de"), "1.234.567,5"},
+		{New(1234567.5).FormatNumber("vi-VN"), "1.234.567,5"},
+		{New(1234567.5).FormatNumber("fr"), "1\u202f234\u202f567,5"},
+		{New(-999).FormatNumber("en"), "-999"},
+		{New(-1000).FormatNumber("en"), "-1,000"},
+		{New(2.675).FormatNumber("en", NumberDecimals(2)), "2.68"},
+		{New(2.5).FormatNumber("en", NumberDecimals(0), NumberRounding(RoundHalfEven)), "2"},
+		{New(3).FormatNumber("de", NumberDecimals(2)), "3,00"},
+		{New(1234.5).FormatNumber("en", NumberNoGrouping()), "1234.5"},
+		{New(-0.001).FormatNumber("en", NumberDecimals(2)), "0.00"},
+		{New(math.Inf(-1)).FormatNumber("en"), "-Inf"},
+		{New(1000).FormatNumber("x-none"), "1,000"},
+	}
+	for i, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got.String(), tt.want)
+		}
+	}
+	if !New("12").FormatNumber("en").IsInvalid() {
+		t.Error("FormatNumber on a String should be Invalid")
+	}
+}
+
+func TestFormatCurrency(t *testing.T) {
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{New(1234.5).FormatCurrency("en", "USD"), "$1,234.50"},
+		{New(1234.5).FormatCurrency("de", "eur"), "1.234,50\u00a0€"},
+		{New(1234567.8).FormatCurrency("vi", "VND"), "1.234.568\u00a0₫"},
+		{New(-5).FormatCurrency("en", "USD"), "-$5.00"},
+		{New(-5).FormatCurrency("de", "EUR"), "-5,00\u00a0€"},
+		{New(10).FormatCurrency("en", "CHF"), "CHF\u00a010.00"},
+		{New(10).FormatCurrency("en", "XYZ"), "XYZ\u00a010.00"},
+		{New(1.005).FormatCurrency("en", "JPY", NumberDecimals(2)), "¥1.01"},
+	}
+	for i, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got.String(), tt.want)
+		}
+	}
+
+	RegisterCurrency("x-tst", Currency{Symbol: "₮", Digits: 1})
+	if got := New(2.25).FormatCurrency("en", "X-TST").String(); got != "₮2.3" {
+		t.Errorf("registered currency = %q", got)
+	}
+}
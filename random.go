@@ -0,0 +1,317 @@
+package kit
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+/* =============================================================================
+   RANDOM VALUES
+   Arbitrary Value trees for property-based tests and fuzzing of
+   serializers. Value implements testing/quick.Generator, so quick.Check
+   can take Values as arguments directly.
+   ============================================================================= */
+
+type randomOptions struct {
+	depth     int
+	width     int
+	weights   map[Kind]int
+	nonFinite bool
+}
+
+// RandomOption configures Random.
+type RandomOption func(*randomOptions)
+
+// RandomDepth bounds how deeply containers nest (default 3); at depth 0
+// only scalars are generated.
+func RandomDepth(n int) RandomOption {
+	return func(o *randomOptions) { o.depth = n }
+}
+
+// RandomWidth bounds the number of elements of each Array and Map and the
+// length of Strings and Bytes (default 8).
+func RandomWidth(n int) RandomOption {
+	return func(o *randomOptions) { o.width = n }
+}
+
+// RandomWeights sets the relative frequency of each kind; kinds missing
+// from w are not generated. Only Nil, Number, Bool, Time, Duration, String,
+// Bytes, Array and Map can be generated. The default weighs every one of
+// them equally.
+func RandomWeights(w map[Kind]int) RandomOption {
+	return func(o *randomOptions) { o.weights = w }
+}
+
+// RandomNonFinite lets Numbers be NaN or ±Inf, which JSON rejects by
+// default.
+func RandomNonFinite() RandomOption {
+	return func(o *randomOptions) { o.nonFinite = true }
+}
+
+var randomKinds = []Kind{Nil, Number, Bool, Time, Duration, String, Bytes, Array, Map}
+
+// Random returns an arbitrary Value drawn from rng; the same seed yields
+// the same tree. Times are in UTC and Strings are valid UTF-8.
+func Random(rng *rand.Rand, opts ...RandomOption) Value {
+	o := randomOptions{depth: 3, width: 8}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.value(rng, o.depth)
+}
+
+// Generate implements testing/quick.Generator; size bounds the width.
+func (Value) Generate(rng *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(Random(rng, RandomWidth(min(size, 8))))
+}
+
+func (o *randomOptions) value(rng *rand.Rand, depth int) Value {
+	total := 0
+	for _, k := range randomKinds {
+		if depth > 0 || k != Array && k != Map {
+			total += o.weight(k)
+		}
+	}
+	if total == 0 {
+		return Value{K: Nil}
+	}
+	n := rng.Intn(total)
+	k := Nil
+	for _, k = range randomKinds {
+		if depth <= 0 && (k == Array || k == Map) {
+			continue
+		}
+		if n -= o.weight(k); n < 0 {
+			break
+		}
+	}
+
+	width := 0
+	if o.width > 0 {
+		width = rng.Intn(o.width + 1)
+	}
+	switch k {
+	case Number:
+		return Value{K: Number, N: o.number(rng)}
+	case Bool:
+		return New(rng.Intn(2) == 1)
+	case Time:
+		// Between 1900 and 2100, with nanoseconds.
+		sec := rng.Int63n(200*365*24*3600) - 70*365*24*3600
+		return New(time.Unix(sec, rng.Int63n(1e9)).UTC())
+	case Duration:
+		return New(time.Duration(rng.Int63n(2e15) - 1e15))
+	case String:
+		return Value{K: String, V: randomString(rng, width)}
+	case Bytes:
+		b := make([]byte, width)
+		rng.Read(b)
+		return Value{K: Bytes, V: b}
+	case Array:
+		a := make([]Value, width)
+		for i := range a {
+			a[i] = o.value(rng, depth-1)
+		}
+		return Value{K: Array, V: a}
+	case Map:
+		m := make(map[string]Value, width)
+		for i := 0; i < width; i++ {
+			m[randomString(rng, 1+rng.Intn(8))] = o.value(rng, depth-1)
+		}
+		return Value{K: Map, V: m}
+	}
+	return Value{K: Nil}
+}
+
+func (o *randomOptions) weight(k Kind) int {
+	if o.weights == nil {
+		return 1
+	}
+	return max(o.weights[k], 0)
+}
+
+func (o *randomOptions) number(rng *rand.Rand) float64 {
+	switch rng.Intn(8) {
+	case 0:
+		if o.nonFinite {
+			return []float64{math.NaN(), math.Inf(1), math.Inf(-1)}[rng.Intn(3)]
+		}
+		return 0
+	case 1, 2, 3:
+		return float64(rng.Intn(2001) - 1000)
+	case 4:
+		return float64(rng.Int63n(1<<53)) * float64(1-2*rng.Intn(2))
+	case 5:
+		return []float64{math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, 0.1, -0.5}[rng.Intn(5)]
+	}
+	return rng.NormFloat64() * math.Pow(10, float64(rng.Intn(21)-10))
+}
+
+// randomString mixes ASCII with multi-byte runes and characters that
+// serializers must escape.
+func randomString(rng *rand.Rand, n int) string {
+	const ascii = "abcdefghijklmnopqrstuvwxyz0123456789 _-.:#"
+	special := []rune{'"', '\\', '\n', '\t', 0, 0x7f, 'é', 'ß', '世', '😀', 0x2028, 0xfeff}
+	r := make([]rune, n)
+	for i := range r {
+		if rng.Intn(4) == 0 {
+			r[i] = special[rng.Intn(len(special))]
+		} else {
+			r[i] = rune(ascii[rng.Intn(len(ascii))])
+		}
+	}
+	return string(r)
+}
+
+/* =============================================================================
+   RANDOM HELPERS
+   Draws over Values for sampling and A/B assignment. A Rand seeded from a
+   key, such as a user ID, makes the same draws for that key on every run
+   and every machine.
+   ============================================================================= */
+
+// Rand draws random Values from a seeded source. It is safe for concurrent
+// use.
+type Rand struct {
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewRand returns a Rand seeded with seed.
+func NewRand(seed int64) *Rand {
+	return &Rand{rng: rand.New(rand.NewSource(seed))}
+}
+
+// RandFor returns a Rand seeded from the FNV-1a hash of key, for stable
+// per-user or per-request assignment.
+func RandFor(key string) *Rand {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return NewRand(int64(h.Sum64()))
+}
+
+// defaultRand backs the template builtins.
+var defaultRand = NewRand(time.Now().UnixNano())
+
+// Number returns a Number drawn uniformly from [lo, hi); lo > hi gives
+// Invalid.
+func (r *Rand) Number(lo, hi float64) Value {
+	if lo > hi {
+		return Value{K: Invalid}
+	}
+	r.mu.Lock()
+	f := r.rng.Float64()
+	r.mu.Unlock()
+	return Value{K: Number, N: lo + f*(hi-lo)}
+}
+
+// Int returns an integral Number drawn uniformly from [lo, hi], both ends
+// included; lo > hi gives Invalid.
+func (r *Rand) Int(lo, hi int64) Value {
+	if lo > hi {
+		return Value{K: Invalid}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if span := hi - lo + 1; span > 0 {
+		return Value{K: Number, N: float64(lo + r.rng.Int63n(span))}
+	}
+	// The span overflows int64, so it covers at least half of all values.
+	for {
+		if n := int64(r.rng.Uint64()); n >= lo && n <= hi {
+			return Value{K: Number, N: float64(n)}
+		}
+	}
+}
+
+// Shuffle returns a copy of an Array with its elements in random order.
+// Other kinds give Invalid.
+func (r *Rand) Shuffle(v Value) Value {
+	if v = v.Force(); v.K != Array {
+		return Value{K: Invalid}
+	}
+	out := append([]Value(nil), v.V.([]Value)...)
+	r.mu.Lock()
+	r.rng.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	r.mu.Unlock()
+	return Value{K: Array, V: out}
+}
+
+// Pick returns a random element of an Array, or Nil when it is empty.
+// Other kinds give Invalid.
+func (r *Rand) Pick(v Value) Value {
+	if v = v.Force(); v.K != Array {
+		return Value{K: Invalid}
+	}
+	a := v.V.([]Value)
+	if len(a) == 0 {
+		return Value{K: Nil}
+	}
+	r.mu.Lock()
+	i := r.rng.Intn(len(a))
+	r.mu.Unlock()
+	return a[i].Force()
+}
+
+// Sample returns n elements of an Array drawn without replacement, in
+// their original order; all of them when n exceeds its length. Other kinds
+// give Invalid.
+func (r *Rand) Sample(v Value, n int) Value {
+	if v = v.Force(); v.K != Array {
+		return Value{K: Invalid}
+	}
+	a := v.V.([]Value)
+	n = max(min(n, len(a)), 0)
+	r.mu.Lock()
+	idx := r.rng.Perm(len(a))[:n]
+	r.mu.Unlock()
+	sort.Ints(idx)
+	out := make([]Value, n)
+	for i, j := range idx {
+		out[i] = a[j]
+	}
+	return Value{K: Array, V: out}
+}
+
+// Choose returns a key of a Map of Number weights, drawn in proportion to
+// its weight, as in an A/B split:
+//
+//	variant := kit.RandFor(userID).Choose(kit.New(map[string]int{"a": 90, "b": 10}))
+//
+// Keys with non-positive weights are never chosen. It gives Nil when no
+// key has a positive weight and Invalid for other kinds.
+func (r *Rand) Choose(weights Value) Value {
+	if weights = weights.Force(); weights.K != Map {
+		return Value{K: Invalid}
+	}
+	m := weights.mapping()
+	keys := sortedKeys(m)
+	total := 0.0
+	for _, k := range keys {
+		if w := m[k].Force(); w.K == Number && w.N > 0 {
+			total += w.N
+		}
+	}
+	if total == 0 || math.IsInf(total, 0) {
+		return Value{K: Nil}
+	}
+	r.mu.Lock()
+	x := r.rng.Float64() * total
+	r.mu.Unlock()
+	last := ""
+	for _, k := range keys {
+		if w := m[k].Force(); w.K == Number && w.N > 0 {
+			if x -= w.N; x < 0 {
+				return Value{K: String, V: k}
+			}
+			last = k
+		}
+	}
+	// Rounding can leave x just above zero after the last key.
+	return Value{K: String, V: last}
+}
@@ -0,0 +1,106 @@
+package kit
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestRandom(t *testing.T) {
+	a := Random(rand.New(rand.NewSource(1)))
+	b := Random(rand.New(rand.NewSource(1)))
+	if !a.Equal(b) {
+		t.Error("same seed produced different trees")
+	}
+
+	rng := rand.New(rand.NewSource(2))
+	for i := 0; i < 200; i++ {
+		v := Random(rng, RandomDepth(0), RandomWeights(map[Kind]int{String: 1, Map: 5}))
+		if v.K != String {
+			t.Fatalf("got %s at depth 0 with only String and Map weighted", v.K)
+		}
+	}
+}
+
+func TestRandom_Properties(t *testing.T) {
+	cfg := &quick.Config{MaxCount: 300, Rand: rand.New(rand.NewSource(3))}
+
+	equalHash := func(seed int64) bool {
+		a := Random(rand.New(rand.NewSource(seed)), RandomNonFinite())
+		b := Random(rand.New(rand.NewSource(seed)), RandomNonFinite())
+		return a.Equal(b) && a.hash() == b.hash() && a.Compare(b) == 0
+	}
+	if err := quick.Check(equalHash, cfg); err != nil {
+		t.Error("Equal/hash:", err)
+	}
+
+	yamlRoundTrip := func(v Value) bool {
+		data, err := v.AppendYAML(nil)
+		if err != nil {
+			return false
+		}
+		back, err := FromYAML(data)
+		if err != nil || !back.Equal(v) {
+			t.Logf("%v\n%s\n%v", err, data, DiffString(v, back))
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(yamlRoundTrip, cfg); err != nil {
+		t.Error("YAML round trip:", err)
+	}
+}
+
+func TestRand(t *testing.T) {
+	arr := New([]int{1, 2, 3, 4, 5, 6, 7, 8})
+	a, b := RandFor("user-42"), RandFor("user-42")
+	if !a.Shuffle(arr).Equal(b.Shuffle(arr)) || !a.Pick(arr).Equal(b.Pick(arr)) {
+		t.Error("same key produced different draws")
+	}
+
+	r := NewRand(1)
+	for i := 0; i < 100; i++ {
+		if n := r.Number(2, 3); n.N < 2 || n.N >= 3 {
+			t.Fatalf("Number(2, 3) = %v", n)
+		}
+		if n := r.Int(-1, 1); n.N < -1 || n.N > 1 || n.N != math.Trunc(n.N) {
+			t.Fatalf("Int(-1, 1) = %v", n)
+		}
+	}
+	if n := r.Int(math.MinInt64, math.MaxInt64); n.K != Number {
+		t.Errorf("Int over the full range = %v", n)
+	}
+
+	if s := r.Shuffle(arr); s.Len() != 8 || !s.Sort(func(x, y Value) bool { return x.N < y.N }).Equal(arr) {
+		t.Errorf("Shuffle lost elements: %v", s)
+	}
+	s := r.Sample(arr, 3)
+	if s.Len() != 3 || !s.Equal(s.Sort(func(x, y Value) bool { return x.N < y.N })) {
+		t.Errorf("Sample(3) = %v, want 3 elements in original order", s)
+	}
+	if r.Sample(arr, 20).Len() != 8 || r.Sample(arr, -1).Len() != 0 {
+		t.Error("Sample should clamp n to the Array length")
+	}
+
+	counts := map[string]int{}
+	split := New(map[string]int{"a": 90, "b": 10, "off": 0})
+	for i := 0; i < 1000; i++ {
+		counts[r.Choose(split).String()]++
+	}
+	if counts["off"] != 0 || counts["a"] < 850 || counts["b"] < 50 {
+		t.Errorf("Choose counts = %v", counts)
+	}
+
+	tests := []Value{
+		r.Pick(New([]int{})), r.Choose(New(map[string]int{"a": 0})),
+	}
+	for _, v := range tests {
+		if !v.IsNil() {
+			t.Errorf("got %v, want Nil", v)
+		}
+	}
+	if !r.Pick(New("x")).IsInvalid() || !r.Int(2, 1).IsInvalid() || !r.Choose(New(1)).IsInvalid() {
+		t.Error("bad inputs should give Invalid")
+	}
+}
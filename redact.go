@@ -0,0 +1,78 @@
+package kit
+
+import (
+	"strconv"
+	"strings"
+)
+
+/* =============================================================================
+   REDACTION
+   Sensitive entries are replaced before a Value leaves the process, so Dump,
+   LogValue and every serializer only ever see the masked marker.
+   ============================================================================= */
+
+// Redacted is the String that replaces every redacted entry.
+const Redacted = "[REDACTED]"
+
+// Redact returns a copy of v where every entry whose path matches one of
+// patterns is replaced by the String Redacted. Patterns use the glob syntax
+// of MatchPath and are compared case-insensitively, so "authorization"
+// also catches an "Authorization" header. A pattern without a dot matches
+// that key at any depth: "password" is shorthand for "**.password".
+//
+//	safe := kit.Redact(req, "password", "*.token", "authorization")
+//	slog.Info("request", "body", safe)
+//
+// Array elements are addressed by index ("users.*.password"). Structs are
+// redacted field by field, named as ToMap names them, and come back as
+// Maps; other kinds are returned unchanged. Sharded Maps come back as plain
+// Maps.
+func Redact(v Value, patterns ...string) Value {
+	compiled := make([][]string, len(patterns))
+	for i, p := range patterns {
+		p = strings.ToLower(p)
+		if !strings.Contains(p, ".") {
+			p = "**." + p
+		}
+		compiled[i] = splitPath(p)
+	}
+	return v.redact(nil, compiled)
+}
+
+func (v Value) redact(path []string, patterns [][]string) Value {
+	for _, p := range patterns {
+		if len(path) > 0 && matchSegments(p, path) {
+			return Value{K: String, V: Redacted}
+		}
+	}
+	v = v.Force()
+	switch v.K {
+	case Map:
+		m := v.mapping()
+		out := make(map[string]Value, len(m))
+		for k, e := range m {
+			out[k] = e.redact(append(path[:len(path):len(path)], strings.ToLower(k)), patterns)
+		}
+		return Value{K: Map, V: out}
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return v
+		}
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = e.redact(append(path[:len(path):len(path)], strconv.Itoa(i)), patterns)
+		}
+		return Value{K: Array, V: out}
+	case Struct:
+		// Fail closed: a Struct that cannot be walked is masked whole.
+		switch m := v.ToMap(); m.K {
+		case Map:
+			return m.redact(path, patterns)
+		case Nil:
+			return m
+		}
+		return Value{K: String, V: Redacted}
+	}
+	return v
+}
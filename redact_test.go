@@ -0,0 +1,73 @@
+package kit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	v := New(map[string]any{
+		"user":    map[string]any{"name": "ann", "Password": "hunter2"},
+		"session": map[string]any{"token": "abc", "id": 7},
+		"headers": map[string]any{"Authorization": "Bearer x", "Accept": "*/*"},
+		"token":   "top-level",
+		"list":    []any{map[string]any{"password": "p1"}, "x"},
+	})
+	r := Redact(v, "password", "*.token", "authorization")
+
+	cases := map[string]string{
+		"user.name":             "ann",
+		"user.Password":         Redacted,
+		"session.token":         Redacted,
+		"headers.Authorization": Redacted,
+		"headers.Accept":        "*/*",
+		"token":                 "top-level",
+		"list.0.password":       Redacted,
+		"list.1":                "x",
+	}
+	for path, want := range cases {
+		if got := r.Path(path).Text(); got != want {
+			t.Errorf("%s = %q, want %q", path, got, want)
+		}
+	}
+	if got := r.Path("session.id").Int(); got != 7 {
+		t.Errorf("session.id = %d", got)
+	}
+	if v.Path("user.Password").Text() != "hunter2" {
+		t.Error("Redact modified its input")
+	}
+
+	for name, out := range map[string]string{
+		"Dump": r.Dump(),
+		"JSON": string(must(r.MarshalJSON())),
+	} {
+		if strings.Contains(out, "hunter2") || !strings.Contains(out, Redacted) {
+			t.Errorf("%s leaked: %s", name, out)
+		}
+	}
+}
+
+func TestRedact_Struct(t *testing.T) {
+	type login struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	r := Redact(New(map[string]any{"body": login{"ann", "hunter2"}}), "password")
+	if got := r.Path("body.password").Text(); got != Redacted {
+		t.Errorf("body.password = %q", got)
+	}
+	if got := r.Path("body.user").Text(); got != "ann" {
+		t.Errorf("body.user = %q", got)
+	}
+
+	// Fields JSON cannot encode must not keep the secrets next to them.
+	type session struct {
+		Password string
+		Done     chan int
+		Hook     func()
+	}
+	r = Redact(New(map[string]any{"s": session{Password: "secret", Done: make(chan int)}}), "password")
+	if got := r.Path("s.Password").Text(); got != Redacted {
+		t.Errorf("s.Password = %q", got)
+	}
+}
@@ -0,0 +1,85 @@
+package kit
+
+import (
+	"sort"
+	"strconv"
+)
+
+/* =============================================================================
+   REDIS CODEC
+   Hash mode flattens a tree into field/value strings suitable for HSET and
+   HGETALL; blob mode stores the whole tree as one MessagePack string via
+   MarshalBinary/UnmarshalBinary.
+   ============================================================================= */
+
+// ToHash flattens v into dotted field names and Text values:
+// {"user": {"name": "an"}, "tags": ["a"]} becomes user.name=an, tags.0=a.
+// Empty Maps and Arrays have no fields and are dropped.
+func (v Value) ToHash() map[string]string {
+	out := make(map[string]string)
+	v.flattenInto(out, "")
+	return out
+}
+
+func (v Value) flattenInto(out map[string]string, prefix string) {
+	v = v.Force()
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + "." + k
+	}
+	switch v.K {
+	case Map:
+		for k, e := range v.mapping() {
+			e.flattenInto(out, join(k))
+		}
+	case Array:
+		for i, e := range v.V.([]Value) {
+			e.flattenInto(out, join(strconv.Itoa(i)))
+		}
+	default:
+		out[prefix] = v.Text()
+	}
+}
+
+// FromHash rebuilds a tree written by ToHash. Values are typed with FromText,
+// so Strings that look like numbers, booleans or null come back typed, and
+// Maps whose keys are exactly 0..n-1 become Arrays.
+func FromHash(h map[string]string) Value {
+	fields := make([]string, 0, len(h))
+	for f := range h {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+
+	out := Value{K: Map, V: map[string]Value{}}
+	for _, f := range fields {
+		if next, err := out.setIn(splitPath(f), FromText(h[f])); err == nil {
+			out = next
+		}
+	}
+	return arrayify(out)
+}
+
+func arrayify(v Value) Value {
+	if v.K != Map {
+		return v
+	}
+	m := v.V.(map[string]Value)
+	for k, e := range m {
+		m[k] = arrayify(e)
+	}
+	arr := make([]Value, len(m))
+	for k, e := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(arr) || strconv.Itoa(i) != k {
+			return v
+		}
+		arr[i] = e
+	}
+	if len(arr) == 0 {
+		return v
+	}
+	return Value{K: Array, V: arr}
+}
@@ -0,0 +1,50 @@
+package kit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValue_HashRoundTrip(t *testing.T) {
+	v := New(map[string]any{
+		"user":  map[string]any{"name": "an", "age": 30},
+		"tags":  []string{"a", "b"},
+		"admin": true,
+	})
+	h := v.ToHash()
+	if h["user.name"] != "an" || h["tags.1"] != "b" || h["admin"] != "true" {
+		t.Errorf("ToHash = %v", h)
+	}
+	if back := FromHash(h); !back.Equal(v) {
+		t.Errorf("FromHash = %v, want %v", back, v)
+	}
+}
+
+func TestValue_BinaryRoundTrip(t *testing.T) {
+	v := New(map[string]any{
+		"n":     []any{0, -1, 300, -70000, 1 << 40, 2.5, nil},
+		"s":     "xin chào",
+		"b":     []byte{0, 1},
+		"when":  time.Unix(1700000000, 123),
+		"ttl":   90 * time.Second,
+		"empty": map[string]any{},
+	})
+	data, err := v.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Value
+	if err := back.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !back.Equal(v) {
+		t.Errorf("round trip = %+v, want %+v", back, v)
+	}
+
+	if _, err := DecodeMsgPack(data[:len(data)-1]); err == nil {
+		t.Error("truncated input should fail")
+	}
+	if _, err := DecodeMsgPack([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}); err == nil {
+		t.Error("oversized array header should fail")
+	}
+}
@@ -0,0 +1,96 @@
+package kit
+
+import (
+	"regexp"
+	"sync"
+)
+
+/* =============================================================================
+   REGULAR EXPRESSIONS
+   Patterns use RE2 syntax (package regexp) and are compiled once into a
+   bounded cache shared by all Values.
+   ============================================================================= */
+
+const regexCacheSize = 256
+
+var regexCache = struct {
+	sync.RWMutex
+	m map[string]*regexp.Regexp
+}{m: make(map[string]*regexp.Regexp)}
+
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.RLock()
+	re, ok := regexCache.m[pattern]
+	regexCache.RUnlock()
+	if ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache.Lock()
+	if len(regexCache.m) >= regexCacheSize {
+		clear(regexCache.m)
+	}
+	regexCache.m[pattern] = re
+	regexCache.Unlock()
+	return re, nil
+}
+
+// Match reports whether a String or Bytes Value contains a match of pattern.
+// Invalid patterns and other kinds never match.
+func (v Value) Match(pattern string) bool {
+	v = v.Force()
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return false
+	}
+	switch v.K {
+	case String:
+		return re.MatchString(v.String())
+	case Bytes:
+		return re.Match(v.Bytes())
+	}
+	return false
+}
+
+// FindAllGroups returns every match of pattern as an Array of Arrays: the
+// full match followed by each capture group, as Strings ("" for groups that
+// did not participate). It returns Invalid for bad patterns or other kinds.
+func (v Value) FindAllGroups(pattern string) Value {
+	v = v.Force()
+	re, err := compileRegex(pattern)
+	if err != nil || v.K != String && v.K != Bytes {
+		return Value{K: Invalid}
+	}
+	s := v.Text()
+	matches := re.FindAllStringSubmatch(s, -1)
+	out := make([]Value, len(matches))
+	for i, m := range matches {
+		groups := make([]Value, len(m))
+		for j, g := range m {
+			groups[j] = Value{K: String, V: g}
+		}
+		out[i] = Value{K: Array, V: groups}
+	}
+	return Value{K: Array, V: out}
+}
+
+// ReplaceRegex replaces every match of pattern with repl, where $1 or ${name}
+// expand to capture groups. Bytes stay Bytes. It returns Invalid for bad
+// patterns or other kinds.
+func (v Value) ReplaceRegex(pattern, repl string) Value {
+	v = v.Force()
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return Value{K: Invalid}
+	}
+	switch v.K {
+	case String:
+		return Value{K: String, V: re.ReplaceAllString(v.String(), repl)}
+	case Bytes:
+		return Value{K: Bytes, V: re.ReplaceAll(v.Bytes(), []byte(repl))}
+	}
+	return Value{K: Invalid}
+}
@@ -0,0 +1,27 @@
+package kit
+
+import "testing"
+
+func TestValue_Regex(t *testing.T) {
+	v := New("order #12 shipped, order #7 pending")
+
+	if !v.Match(`#\d+`) || v.Match(`^shipped`) || v.Match(`(`) {
+		t.Error("Match")
+	}
+
+	groups := v.FindAllGroups(`#(\d+) (\w+)`)
+	want := New([][]string{{"#12 shipped", "12", "shipped"}, {"#7 pending", "7", "pending"}})
+	if !groups.Equal(want) {
+		t.Errorf("FindAllGroups = %v", groups)
+	}
+
+	if got := v.ReplaceRegex(`#(?P<id>\d+)`, "[${id}]"); got.String() != "order [12] shipped, order [7] pending" {
+		t.Errorf("ReplaceRegex = %q", got.String())
+	}
+	if got := New([]byte("a1b2")).ReplaceRegex(`\d`, ""); got.K != Bytes || string(got.Bytes()) != "ab" {
+		t.Errorf("ReplaceRegex on Bytes = %v", got)
+	}
+	if !New(5).ReplaceRegex(`5`, "x").IsInvalid() || !v.FindAllGroups(`[`).IsInvalid() {
+		t.Error("unsupported kinds and bad patterns should be Invalid")
+	}
+}
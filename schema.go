@@ -0,0 +1,371 @@
+package kit
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   SCHEMAS
+   A fluent, Go-native alternative to JSON Schema:
+
+	kit.Schema().
+		Field("age", kit.Num().Int().Min(0)).
+		Field("email", kit.Str().Pattern(`^[^@\s]+@[^@\s]+$`)).
+		Optional("tags", kit.Arr(kit.Str()).MaxLen(10))
+
+   Schemas are immutable once built and safe for concurrent use.
+   ============================================================================= */
+
+// Rule checks a single Value. With coerce set, a Rule converts compatible
+// kinds (such as the String "42" for a Number rule) and returns the
+// converted Value; otherwise it returns v unchanged. Failures are reported
+// as a ValidationError.
+type Rule interface {
+	Check(v Value, coerce bool) (Value, error)
+}
+
+// FieldError is one validation failure at a dot-separated path; the path is
+// empty for the checked Value itself.
+type FieldError struct {
+	Path string
+	Msg  string
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Msg
+	}
+	return e.Path + ": " + e.Msg
+}
+
+// ValidationError lists every failure found in a Value, in field order.
+type ValidationError []*FieldError
+
+func (e ValidationError) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "kit: invalid value: " + strings.Join(msgs, "; ")
+}
+
+func invalid(format string, args ...any) error {
+	return ValidationError{{Msg: fmt.Sprintf(format, args...)}}
+}
+
+// nest prefixes the paths of a Rule's errors with key.
+func nest(key string, err error) []*FieldError {
+	ve, ok := err.(ValidationError)
+	if !ok {
+		return []*FieldError{{Path: key, Msg: err.Error()}}
+	}
+	out := make([]*FieldError, len(ve))
+	for i, fe := range ve {
+		path := key
+		if fe.Path != "" {
+			path += "." + fe.Path
+		}
+		out[i] = &FieldError{Path: path, Msg: fe.Msg}
+	}
+	return out
+}
+
+func wrongKind(want Kind, v Value) error {
+	return invalid("must be a %s, got %s", want, v.K)
+}
+
+/* ---------------------------------------------------------------------------
+   Objects
+   --------------------------------------------------------------------------- */
+
+type field struct {
+	name     string
+	rule     Rule
+	optional bool
+	def      *Value
+}
+
+// ObjectSchema is a Rule for Maps with declared fields. Validation accepts
+// keys without a declared field; coercion (see Conform) strips them.
+type ObjectSchema struct {
+	fields []field
+}
+
+// Schema starts an empty ObjectSchema.
+func Schema() *ObjectSchema { return &ObjectSchema{} }
+
+// Field declares a required field; a missing or Nil value fails.
+func (s *ObjectSchema) Field(name string, rule Rule) *ObjectSchema {
+	return s.with(field{name: name, rule: rule})
+}
+
+// Optional declares a field that may be missing or Nil.
+func (s *ObjectSchema) Optional(name string, rule Rule) *ObjectSchema {
+	return s.with(field{name: name, rule: rule, optional: true})
+}
+
+// Default declares an optional field that Conform sets to def when it is
+// missing or Nil. Validate treats it as Optional.
+func (s *ObjectSchema) Default(name string, rule Rule, def any) *ObjectSchema {
+	d := New(def)
+	return s.with(field{name: name, rule: rule, optional: true, def: &d})
+}
+
+// with returns a copy of s with f added, so partially built schemas can be
+// shared and extended independently.
+func (s *ObjectSchema) with(f field) *ObjectSchema {
+	out := &ObjectSchema{fields: make([]field, 0, len(s.fields)+1)}
+	for _, g := range s.fields {
+		if g.name != f.name {
+			out.fields = append(out.fields, g)
+		}
+	}
+	out.fields = append(out.fields, f)
+	return out
+}
+
+// Validate checks v against the schema without changing it.
+func (s *ObjectSchema) Validate(v Value) error {
+	_, err := s.Check(v, false)
+	return err
+}
+
+// Conform turns raw input such as a decoded request body into a clean Value
+// in one step: kinds are coerced ("42" becomes a Number for a Num field),
+// defaults fill missing fields and undeclared keys are dropped, recursively
+// through nested schemas. The input is not modified. On failure the error
+// is a ValidationError listing every problem.
+func (s *ObjectSchema) Conform(v Value) (Value, error) {
+	return s.Check(v, true)
+}
+
+// Check implements Rule. With coerce set it behaves as Conform.
+func (s *ObjectSchema) Check(v Value, coerce bool) (Value, error) {
+	v = v.Force()
+	if v.K != Map {
+		return v, wrongKind(Map, v)
+	}
+	var errs ValidationError
+	var out map[string]Value
+	if coerce {
+		out = make(map[string]Value, len(s.fields))
+	}
+	for _, f := range s.fields {
+		x := v.Get(f.name)
+		if x.IsBlank() && f.def != nil && coerce {
+			out[f.name] = *f.def
+			continue
+		}
+		if x.IsBlank() {
+			if coerce && x.IsNil() && v.Has(f.name) {
+				out[f.name] = x
+			}
+			if !f.optional {
+				errs = append(errs, &FieldError{Path: f.name, Msg: "is required"})
+			}
+			continue
+		}
+		y, err := f.rule.Check(x, coerce)
+		if err != nil {
+			errs = append(errs, nest(f.name, err)...)
+			continue
+		}
+		if coerce {
+			out[f.name] = y
+		}
+	}
+	if errs != nil {
+		return v, errs
+	}
+	if coerce {
+		return Value{K: Map, V: out}, nil
+	}
+	return v, nil
+}
+
+/* ---------------------------------------------------------------------------
+   Scalars
+   --------------------------------------------------------------------------- */
+
+// NumberRule is a Rule for Numbers.
+type NumberRule struct {
+	min, max float64
+	integer  bool
+}
+
+// Num starts a NumberRule accepting any finite Number. When coercing,
+// numeric Strings are converted.
+func Num() *NumberRule { return &NumberRule{min: math.Inf(-1), max: math.Inf(1)} }
+
+// Min sets the smallest accepted value.
+func (r *NumberRule) Min(n float64) *NumberRule { c := *r; c.min = n; return &c }
+
+// Max sets the largest accepted value.
+func (r *NumberRule) Max(n float64) *NumberRule { c := *r; c.max = n; return &c }
+
+// Int accepts only integral values.
+func (r *NumberRule) Int() *NumberRule { c := *r; c.integer = true; return &c }
+
+// Check implements Rule.
+func (r *NumberRule) Check(v Value, coerce bool) (Value, error) {
+	v = v.Force()
+	if v.K == String && coerce {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64); err == nil {
+			v = Value{K: Number, N: n}
+		}
+	}
+	switch {
+	case v.K != Number:
+		return v, wrongKind(Number, v)
+	case math.IsNaN(v.N) || math.IsInf(v.N, 0):
+		return v, invalid("must be finite")
+	case r.integer && v.N != math.Trunc(v.N):
+		return v, invalid("must be an integer")
+	case v.N < r.min:
+		return v, invalid("must be at least %v", r.min)
+	case v.N > r.max:
+		return v, invalid("must be at most %v", r.max)
+	}
+	return v, nil
+}
+
+// StringRule is a Rule for Strings.
+type StringRule struct {
+	minLen, maxLen int
+	pattern        *regexp.Regexp
+	oneOf          []string
+}
+
+// Str starts a StringRule accepting any String. When coercing, Numbers,
+// Bools, Times and Durations are converted to their Text.
+func Str() *StringRule { return &StringRule{maxLen: -1} }
+
+// MinLen sets the minimum length in runes.
+func (r *StringRule) MinLen(n int) *StringRule { c := *r; c.minLen = n; return &c }
+
+// MaxLen sets the maximum length in runes.
+func (r *StringRule) MaxLen(n int) *StringRule { c := *r; c.maxLen = n; return &c }
+
+// Pattern requires a match of the RE2 expression expr somewhere in the
+// String; anchor it with ^ and $ to match the whole. It panics if expr does
+// not compile, like regexp.MustCompile, since schemas are built at init.
+func (r *StringRule) Pattern(expr string) *StringRule {
+	re, err := compileRegex(expr)
+	if err != nil {
+		panic("kit: Str().Pattern: " + err.Error())
+	}
+	c := *r
+	c.pattern = re
+	return &c
+}
+
+// OneOf restricts the String to the given choices.
+func (r *StringRule) OneOf(choices ...string) *StringRule { c := *r; c.oneOf = choices; return &c }
+
+// Check implements Rule.
+func (r *StringRule) Check(v Value, coerce bool) (Value, error) {
+	v = v.Force()
+	if coerce && v.IsScalar() {
+		v = Value{K: String, V: v.Text()}
+	}
+	if v.K != String {
+		return v, wrongKind(String, v)
+	}
+	s := v.String()
+	n := utf8.RuneCountInString(s)
+	switch {
+	case n < r.minLen:
+		return v, invalid("must be at least %d characters", r.minLen)
+	case r.maxLen >= 0 && n > r.maxLen:
+		return v, invalid("must be at most %d characters", r.maxLen)
+	case r.pattern != nil && !r.pattern.MatchString(s):
+		return v, invalid("must match %s", r.pattern)
+	case r.oneOf != nil && !contains(r.oneOf, s):
+		return v, invalid("must be one of %s", strings.Join(r.oneOf, ", "))
+	}
+	return v, nil
+}
+
+// BoolRule is a Rule for Bools.
+type BoolRule struct{}
+
+// Boolean returns a Rule accepting Bools. When coercing, the Strings
+// accepted by ToBool, such as "yes" and "off", are converted.
+func Boolean() *BoolRule { return &BoolRule{} }
+
+// Check implements Rule.
+func (r *BoolRule) Check(v Value, coerce bool) (Value, error) {
+	v = v.Force()
+	if v.K == String && coerce {
+		if b := v.ToBool(); b.K == Bool {
+			v = b
+		}
+	}
+	if v.K != Bool {
+		return v, wrongKind(Bool, v)
+	}
+	return v, nil
+}
+
+/* ---------------------------------------------------------------------------
+   Arrays
+   --------------------------------------------------------------------------- */
+
+// ArrayRule is a Rule for Arrays whose elements all satisfy one Rule.
+type ArrayRule struct {
+	elem           Rule
+	minLen, maxLen int
+}
+
+// Arr starts an ArrayRule; a nil elem accepts any elements.
+func Arr(elem Rule) *ArrayRule { return &ArrayRule{elem: elem, maxLen: -1} }
+
+// MinLen sets the minimum number of elements.
+func (r *ArrayRule) MinLen(n int) *ArrayRule { c := *r; c.minLen = n; return &c }
+
+// MaxLen sets the maximum number of elements.
+func (r *ArrayRule) MaxLen(n int) *ArrayRule { c := *r; c.maxLen = n; return &c }
+
+// Check implements Rule. Element errors carry their index in the path.
+func (r *ArrayRule) Check(v Value, coerce bool) (Value, error) {
+	v = v.Force()
+	if v.K != Array {
+		return v, wrongKind(Array, v)
+	}
+	a := v.V.([]Value)
+	switch {
+	case len(a) < r.minLen:
+		return v, invalid("must have at least %d elements", r.minLen)
+	case r.maxLen >= 0 && len(a) > r.maxLen:
+		return v, invalid("must have at most %d elements", r.maxLen)
+	case r.elem == nil:
+		return v, nil
+	}
+	var errs ValidationError
+	var out []Value
+	if coerce {
+		out = make([]Value, len(a))
+	}
+	for i, e := range a {
+		y, err := r.elem.Check(e, coerce)
+		if err != nil {
+			errs = append(errs, nest(strconv.Itoa(i), err)...)
+			continue
+		}
+		if coerce {
+			out[i] = y
+		}
+	}
+	if errs != nil {
+		return v, errs
+	}
+	if coerce {
+		return Value{K: Array, V: out}, nil
+	}
+	return v, nil
+}
@@ -0,0 +1,107 @@
+package kit
+
+import (
+	"errors"
+	"testing"
+)
+
+var userSchema = Schema().
+	Field("age", Num().Int().Min(0).Max(150)).
+	Field("email", Str().Pattern(`^[^@\s]+@[^@\s]+$`)).
+	Optional("role", Str().OneOf("admin", "user")).
+	Optional("tags", Arr(Str().MinLen(1)).MaxLen(3)).
+	Optional("address", Schema().Field("city", Str()))
+
+func TestSchema_Validate(t *testing.T) {
+	ok := New(map[string]any{"age": 30, "email": "a@b.c", "extra": true})
+	if err := userSchema.Validate(ok); err != nil {
+		t.Errorf("Validate(ok) = %v", err)
+	}
+
+	bad := New(map[string]any{
+		"age":     -1.5,
+		"role":    "root",
+		"tags":    []any{"x", ""},
+		"address": map[string]any{"city": 7},
+	})
+	err := userSchema.Validate(bad)
+	var ve ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("Validate(bad) = %v", err)
+	}
+	want := []string{
+		"age: must be an integer",
+		"email: is required",
+		"role: must be one of admin, user",
+		"tags.1: must be at least 1 characters",
+		"address.city: must be a String, got Number",
+	}
+	if len(ve) != len(want) {
+		t.Fatalf("got %d errors: %v", len(ve), err)
+	}
+	for i, fe := range ve {
+		if fe.Error() != want[i] {
+			t.Errorf("error %d = %q, want %q", i, fe.Error(), want[i])
+		}
+	}
+
+	if err := userSchema.Validate(New("x")); err == nil || err.Error() != "kit: invalid value: must be a Map, got String" {
+		t.Errorf("Validate(String) = %v", err)
+	}
+}
+
+func TestSchema_Check(t *testing.T) {
+	raw := New(map[string]any{"age": "42", "email": "a@b.c", "tags": []any{7}, "flag": "yes"})
+	s := userSchema.Field("flag", Boolean())
+	if err := s.Validate(raw); err == nil {
+		t.Error("Validate should not coerce")
+	}
+	got, err := s.Check(raw, true)
+	want := New(map[string]any{"age": 42, "email": "a@b.c", "tags": []any{"7"}, "flag": true})
+	if err != nil || !got.Equal(want) {
+		t.Errorf("Check(coerce) = %v, %v", got, err)
+	}
+	if raw.Get("age").K != String {
+		t.Error("Check must not modify its input")
+	}
+
+	if userSchema.Validate(New(map[string]any{"age": 1, "email": "a@b.c"})) != nil {
+		t.Error("extending a schema must not change the original")
+	}
+}
+
+func TestSchema_Conform(t *testing.T) {
+	s := Schema().
+		Field("qty", Num().Int().Min(1)).
+		Default("role", Str().OneOf("admin", "user"), "user").
+		Default("active", Boolean(), true).
+		Optional("note", Str()).
+		Optional("shipping", Schema().Field("zip", Str()).Default("country", Str(), "VN"))
+
+	raw := New(map[string]any{
+		"qty":      "3",
+		"active":   "false",
+		"note":     nil,
+		"admin":    true,
+		"shipping": map[string]any{"zip": 70000, "junk": 1},
+	})
+	got, err := s.Conform(raw)
+	want := New(map[string]any{
+		"qty":      3,
+		"role":     "user",
+		"active":   false,
+		"note":     nil,
+		"shipping": map[string]any{"zip": "70000", "country": "VN"},
+	})
+	if err != nil || !got.Equal(want) {
+		t.Errorf("Conform = %v, %v\nwant %v", got, err, want)
+	}
+	if !raw.Has("admin") {
+		t.Error("Conform must not modify its input")
+	}
+
+	_, err = s.Conform(New(map[string]any{"qty": "zero", "role": "root"}))
+	if err == nil || err.Error() != "kit: invalid value: qty: must be a Number, got String; role: must be one of admin, user" {
+		t.Errorf("Conform(bad) = %v", err)
+	}
+}
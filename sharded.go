@@ -0,0 +1,219 @@
+package kit
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+/* =============================================================================
+   SHARDED MAP
+   A Map kind may be backed by *sharded instead of map[string]Value. It trades
+   the copy-on-write semantics of plain Maps for in-place writes spread over
+   N independently locked shards, for workloads with heavy concurrent writes.
+   ============================================================================= */
+
+type shard struct {
+	mu     sync.RWMutex
+	m      map[string]Value
+	shared bool // m is referenced by a snapshot and must be copied before writing
+}
+
+type sharded struct {
+	seed   maphash.Seed
+	shards []shard
+	frozen bool // snapshot: never written, writes fall back to copy-on-write
+}
+
+type mapOptions struct {
+	shards int
+}
+
+// MapOption configures NewMap.
+type MapOption func(*mapOptions)
+
+// Shards selects a sharded backing with n independently locked shards.
+// Set and Delete on such a Map write in place and return the same Value.
+func Shards(n int) MapOption {
+	return func(o *mapOptions) { o.shards = n }
+}
+
+// NewMap returns an empty Map. Without options it is a plain copy-on-write Map.
+func NewMap(opts ...MapOption) Value {
+	var o mapOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.shards <= 1 {
+		return Value{K: Map, V: map[string]Value{}}
+	}
+	s := &sharded{seed: maphash.MakeSeed(), shards: make([]shard, o.shards)}
+	for i := range s.shards {
+		s.shards[i].m = make(map[string]Value)
+	}
+	return Value{K: Map, V: s}
+}
+
+func (s *sharded) shard(key string) *shard {
+	return &s.shards[maphash.String(s.seed, key)%uint64(len(s.shards))]
+}
+
+func (s *sharded) get(key string) (Value, bool) {
+	sh := s.shard(key)
+	sh.mu.RLock()
+	v, ok := sh.m[key]
+	sh.mu.RUnlock()
+	return v, ok
+}
+
+func (s *sharded) len() int {
+	n := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		n += len(sh.m)
+		sh.mu.RUnlock()
+	}
+	return n
+}
+
+// flatten copies every shard into a single plain map.
+func (s *sharded) flatten() map[string]Value {
+	out := make(map[string]Value)
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.RLock()
+		for k, v := range sh.m {
+			out[k] = v
+		}
+		sh.mu.RUnlock()
+	}
+	return out
+}
+
+// own makes m private to the live map again after a snapshot.
+// Callers must hold the write lock.
+func (sh *shard) own() {
+	if !sh.shared {
+		return
+	}
+	m := make(map[string]Value, len(sh.m))
+	for k, v := range sh.m {
+		m[k] = v
+	}
+	sh.m, sh.shared = m, false
+}
+
+func (s *sharded) setIn(key string, rest []string, x Value) error {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.own()
+	child, err := sh.m[key].setIn(rest, x)
+	if err != nil {
+		return err
+	}
+	sh.m[key] = child
+	return nil
+}
+
+func (s *sharded) deleteIn(key string, rest []string) error {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	old, ok := sh.m[key]
+	if !ok {
+		return nil
+	}
+	sh.own()
+	if len(rest) == 0 {
+		delete(sh.m, key)
+		return nil
+	}
+	child, err := old.deleteIn(rest)
+	if err != nil {
+		return err
+	}
+	sh.m[key] = child
+	return nil
+}
+
+// update applies fn to a plain view while every shard is write-locked, so
+// readers observe either none or all of the transaction.
+func (s *sharded) update(fn func(tx *Tx) error) error {
+	for i := range s.shards {
+		s.shards[i].mu.Lock()
+	}
+	defer func() {
+		for i := range s.shards {
+			s.shards[i].mu.Unlock()
+		}
+	}()
+
+	view := make(map[string]Value)
+	for i := range s.shards {
+		for k, v := range s.shards[i].m {
+			view[k] = v
+		}
+	}
+	tx := &Tx{root: Value{K: Map, V: view}}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if tx.err != nil {
+		return tx.err
+	}
+	out := tx.root.mapping()
+	for i := range s.shards {
+		s.shards[i].m, s.shards[i].shared = make(map[string]Value), false
+	}
+	for k, v := range out {
+		s.shard(k).m[k] = v
+	}
+	return nil
+}
+
+// snapshot freezes the current shard maps in O(shards): all shards are locked
+// together so the view is consistent, and the live map copies a shard lazily
+// the next time it writes to it.
+func (s *sharded) snapshot() *sharded {
+	for i := range s.shards {
+		s.shards[i].mu.Lock()
+	}
+	snap := &sharded{seed: s.seed, shards: make([]shard, len(s.shards)), frozen: true}
+	for i := range s.shards {
+		s.shards[i].shared = true
+		snap.shards[i].m = s.shards[i].m
+		s.shards[i].mu.Unlock()
+	}
+	return snap
+}
+
+// Snapshot returns a point-in-time, immutable view of v. Plain Values are
+// already immutable and are returned as is; a sharded Map is frozen in
+// O(shards) while writers continue on the live Map. Sharded Maps nested
+// inside the result are not frozen.
+func (v Value) Snapshot() Value {
+	if s, ok := v.V.(*sharded); ok && v.K == Map && !s.frozen {
+		return Value{K: Map, V: s.snapshot()}
+	}
+	return v
+}
+
+// mapping returns the entries of a Map kind as a plain map. Sharded Maps
+// are copied, so the result is safe to range over without locks.
+func (v Value) mapping() map[string]Value {
+	switch m := v.V.(type) {
+	case map[string]Value:
+		return m
+	case *sharded:
+		return m.flatten()
+	default:
+		return nil
+	}
+}
+
+// IsSharded reports whether v is a Map with a sharded backing.
+func (v Value) IsSharded() bool {
+	_, ok := v.V.(*sharded)
+	return v.K == Map && ok
+}
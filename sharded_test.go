@@ -0,0 +1,84 @@
+package kit
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestNewMap_Sharded(t *testing.T) {
+	m := NewMap(Shards(8))
+	if !m.IsSharded() {
+		t.Fatal("expected sharded backing")
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				m.Set(strconv.Itoa(w*100+i), i)
+				_ = m.Get("0")
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if m.Len() != 800 {
+		t.Errorf("Len() = %d, want 800", m.Len())
+	}
+	if m.Get("105").Int() != 5 {
+		t.Errorf("Get(105) = %v, want 5", m.Get("105").N)
+	}
+	m.Delete("105")
+	if m.Get("105").IsValid() {
+		t.Error("Delete did not remove key")
+	}
+}
+
+func TestNewMap_ShardedUpdate(t *testing.T) {
+	m := NewMap(Shards(4))
+	m.Set("a", 1)
+
+	if _, err := m.Update(func(tx *Tx) error {
+		tx.Set("b.c", 2)
+		return tx.Delete("a")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Get("a").IsValid() || m.Path("b.c").Int() != 2 {
+		t.Error("sharded Update not applied in place")
+	}
+	if !m.Equal(New(map[string]any{"b": map[string]any{"c": 2}})) {
+		t.Error("sharded Map should equal its plain counterpart")
+	}
+}
+
+func TestValue_Snapshot(t *testing.T) {
+	m := NewMap(Shards(4))
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	snap := m.Snapshot()
+	m.Set("a", 10)
+	m.Delete("b")
+	m.Set("c", 3)
+
+	if snap.Get("a").Int() != 1 || snap.Get("b").Int() != 2 || snap.Get("c").IsValid() {
+		t.Errorf("snapshot observed later writes: a=%v b=%v", snap.Get("a").N, snap.Get("b").N)
+	}
+	if m.Get("a").Int() != 10 || m.Len() != 2 {
+		t.Error("live map lost writes after snapshot")
+	}
+
+	next := snap.Set("d", 4)
+	if snap.Get("d").IsValid() || next.Get("d").Int() != 4 || next.IsSharded() {
+		t.Error("writes to a snapshot should copy on write")
+	}
+
+	plain := New(map[string]any{"x": 1})
+	if !plain.Snapshot().Equal(plain) {
+		t.Error("plain Snapshot should be the Value itself")
+	}
+}
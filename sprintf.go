@@ -0,0 +1,148 @@
+package kit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   PRINTF
+   Sprintf converts each argument to the Go type its verb expects before
+   handing off to fmt, so "%d" accepts Number, Bool, Duration and numeric
+   Strings alike.
+   ============================================================================= */
+
+// Sprintf formats args according to format, coercing each Value to suit its
+// verb: integer verbs (%d %b %o %x %c ...) and float verbs (%f %g %e ...)
+// take the numeric form, parsing Strings when needed; %t takes Truthy; %s, %q
+// and %v use the Value's own Format. %x and %X on Strings and Bytes keep
+// their hex-dump meaning. Arguments that cannot be coerced are passed as
+// their Text so fmt reports the usual %!verb(...) error inline.
+func Sprintf(format string, args ...Value) Value {
+	verbs := scanVerbs(format, len(args))
+	out := make([]any, len(args))
+	for i, a := range args {
+		out[i] = coerceArg(a.Force(), verbs[i])
+	}
+	return Value{K: String, V: fmt.Sprintf(format, out...)}
+}
+
+// Formatf is Sprintf with v as the arguments: an Array supplies one
+// argument per element and any other Value a single argument.
+func (v Value) Formatf(format string) Value {
+	v = v.Force()
+	if v.K == Array {
+		return Sprintf(format, v.V.([]Value)...)
+	}
+	return Sprintf(format, v)
+}
+
+// scanVerbs returns, for each of n arguments, the verb that consumes it;
+// '*' marks a width or precision argument and 0 an unreferenced one.
+func scanVerbs(format string, n int) []rune {
+	verbs := make([]rune, n)
+	arg := 0
+	use := func(verb rune) {
+		if arg >= 0 && arg < n {
+			verbs[arg] = verb
+		}
+		arg++
+	}
+	// index parses an explicit argument index such as [2] at format[i:].
+	index := func(i int) int {
+		if i >= len(format) || format[i] != '[' {
+			return i
+		}
+		j := strings.IndexByte(format[i:], ']')
+		if j < 0 {
+			return i
+		}
+		if k, err := strconv.Atoi(format[i+1 : i+j]); err == nil {
+			arg = k - 1
+		}
+		return i + j + 1
+	}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+		i = index(i)
+		if i < len(format) && format[i] == '*' {
+			use('*')
+			i++
+		}
+		for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+			i++
+		}
+		if i < len(format) && format[i] == '.' {
+			i = index(i + 1)
+			if i < len(format) && format[i] == '*' {
+				use('*')
+				i++
+			}
+			for i < len(format) && format[i] >= '0' && format[i] <= '9' {
+				i++
+			}
+		}
+		i = index(i)
+		if i >= len(format) {
+			break
+		}
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		if verb != '%' {
+			use(verb)
+		}
+	}
+	return verbs
+}
+
+func coerceArg(v Value, verb rune) any {
+	switch verb {
+	case '*':
+		if n, ok := v.number(); ok {
+			return int(n)
+		}
+	case 'd', 'b', 'o', 'O', 'c', 'U':
+		if n, ok := v.number(); ok {
+			return int64(n)
+		}
+	case 'x', 'X':
+		if v.K == String || v.K == Bytes {
+			return v.Export()
+		}
+		if n, ok := v.number(); ok {
+			if n == float64(int64(n)) {
+				return int64(n)
+			}
+			return n
+		}
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		if n, ok := v.number(); ok {
+			return n
+		}
+	case 't':
+		return v.Truthy()
+	default:
+		return v
+	}
+	return v.Text()
+}
+
+// number returns the numeric form of scalars and of Strings holding a number.
+func (v Value) number() (float64, bool) {
+	switch v.K {
+	case Number, Bool, Time, Duration:
+		return v.N, true
+	case String:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v.String()), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,36 @@
+package kit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSprintf(t *testing.T) {
+	tests := []struct {
+		format string
+		args   []Value
+		want   string
+	}{
+		{"%d items", []Value{New(3.0)}, "3 items"},
+		{"%d", []Value{New(" 42 ")}, "42"},
+		{"%05.1f", []Value{New("2.25")}, "002.2"},
+		{"%s=%v", []Value{New("a"), New([]any{1, 2})}, "a=[1 2]"},
+		{"%q", []Value{New("x")}, `"x"`},
+		{"%t", []Value{New("yes")}, "true"},
+		{"%x %X", []Value{New("hi"), New(255)}, "6869 FF"},
+		{"%*d|", []Value{New("4"), New(7)}, "   7|"},
+		{"%[2]s %[1]d", []Value{New(1), New("b")}, "b 1"},
+		{"%d", []Value{New(time.Second)}, "1000000000"},
+		{"100%% %d", []Value{New(true)}, "100% 1"},
+		{"%d", []Value{New("abc")}, "%!d(string=abc)"},
+	}
+	for _, tt := range tests {
+		if got := Sprintf(tt.format, tt.args...).String(); got != tt.want {
+			t.Errorf("Sprintf(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+
+	if got := New([]any{"id", 7}).Formatf("%s=%03d").String(); got != "id=007" {
+		t.Errorf("Formatf = %q", got)
+	}
+}
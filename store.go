@@ -0,0 +1,171 @@
+package kit
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/* =============================================================================
+   SNAPSHOT STORE
+   A root Value that changes only through whole-tree replacement, keeping
+   the last N versions for audit trails. The copy-on-write mutation APIs
+   make each version cheap: unchanged subtrees are shared between them.
+   ============================================================================= */
+
+// ErrVersionGone is returned for versions that were never recorded or have
+// been dropped from the history.
+var ErrVersionGone = errors.New("kit: version not in history")
+
+// Snapshot is one recorded version of a Store's root.
+type Snapshot struct {
+	Version uint64 // 1 for the initial root, then one more per change
+	Time    time.Time
+	Root    Value
+}
+
+// Store holds a root Value and the history of its last versions. It is
+// safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	keep     int
+	history  []Snapshot // oldest first, never empty
+	next     uint64
+	watchers []storeWatcher
+}
+
+type storeWatcher struct {
+	id uint64
+	fn func(Snapshot)
+}
+
+// NewStore returns a Store whose version 1 is root, keeping the last keep
+// versions (at least 1).
+func NewStore(root Value, keep int) *Store {
+	s := &Store{keep: max(keep, 1)}
+	s.history = []Snapshot{{Version: 1, Time: now(), Root: root.Snapshot()}}
+	return s
+}
+
+// Current returns the latest Snapshot.
+func (s *Store) Current() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.history[len(s.history)-1]
+}
+
+// Value returns the latest root.
+func (s *Store) Value() Value { return s.Current().Root }
+
+// Set records root as a new version and returns its Snapshot. A sharded
+// root is frozen first, so later writes to it do not alter the history.
+func (s *Store) Set(root Value) Snapshot {
+	s.mu.Lock()
+	snap := s.record(root)
+	watchers := s.watchers
+	s.mu.Unlock()
+	notify(watchers, snap)
+	return snap
+}
+
+func (s *Store) record(root Value) Snapshot {
+	snap := Snapshot{Version: s.history[len(s.history)-1].Version + 1, Time: now(), Root: root.Snapshot()}
+	if len(s.history) == s.keep {
+		// Shift rather than reslice so dropped roots can be collected.
+		copy(s.history, s.history[1:])
+		s.history[len(s.history)-1] = snap
+	} else {
+		s.history = append(s.history, snap)
+	}
+	return snap
+}
+
+// Update runs fn against a transaction on the latest root, as Value.Update
+// does, and records the result as a new version. Updates are serialized,
+// so none is lost to a concurrent one. If fn fails nothing is recorded.
+func (s *Store) Update(fn func(tx *Tx) error) (Snapshot, error) {
+	s.mu.Lock()
+	// A recorded sharded root is frozen, so the transaction copies it.
+	cur := s.history[len(s.history)-1]
+	root, err := cur.Root.Update(fn)
+	if err != nil {
+		s.mu.Unlock()
+		return cur, err
+	}
+	snap := s.record(root)
+	watchers := s.watchers
+	s.mu.Unlock()
+	notify(watchers, snap)
+	return snap, nil
+}
+
+// Watch registers fn to run with every new version, on the goroutine that
+// recorded it and after the Store is unlocked, so fn may read the Store.
+// Concurrent writers may deliver versions out of order; compare Version.
+// The returned function removes the watcher.
+func (s *Store) Watch(fn func(Snapshot)) (cancel func()) {
+	s.mu.Lock()
+	s.next++
+	id := s.next
+	s.watchers = append(s.watchers, storeWatcher{id: id, fn: fn})
+	s.mu.Unlock()
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for i, w := range s.watchers {
+			if w.id == id {
+				s.watchers = append(s.watchers[:i:i], s.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func notify(watchers []storeWatcher, snap Snapshot) {
+	for _, w := range watchers {
+		w.fn(snap)
+	}
+}
+
+// History returns the recorded Snapshots, oldest first.
+func (s *Store) History() []Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Snapshot(nil), s.history...)
+}
+
+// At returns the Snapshot of version, or ErrVersionGone.
+func (s *Store) At(version uint64) (Snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.at(version)
+}
+
+func (s *Store) at(version uint64) (Snapshot, error) {
+	first := s.history[0].Version
+	if version < first || version-first >= uint64(len(s.history)) {
+		return Snapshot{}, fmt.Errorf("%w: %d", ErrVersionGone, version)
+	}
+	return s.history[version-first], nil
+}
+
+// DiffSince lists the changes from version to the latest root, as Diff
+// does, or returns ErrVersionGone when version is no longer recorded.
+func (s *Store) DiffSince(version uint64) ([]Change, error) {
+	_, changes, err := s.since(version)
+	return changes, err
+}
+
+// since is DiffSince also returning the Snapshot diffed against.
+func (s *Store) since(version uint64) (Snapshot, []Change, error) {
+	s.mu.RLock()
+	old, err := s.at(version)
+	cur := s.history[len(s.history)-1]
+	s.mu.RUnlock()
+	if err != nil {
+		return cur, nil, err
+	}
+	return cur, Diff(old.Root, cur.Root), nil
+}
@@ -0,0 +1,56 @@
+package kit
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(New(map[string]any{"port": 80}), 3)
+	if s.Current().Version != 1 || s.Value().Get("port").Int() != 80 {
+		t.Fatalf("initial = %+v", s.Current())
+	}
+
+	snap, err := s.Update(func(tx *Tx) error { return tx.Set("port", 8080) })
+	if err != nil || snap.Version != 2 {
+		t.Fatalf("Update = %+v, %v", snap, err)
+	}
+	boom := errors.New("boom")
+	if _, err := s.Update(func(tx *Tx) error { tx.Set("port", 1); return boom }); err != boom || s.Current().Version != 2 {
+		t.Errorf("failed Update recorded a version: %v", err)
+	}
+	s.Set(s.Value().Set("tls", true))
+	s.Set(s.Value().Delete("port"))
+
+	if h := s.History(); len(h) != 3 || h[0].Version != 2 || h[2].Version != 4 {
+		t.Errorf("History = %+v", h)
+	}
+	if _, err := s.At(1); !errors.Is(err, ErrVersionGone) {
+		t.Errorf("At(1) = %v, want ErrVersionGone", err)
+	}
+	if _, err := s.DiffSince(9); !errors.Is(err, ErrVersionGone) {
+		t.Errorf("DiffSince(9) = %v, want ErrVersionGone", err)
+	}
+	changes, err := s.DiffSince(2)
+	if err != nil || len(changes) != 2 || changes[0].Op != Removed || changes[0].Path != "port" || changes[1].Path != "tls" {
+		t.Errorf("DiffSince(2) = %+v, %v", changes, err)
+	}
+	if changes, _ := s.DiffSince(4); len(changes) != 0 {
+		t.Errorf("DiffSince(current) = %+v", changes)
+	}
+}
+
+func TestStore_Sharded(t *testing.T) {
+	live := NewMap(Shards(4)).Set("a", 1)
+	s := NewStore(live, 2)
+	live.Set("a", 2) // in place on the live Map
+	if s.Value().Get("a").Int() != 1 {
+		t.Error("writes to the live Map leaked into the history")
+	}
+	if _, err := s.Update(func(tx *Tx) error { return tx.Set("a", 3) }); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := s.At(1); v.Root.Get("a").Int() != 1 {
+		t.Error("Update wrote into a recorded version")
+	}
+}
@@ -0,0 +1,227 @@
+package kit
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kitwork/kit/internal/websocket"
+)
+
+/* =============================================================================
+   STREAMING
+   Live views of a Store over Server-Sent Events or WebSocket. Each message
+   is a JSON object:
+
+	{"type": "snapshot", "version": 3, "time": "...", "root": {...}}
+	{"type": "patch", "version": 4, "time": "...", "patch": [...]}
+
+   A client first receives a snapshot, then one message per change. Changes
+   arriving faster than a client reads are coalesced into one message.
+   ============================================================================= */
+
+type streamOptions struct {
+	patches   bool
+	heartbeat time.Duration
+	origins   []string
+}
+
+// StreamOption configures StreamHandler.
+type StreamOption func(*streamOptions)
+
+// StreamPatches sends changes as JSON Patches (see JSONPatch) instead of
+// full snapshots.
+func StreamPatches() StreamOption {
+	return func(o *streamOptions) { o.patches = true }
+}
+
+// StreamHeartbeat sets how often an idle stream is kept alive with an SSE
+// comment or a WebSocket ping (default 30s).
+func StreamHeartbeat(d time.Duration) StreamOption {
+	return func(o *streamOptions) { o.heartbeat = d }
+}
+
+// StreamOrigins lists the origins, such as "https://app.example.com", whose
+// pages may open the stream over WebSocket; "*" allows any. By default
+// only the handler's own host is allowed.
+func StreamOrigins(origins ...string) StreamOption {
+	return func(o *streamOptions) { o.origins = append(o.origins, origins...) }
+}
+
+// StreamHandler serves the versions of s as they are recorded: over
+// WebSocket for upgrade requests and over Server-Sent Events otherwise.
+// SSE event IDs are versions, so a reconnecting EventSource resumes with
+// a patch from its Last-Event-ID when that version is still in the
+// history. WebSocket handshakes from pages of other origins are refused
+// unless StreamOrigins allows them.
+func StreamHandler(s *Store, opts ...StreamOption) http.Handler {
+	o := streamOptions{heartbeat: 30 * time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if websocket.IsUpgrade(r) {
+			serveWebSocket(w, r, s, &o)
+			return
+		}
+		serveSSE(w, r, s, &o)
+	})
+}
+
+// streamer produces the messages of one client.
+type streamer struct {
+	s    *Store
+	o    *streamOptions
+	last uint64 // version last sent, 0 before the first snapshot
+	wake chan struct{}
+	stop func()
+}
+
+func newStreamer(s *Store, o *streamOptions, last uint64) *streamer {
+	st := &streamer{s: s, o: o, last: last, wake: make(chan struct{}, 1)}
+	st.stop = s.Watch(func(Snapshot) {
+		select {
+		case st.wake <- struct{}{}:
+		default: // already pending; the next message covers this version
+		}
+	})
+	return st
+}
+
+// next returns the message bringing the client up to date with its type,
+// or false when the client already is.
+func (st *streamer) next() (cur Snapshot, typ string, data []byte, ok bool) {
+	var patch Value
+	if st.last > 0 && st.o.patches {
+		snap, changes, err := st.s.since(st.last)
+		if err == nil {
+			cur, patch = snap, JSONPatch(changes)
+		}
+	}
+	if patch.K == Invalid {
+		cur = st.s.Current()
+	}
+	if cur.Version == st.last {
+		return cur, "", nil, false
+	}
+	st.last = cur.Version
+
+	msg := map[string]Value{
+		"version": {K: Number, N: float64(cur.Version)},
+		"time":    New(cur.Time),
+	}
+	if typ = "snapshot"; patch.K == Array {
+		typ, msg["patch"] = "patch", patch
+	} else {
+		msg["root"] = cur.Root
+	}
+	msg["type"] = New(typ)
+	data, err := Value{K: Map, V: msg}.AppendJSON(nil)
+	if err != nil {
+		typ = "error"
+		data, _ = Value{K: Map, V: map[string]Value{
+			"type":    New(typ),
+			"version": msg["version"],
+			"error":   New(err.Error()),
+		}}.AppendJSON(nil)
+	}
+	return cur, typ, data, true
+}
+
+func serveSSE(w http.ResponseWriter, r *http.Request, s *Store, o *streamOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	last, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+	if _, err := s.At(last); err != nil {
+		last = 0
+	}
+	st := newStreamer(s, o, last)
+	defer st.stop()
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	tick := time.NewTicker(o.heartbeat)
+	defer tick.Stop()
+	for {
+		if cur, typ, data, ok := st.next(); ok {
+			if _, err := io.WriteString(w, "id: "+strconv.FormatUint(cur.Version, 10)+"\nevent: "+typ+"\ndata: "+string(data)+"\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-st.wake:
+		case <-tick.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func serveWebSocket(w http.ResponseWriter, r *http.Request, s *Store, o *streamOptions) {
+	if !o.allowOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	ws, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	st := newStreamer(s, o, 0)
+	defer st.stop()
+	tick := time.NewTicker(o.heartbeat)
+	defer tick.Stop()
+	for {
+		if _, _, data, ok := st.next(); ok {
+			if ws.Write(websocket.OpText, data) != nil {
+				return
+			}
+		}
+		select {
+		case <-ws.Done():
+			return
+		case <-st.wake:
+		case <-tick.C:
+			if ws.Write(websocket.OpPing, nil) != nil {
+				return
+			}
+		}
+	}
+}
+
+// allowOrigin reports whether a WebSocket handshake may proceed. Browsers
+// send cookies with cross-site WebSocket requests and apply no CORS, so
+// without StreamOrigins only pages from the handler's own host, and
+// clients sending no Origin at all, are let in.
+func (o *streamOptions) allowOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if len(o.origins) == 0 {
+		u, err := url.Parse(origin)
+		return err == nil && strings.EqualFold(u.Host, r.Host)
+	}
+	for _, allowed := range o.origins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
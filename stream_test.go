@@ -0,0 +1,178 @@
+package kit
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kitwork/kit/internal/websocket"
+)
+
+// readSSE returns the next event of an SSE stream, skipping comments.
+func readSSE(t *testing.T, r *bufio.Reader) (event string, data Value) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = line[7:]
+		case strings.HasPrefix(line, "data: "):
+			if data, err = FromJSON([]byte(line[6:])); err != nil {
+				t.Fatal(err)
+			}
+		case line == "" && event != "":
+			return event, data
+		}
+	}
+}
+
+func TestStreamHandler_SSE(t *testing.T) {
+	s := NewStore(New(map[string]any{"n": 1}), 10)
+	srv := httptest.NewServer(StreamHandler(s, StreamPatches(), StreamHeartbeat(10*time.Millisecond)))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if ev, data := readSSE(t, r); ev != "snapshot" || data.Path("root.n").Int() != 1 || data.Path("version").Int() != 1 {
+		t.Fatalf("first event = %s %v", ev, data)
+	}
+
+	s.Update(func(tx *Tx) error { return tx.Set("n", 2) })
+	ev, data := readSSE(t, r)
+	if ev != "patch" || data.Path("version").Int() != 2 {
+		t.Fatalf("second event = %s %v", ev, data)
+	}
+	if op := data.Path("patch.0"); op.Get("op").String() != "replace" || op.Get("path").String() != "/n" || op.Get("value").Int() != 2 {
+		t.Errorf("patch = %v", data.Get("patch"))
+	}
+
+	// Resuming from an event ID sends only what was missed.
+	s.Set(s.Value().Set("m", true))
+	req, _ := http.NewRequest("GET", srv.URL, nil)
+	req.Header.Set("Last-Event-ID", "2")
+	resp2, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp2.Body.Close()
+	if ev, data := readSSE(t, bufio.NewReader(resp2.Body)); ev != "patch" || data.Path("patch.0.path").String() != "/m" {
+		t.Errorf("resumed event = %s %v", ev, data)
+	}
+}
+
+func TestStreamHandler_WebSocket(t *testing.T) {
+	s := NewStore(New(map[string]any{"n": 1}), 10)
+	srv := httptest.NewServer(StreamHandler(s))
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", strings.TrimPrefix(srv.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	io.WriteString(conn, "GET / HTTP/1.1\r\nHost: x\r\nUpgrade: websocket\r\nConnection: keep-alive, Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n")
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The accept key for the RFC 6455 sample nonce.
+	if resp.StatusCode != 101 || resp.Header.Get("Sec-WebSocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake = %d %v", resp.StatusCode, resp.Header)
+	}
+
+	readFrame := func() (byte, Value) {
+		var hdr [2]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			t.Fatal(err)
+		}
+		n := int(hdr[1])
+		if n == 126 {
+			var ext [2]byte
+			io.ReadFull(r, ext[:])
+			n = int(binary.BigEndian.Uint16(ext[:]))
+		}
+		payload := make([]byte, n)
+		io.ReadFull(r, payload)
+		if hdr[0]&0x0f != websocket.OpText {
+			return hdr[0] & 0x0f, Value{}
+		}
+		v, err := FromJSON(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return websocket.OpText, v
+	}
+	if _, v := readFrame(); v.Get("type").String() != "snapshot" || v.Path("root.n").Int() != 1 {
+		t.Fatalf("first message = %v", v)
+	}
+	s.Set(New(map[string]any{"n": 5}))
+	if _, v := readFrame(); v.Get("type").String() != "snapshot" || v.Path("root.n").Int() != 5 {
+		t.Fatalf("second message = %v", v)
+	}
+
+	// A masked close frame is echoed and ends the stream.
+	conn.Write([]byte{0x80 | websocket.OpClose, 0x80 | 2, 0, 0, 0, 0, 0x03, 0xe8})
+	if op, _ := readFrame(); op != websocket.OpClose {
+		t.Errorf("got opcode %d, want close", op)
+	}
+}
+
+func TestStreamHandler_WebSocketOrigin(t *testing.T) {
+	s := NewStore(New(1), 2)
+	for _, c := range []struct {
+		origin string
+		opts   []StreamOption
+		want   int
+	}{
+		{"https://evil.example", nil, http.StatusForbidden},
+		{"https://kit.example", nil, http.StatusInternalServerError}, // allowed; the recorder cannot upgrade
+		{"https://evil.example", []StreamOption{StreamOrigins("https://app.example")}, http.StatusForbidden},
+		{"https://APP.example", []StreamOption{StreamOrigins("https://app.example")}, http.StatusInternalServerError},
+		{"https://evil.example", []StreamOption{StreamOrigins("*")}, http.StatusInternalServerError},
+	} {
+		req := httptest.NewRequest("GET", "http://kit.example/", nil)
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Origin", c.origin)
+		rec := httptest.NewRecorder()
+		StreamHandler(s, c.opts...).ServeHTTP(rec, req)
+		if rec.Code != c.want {
+			t.Errorf("Origin %s with %d options: status %d, want %d", c.origin, len(c.opts), rec.Code, c.want)
+		}
+	}
+}
+
+func TestStoreWatch(t *testing.T) {
+	s := NewStore(New(1), 2)
+	var got []uint64
+	cancel := s.Watch(func(snap Snapshot) { got = append(got, snap.Version) })
+	s.Set(New(2))
+	s.Update(func(tx *Tx) error { return nil })
+	cancel()
+	s.Set(New(3))
+	if len(got) != 2 || got[0] != 2 || got[1] != 3 {
+		t.Errorf("watched versions = %v", got)
+	}
+}
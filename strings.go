@@ -0,0 +1,399 @@
+package kit
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   STRING OPERATIONS
+   Methods act on String Values and return Invalid for other kinds unless
+   documented otherwise.
+   ============================================================================= */
+
+// Split slices a String around each sep into an Array of Strings. An empty
+// sep splits into UTF-8 characters.
+func (v Value) Split(sep string) Value {
+	v = v.Force()
+	if v.K != String {
+		return Value{K: Invalid}
+	}
+	parts := strings.Split(v.String(), sep)
+	out := make([]Value, len(parts))
+	for i, p := range parts {
+		out[i] = Value{K: String, V: p}
+	}
+	return Value{K: Array, V: out}
+}
+
+// Join concatenates the Text forms of an Array's elements with sep.
+func (v Value) Join(sep string) Value {
+	a, ok := v.elements()
+	if !ok {
+		return Value{K: Invalid}
+	}
+	var b []byte
+	for i, e := range a {
+		if i > 0 {
+			b = append(b, sep...)
+		}
+		b = e.Force().Append(b)
+	}
+	return Value{K: String, V: string(b)}
+}
+
+// Upper returns a String with all letters upper-cased.
+func (v Value) Upper() Value { return v.mapString(strings.ToUpper) }
+
+// Lower returns a String with all letters lower-cased.
+func (v Value) Lower() Value { return v.mapString(strings.ToLower) }
+
+// Title upper-cases the first letter of every space-separated word.
+func (v Value) Title() Value { return v.mapString(title) }
+
+// CamelCase converts a String to camelCase ("user_id" → "userId").
+func (v Value) CamelCase() Value { return v.mapString(CamelCase) }
+
+// SnakeCase converts a String to snake_case ("UserID" → "user_id").
+func (v Value) SnakeCase() Value { return v.mapString(SnakeCase) }
+
+// KebabCase converts a String to kebab-case ("UserID" → "user-id").
+func (v Value) KebabCase() Value { return v.mapString(KebabCase) }
+
+func (v Value) mapString(fn func(string) string) Value {
+	v = v.Force()
+	if v.K != String {
+		return Value{K: Invalid}
+	}
+	return Value{K: String, V: fn(v.String())}
+}
+
+// MapKeys renames every Map key with fn, recursing into nested Maps and
+// Arrays, e.g. v.MapKeys(kit.SnakeCase) before sending a JSON response.
+// Keys that collide after renaming keep the value of the last one in sorted
+// order. Other kinds are returned unchanged.
+func (v Value) MapKeys(fn func(string) string) Value {
+	v = v.Force()
+	switch v.K {
+	case Map:
+		m := v.mapping()
+		out := make(map[string]Value, len(m))
+		for _, k := range sortedKeys(m) {
+			out[fn(k)] = m[k].MapKeys(fn)
+		}
+		return Value{K: Map, V: out}
+	case Array:
+		a := v.V.([]Value)
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = e.MapKeys(fn)
+		}
+		return Value{K: Array, V: out}
+	}
+	return v
+}
+
+// CamelCase joins the words of s as camelCase.
+func CamelCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		if i == 0 {
+			words[i] = strings.ToLower(w)
+		} else {
+			words[i] = capitalize(strings.ToLower(w))
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// PascalCase joins the words of s as PascalCase.
+func PascalCase(s string) string {
+	words := splitWords(s)
+	for i, w := range words {
+		words[i] = capitalize(strings.ToLower(w))
+	}
+	return strings.Join(words, "")
+}
+
+// SnakeCase joins the lower-cased words of s with underscores.
+func SnakeCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "_"))
+}
+
+// KebabCase joins the lower-cased words of s with hyphens.
+func KebabCase(s string) string {
+	return strings.ToLower(strings.Join(splitWords(s), "-"))
+}
+
+// splitWords breaks an identifier-like string into words at separators,
+// lower-to-upper transitions and the end of acronyms ("HTTPServer" →
+// "HTTP", "Server"). Digits stay attached to the preceding word.
+func splitWords(s string) []string {
+	var words []string
+	rs := []rune(s)
+	start := -1
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if start >= 0 {
+				words = append(words, string(rs[start:i]))
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+			continue
+		}
+		prev := rs[i-1]
+		boundary := unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)) ||
+			unicode.IsUpper(r) && unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1])
+		if boundary {
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToTitle(r)) + s[size:]
+}
+
+func title(s string) string {
+	b := make([]rune, 0, len(s))
+	atStart := true
+	for _, r := range s {
+		if atStart && unicode.IsLetter(r) {
+			r = unicode.ToTitle(r)
+		}
+		atStart = unicode.IsSpace(r)
+		b = append(b, r)
+	}
+	return string(b)
+}
+
+// TrimSpace removes leading and trailing white space from a String.
+func (v Value) TrimSpace() Value { return v.mapString(strings.TrimSpace) }
+
+// Trim removes leading and trailing runes contained in cutset; an empty
+// cutset trims white space.
+func (v Value) Trim(cutset string) Value {
+	if cutset == "" {
+		return v.TrimSpace()
+	}
+	return v.mapString(func(s string) string { return strings.Trim(s, cutset) })
+}
+
+// TrimPrefix removes a leading prefix from a String, if present.
+func (v Value) TrimPrefix(prefix string) Value {
+	return v.mapString(func(s string) string { return strings.TrimPrefix(s, prefix) })
+}
+
+// TrimSuffix removes a trailing suffix from a String, if present.
+func (v Value) TrimSuffix(suffix string) Value {
+	return v.mapString(func(s string) string { return strings.TrimSuffix(s, suffix) })
+}
+
+// PadLeft prepends copies of pad until the String is width runes long. The
+// padding is cut short rather than overshooting width; Strings already that
+// long, or an empty pad, are returned unchanged.
+func (v Value) PadLeft(width int, pad string) Value {
+	return v.mapString(func(s string) string { return padding(s, width, pad) + s })
+}
+
+// PadRight appends copies of pad until the String is width runes long.
+func (v Value) PadRight(width int, pad string) Value {
+	return v.mapString(func(s string) string { return s + padding(s, width, pad) })
+}
+
+// CollapseSpace trims a String and replaces every inner run of white space
+// with a single space.
+func (v Value) CollapseSpace() Value {
+	return v.mapString(func(s string) string { return strings.Join(strings.Fields(s), " ") })
+}
+
+func padding(s string, width int, pad string) string {
+	n := width - utf8.RuneCountInString(s)
+	if n <= 0 || pad == "" {
+		return ""
+	}
+	p := []rune(strings.Repeat(pad, n/utf8.RuneCountInString(pad)+1))
+	return string(p[:n])
+}
+
+// RuneLen returns the number of runes in a String, unlike Len which counts
+// bytes. Other kinds report Len.
+func (v Value) RuneLen() int {
+	v = v.Force()
+	if v.K == String {
+		return utf8.RuneCountInString(v.String())
+	}
+	return v.Len()
+}
+
+// RuneAt returns the i-th rune of a String as a String, or Nil when i is out
+// of range. Other kinds behave like Index.
+func (v Value) RuneAt(i int) Value {
+	v = v.Force()
+	if v.K != String {
+		return v.Index(i)
+	}
+	if i < 0 {
+		return Value{K: Nil}
+	}
+	for _, r := range v.String() {
+		if i == 0 {
+			return Value{K: String, V: string(r)}
+		}
+		i--
+	}
+	return Value{K: Nil}
+}
+
+// Slice returns elements [start, end) of an Array or Bytes, or runes
+// [start, end) of a String. Bounds are clamped to the valid range, so an
+// empty result is returned rather than Invalid. Arrays and Bytes share
+// storage with v.
+func (v Value) Slice(start, end int) Value {
+	v = v.Force()
+	clamp := func(n int) (int, int) {
+		i := min(max(start, 0), n)
+		return i, max(i, min(end, n))
+	}
+	switch v.K {
+	case Array:
+		a := v.V.([]Value)
+		i, j := clamp(len(a))
+		return Value{K: Array, V: a[i:j:j]}
+	case Bytes:
+		b := v.Bytes()
+		i, j := clamp(len(b))
+		return Value{K: Bytes, V: b[i:j:j]}
+	case String:
+		r := []rune(v.String())
+		i, j := clamp(len(r))
+		return Value{K: String, V: string(r[i:j])}
+	}
+	return Value{K: Invalid}
+}
+
+// HasPrefix reports whether the Text of v begins with the Text of x. x may
+// be a Value or any Go value accepted by New, so New(2024).HasPrefix("20")
+// holds. Blank values on either side never match.
+func (v Value) HasPrefix(x any) bool {
+	return v.textMatch(x, strings.HasPrefix)
+}
+
+// HasSuffix reports whether the Text of v ends with the Text of x.
+func (v Value) HasSuffix(x any) bool {
+	return v.textMatch(x, strings.HasSuffix)
+}
+
+// ContainsStr reports whether the Text of x occurs within the Text of v.
+// Unlike Contains it never searches Array elements: scalars are compared by
+// their Text, which suits "field contains 42" filters over Numbers.
+func (v Value) ContainsStr(x any) bool {
+	return v.textMatch(x, strings.Contains)
+}
+
+func (v Value) textMatch(x any, fn func(s, sub string) bool) bool {
+	v = v.Force()
+	xv, ok := x.(Value)
+	if !ok {
+		xv = New(x)
+	}
+	xv = xv.Force()
+	if v.IsBlank() || xv.IsBlank() {
+		return false
+	}
+	return fn(v.Text(), xv.Text())
+}
+
+//go:generate go run ./internal/gen -tables width -o widthtables.go
+
+// RuneWidth returns the number of terminal cells r occupies: 2 for East
+// Asian wide and fullwidth characters, 0 for combining marks and control
+// characters, 1 otherwise.
+func RuneWidth(r rune) int {
+	switch {
+	case r < 0x20 || r >= 0x7f && r < 0xa0:
+		return 0
+	case r < 0x300:
+		return 1
+	case inRanges(zeroWidth, r):
+		return 0
+	case inRanges(doubleWidth, r):
+		return 2
+	}
+	return 1
+}
+
+// StringWidth returns the display width of s in terminal cells.
+func StringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += RuneWidth(r)
+	}
+	return n
+}
+
+// Width returns the display width of a String, or of the Text of any other
+// Value.
+func (v Value) Width() int {
+	v = v.Force()
+	if v.K == String {
+		return StringWidth(v.String())
+	}
+	return StringWidth(v.Text())
+}
+
+// Truncate shortens a String to at most maxWidth terminal cells, ending it
+// with ellipsis when anything was cut. It never splits a rune or separates
+// a combining mark from its base, and a wide character that would straddle
+// the limit is dropped whole. An ellipsis wider than maxWidth is omitted.
+func (v Value) Truncate(maxWidth int, ellipsis string) Value {
+	return v.mapString(func(s string) string {
+		if StringWidth(s) <= maxWidth {
+			return s
+		}
+		limit := maxWidth - StringWidth(ellipsis)
+		if limit < 0 {
+			limit, ellipsis = maxWidth, ""
+		}
+		width, cut := 0, 0
+		for i, r := range s {
+			w := RuneWidth(r)
+			if w > 0 && width+w > limit {
+				break
+			}
+			width += w
+			_, size := utf8.DecodeRuneInString(s[i:])
+			cut = i + size
+		}
+		return s[:cut] + ellipsis
+	})
+}
+
+func inRanges(ranges [][2]rune, r rune) bool {
+	i, j := 0, len(ranges)
+	for i < j {
+		h := int(uint(i+j) >> 1)
+		switch {
+		case r < ranges[h][0]:
+			j = h
+		case r > ranges[h][1]:
+			i = h + 1
+		default:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+package kit
+
+import "testing"
+
+func TestValue_SplitJoin(t *testing.T) {
+	parts := New("a,b,,c").Split(",")
+	if !parts.Equal(New([]string{"a", "b", "", "c"})) {
+		t.Errorf("Split = %v", parts)
+	}
+	if got := parts.Join("-"); got.String() != "a-b--c" {
+		t.Errorf("Join = %q", got.String())
+	}
+	if got := New([]any{1, true, "x", nil}).Join(" "); got.String() != "1 true x null" {
+		t.Errorf("Join mixed = %q", got.String())
+	}
+	if !New(1).Split(",").IsInvalid() || !New("x").Join(",").IsInvalid() {
+		t.Error("Split/Join on wrong kinds should be Invalid")
+	}
+}
+
+func TestCaseConversion(t *testing.T) {
+	tests := []struct {
+		in, camel, snake, kebab, pascal string
+	}{
+		{"user_id", "userId", "user_id", "user-id", "UserId"},
+		{"UserID", "userId", "user_id", "user-id", "UserId"},
+		{"HTTPServer", "httpServer", "http_server", "http-server", "HttpServer"},
+		{"created at", "createdAt", "created_at", "created-at", "CreatedAt"},
+		{"v2Api", "v2Api", "v2_api", "v2-api", "V2Api"},
+		{"đặtHàng", "đặtHàng", "đặt_hàng", "đặt-hàng", "ĐặtHàng"},
+	}
+	for _, tt := range tests {
+		if got := CamelCase(tt.in); got != tt.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := SnakeCase(tt.in); got != tt.snake {
+			t.Errorf("SnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+		if got := KebabCase(tt.in); got != tt.kebab {
+			t.Errorf("KebabCase(%q) = %q, want %q", tt.in, got, tt.kebab)
+		}
+		if got := PascalCase(tt.in); got != tt.pascal {
+			t.Errorf("PascalCase(%q) = %q, want %q", tt.in, got, tt.pascal)
+		}
+	}
+
+	if New("xin chào").Title().String() != "Xin Chào" || New("Ab").Upper().String() != "AB" || New("Ab").Lower().String() != "ab" {
+		t.Error("Title/Upper/Lower")
+	}
+	if !New(1).SnakeCase().IsInvalid() {
+		t.Error("SnakeCase on Number should be Invalid")
+	}
+}
+
+func TestValue_MapKeys(t *testing.T) {
+	v := New(map[string]any{"UserID": 1, "Items": []any{map[string]any{"CreatedAt": "x"}}})
+	want := New(map[string]any{"user_id": 1, "items": []any{map[string]any{"created_at": "x"}}})
+	if got := v.MapKeys(SnakeCase); !got.Equal(want) {
+		t.Errorf("MapKeys = %v, want %v", got, want)
+	}
+}
+
+func TestValue_TrimPad(t *testing.T) {
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{New("  hi \n").TrimSpace(), "hi"},
+		{New("--hi-").Trim("-"), "hi"},
+		{New(" hi ").Trim(""), "hi"},
+		{New("v1.2").TrimPrefix("v"), "1.2"},
+		{New("a.json").TrimSuffix(".json"), "a"},
+		{New("7").PadLeft(3, "0"), "007"},
+		{New("ab").PadRight(5, "-="), "ab-=-"},
+		{New("việt").PadLeft(5, " "), " việt"},
+		{New("long").PadLeft(2, "0"), "long"},
+		{New(" a \t b\n c ").CollapseSpace(), "a b c"},
+	}
+	for i, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got.String(), tt.want)
+		}
+	}
+	if !New(7).PadLeft(3, "0").IsInvalid() {
+		t.Error("PadLeft on Number should be Invalid")
+	}
+}
+
+func TestValue_Runes(t *testing.T) {
+	v := New("Việt 🇻🇳")
+	if v.RuneLen() != 7 || v.Len() == 7 {
+		t.Errorf("RuneLen = %d, Len = %d", v.RuneLen(), v.Len())
+	}
+	if got := v.RuneAt(1).String(); got != "i" {
+		t.Errorf("RuneAt(1) = %q", got)
+	}
+	if got := v.RuneAt(2).String(); got != "ệ" {
+		t.Errorf("RuneAt(2) = %q", got)
+	}
+	if !v.RuneAt(7).IsNil() || !v.RuneAt(-1).IsNil() {
+		t.Error("RuneAt out of range should be Nil")
+	}
+	if got := v.Slice(0, 4).String(); got != "Việt" {
+		t.Errorf("Slice(0, 4) = %q", got)
+	}
+	if got := v.Slice(5, 100).String(); got != "🇻🇳" {
+		t.Errorf("Slice(5, 100) = %q", got)
+	}
+	if got := v.Slice(9, 2).String(); got != "" {
+		t.Errorf("Slice(9, 2) = %q", got)
+	}
+	if got := New([]any{1, 2, 3}).Slice(1, 5); !got.Equal(New([]any{2, 3})) {
+		t.Errorf("Array Slice = %v", got)
+	}
+	if got := New([]byte("abc")).Slice(-1, 2).Bytes(); string(got) != "ab" {
+		t.Errorf("Bytes Slice = %q", got)
+	}
+}
+
+func TestValue_TextPredicates(t *testing.T) {
+	tests := []struct {
+		got, want bool
+	}{
+		{New("order-42").HasPrefix("order"), true},
+		{New(2024).HasPrefix(20), true},
+		{New(2024).HasPrefix(New("24")), false},
+		{New(3.5).HasSuffix(".5"), true},
+		{New([]byte("abc")).HasSuffix("bc"), true},
+		{New(1042).ContainsStr(4), true},
+		{New(true).ContainsStr("ru"), true},
+		{New("abc").ContainsStr("x"), false},
+		{New(nil).HasPrefix("nu"), false},
+		{New("null").HasPrefix(nil), false},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%d: got %v, want %v", i, tt.got, tt.want)
+		}
+	}
+}
+
+func TestValue_Truncate(t *testing.T) {
+	tests := []struct {
+		in       string
+		width    int
+		ellipsis string
+		want     string
+	}{
+		{"hello world", 8, "...", "hello..."},
+		{"hello", 5, "…", "hello"},
+		{"日本語のテキスト", 7, "…", "日本語…"},
+		{"日本語", 4, "", "日本"},
+		{"Việt Nam", 5, "…", "Việt…"},
+		{"abc", 1, "...", "a"},
+		{"🍜🍜🍜", 5, "…", "🍜🍜…"},
+		{"a\xffb", 2, "", "a\xff"}, // invalid bytes are one cell each
+		{"a\xffé", 2, "", "a\xff"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.in).Truncate(tt.width, tt.ellipsis).String(); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+
+	if w := StringWidth("日本 ok"); w != 7 {
+		t.Errorf("StringWidth = %d", w)
+	}
+	// Unassigned code points are narrow outside the reserved ideograph ranges.
+	if RuneWidth(0x0378) != 1 || RuneWidth(0x2fffd) != 2 {
+		t.Errorf("RuneWidth(U+0378, U+2FFFD) = %d, %d", RuneWidth(0x0378), RuneWidth(0x2fffd))
+	}
+	if New(12345).Width() != 5 {
+		t.Error("Width of Number")
+	}
+}
@@ -0,0 +1,538 @@
+package kit
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+/* =============================================================================
+   STRUCT FIELDS
+   The dynamic view of Go structs, controlled by kit tags:
+
+	Name  string `kit:"name"`            renamed
+	Note  string `kit:"note,omitempty"`  left out when empty
+	Token string `kit:"-"`               never exposed
+	Audit Audit  `kit:",inline"`         fields spliced into the parent
+
+   Fields without a kit tag follow their json tag the same way, so paths
+   can use wire names such as "created_at". Embedded structs are inlined
+   unless their tag gives them a name. The fields of each type are
+   computed once and cached. When inlined fields share a name, the
+   shallowest wins, then a tagged one; a tie hides them all, as in
+   encoding/json.
+   ============================================================================= */
+
+type structField struct {
+	name      string
+	goName    string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+type structInfo struct {
+	fields   []structField // in declaration order
+	byName   map[string]int
+	byGoName map[string]int // of renamed fields
+}
+
+var structCache sync.Map // reflect.Type -> *structInfo
+
+// fieldsOf returns the exposed fields of a struct type.
+func fieldsOf(t reflect.Type) *structInfo {
+	if info, ok := structCache.Load(t); ok {
+		return info.(*structInfo)
+	}
+	info := &structInfo{byName: map[string]int{}, byGoName: map[string]int{}}
+	type level struct {
+		t     reflect.Type
+		index []int
+	}
+	seen := map[reflect.Type]bool{}
+	hidden := map[string]bool{}
+	for cur := []level{{t: t}}; len(cur) > 0; {
+		var next []level
+		var found []structField
+		for _, l := range cur {
+			if seen[l.t] {
+				continue
+			}
+			seen[l.t] = true
+			for i := 0; i < l.t.NumField(); i++ {
+				f := l.t.Field(i)
+				tag, ok := f.Tag.Lookup("kit")
+				if !ok {
+					tag = f.Tag.Get("json")
+				}
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(slices.Clip(l.index), i)
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				// Embedded structs are promoted unless the tag names them.
+				inline := hasTagOption(opts, "inline") || f.Anonymous && name == ""
+				if inline && ft.Kind() == reflect.Struct {
+					next = append(next, level{t: ft, index: index})
+					continue
+				}
+				if !f.IsExported() {
+					continue
+				}
+				if name == "" {
+					name = f.Name
+				}
+				found = append(found, structField{
+					name:      name,
+					goName:    f.Name,
+					index:     index,
+					omitEmpty: hasTagOption(opts, "omitempty"),
+					tagged:    tag != "" && tag[0] != ',',
+				})
+			}
+		}
+		// Fields at one depth compete by name; shallower names shadow them.
+		byName := map[string][]int{}
+		for i, f := range found {
+			byName[f.name] = append(byName[f.name], i)
+		}
+		for i, f := range found {
+			if hidden[f.name] {
+				continue
+			}
+			if dup := byName[f.name]; len(dup) > 1 && dominant(found, dup) != i {
+				continue
+			}
+			info.byName[f.name] = len(info.fields)
+			if _, ok := info.byGoName[f.goName]; !ok && f.goName != f.name {
+				info.byGoName[f.goName] = len(info.fields)
+			}
+			info.fields = append(info.fields, f)
+		}
+		for name := range byName {
+			hidden[name] = true
+		}
+		cur = next
+	}
+	actual, _ := structCache.LoadOrStore(t, info)
+	return actual.(*structInfo)
+}
+
+// dominant returns the only tagged field among same-named ones, or -1.
+func dominant(found []structField, dup []int) int {
+	win := -1
+	for _, j := range dup {
+		if found[j].tagged {
+			if win >= 0 {
+				return -1
+			}
+			win = j
+		}
+	}
+	return win
+}
+
+func hasTagOption(opts, name string) bool {
+	for opts != "" {
+		var o string
+		o, opts, _ = strings.Cut(opts, ",")
+		if o == name {
+			return true
+		}
+	}
+	return false
+}
+
+// structFieldByKey returns the field of a struct reached by key: first by
+// its exposed name, then by its Go name when a tag renames it, so paths
+// written against Go identifiers keep working. Fields tagged "-" are never
+// found.
+func structFieldByKey(rv reflect.Value, key string) (reflect.Value, bool) {
+	index, ok := structIndex(rv.Type(), key)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return fieldAt(rv, index)
+}
+
+// structIndex returns the field index of key in a struct type, as
+// structFieldByKey resolves it.
+func structIndex(t reflect.Type, key string) ([]int, bool) {
+	info := fieldsOf(t)
+	if i, ok := info.byName[key]; ok {
+		return info.fields[i].index, true
+	}
+	if i, ok := info.byGoName[key]; ok {
+		return info.fields[i].index, true
+	}
+	return nil, false
+}
+
+// setStructIn assigns x at path inside the addressable struct rv,
+// converting it as Bind does. With clone, rv is a copy owned by the caller
+// and what it shares with the original, embedded pointers, pointers to
+// structs and containers on the way, is copied before it is written, so
+// the original never changes. Without it, writes go through pointers and
+// nil pointers on the way are allocated.
+func setStructIn(rv reflect.Value, path []string, x Value, clone bool) error {
+	key, rest := path[0], path[1:]
+	index, ok := structIndex(rv.Type(), key)
+	if !ok {
+		return fmt.Errorf("kit: %s has no field %q", rv.Type(), key)
+	}
+	f, ok := allocField(rv, index)
+	if clone {
+		f, ok = cloneField(rv, index)
+	}
+	if !ok || !f.CanSet() {
+		return fmt.Errorf("kit: field %q of %s cannot be set", key, rv.Type())
+	}
+	if len(rest) == 0 {
+		if clone {
+			f.SetZero() // bind would write through pointers and into maps
+		}
+		return bind(f, x, key)
+	}
+	if t := f.Type(); t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct && t.Elem() != timeType {
+		if !clone {
+			if f.IsNil() {
+				f.Set(reflect.New(t.Elem()))
+			}
+			return setStructIn(f.Elem(), rest, x, false)
+		}
+		cp := reflect.New(t.Elem())
+		if !f.IsNil() {
+			cp.Elem().Set(f.Elem())
+		}
+		if err := setStructIn(cp.Elem(), rest, x, true); err != nil {
+			return err
+		}
+		f.Set(cp)
+		return nil
+	}
+	if f.Kind() == reflect.Struct && f.Type() != timeType {
+		return setStructIn(f, rest, x, clone)
+	}
+	out, err := New(f.Interface()).setIn(rest, x)
+	if err != nil {
+		return err
+	}
+	if clone {
+		f.SetZero()
+	}
+	return bind(f, out, key)
+}
+
+// cloneField is allocField for setStructIn: embedded pointers on the way
+// are replaced by pointers to copies, allocated when nil.
+func cloneField(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if !rv.CanSet() {
+				return reflect.Value{}, false
+			}
+			cp := reflect.New(rv.Type().Elem())
+			if !rv.IsNil() {
+				cp.Elem().Set(rv.Elem())
+			}
+			rv.Set(cp)
+			rv = cp.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+// fieldAt returns the field at index in rv and false when a nil embedded
+// pointer is in the way.
+func fieldAt(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return reflect.Value{}, false
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
+
+func isEmptyField(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Struct:
+		return false
+	}
+	return rv.IsZero()
+}
+
+// ToMap converts a Struct into a Map of its fields as named by their kit
+// or json tags, converting Structs nested in fields, Arrays and Maps too. Maps are
+// returned as is and other kinds give Invalid.
+func (v Value) ToMap() Value {
+	v = v.Force()
+	switch v.K {
+	case Map:
+		return v
+	case Struct:
+		return expandStructs(v)
+	}
+	return Value{K: Invalid}
+}
+
+func expandStructs(v Value) Value {
+	v = v.Force()
+	switch v.K {
+	case Struct:
+		rv := reflect.ValueOf(v.V)
+		for rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				return Value{K: Nil}
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return v
+		}
+		info := fieldsOf(rv.Type())
+		out := make(map[string]Value, len(info.fields))
+		for _, f := range info.fields {
+			fv, ok := fieldAt(rv, f.index)
+			if !ok || f.omitEmpty && isEmptyField(fv) {
+				continue
+			}
+			out[f.name] = expandStructs(New(fv.Interface()))
+		}
+		return Value{K: Map, V: out}
+	case Array:
+		a := v.V.([]Value)
+		if a == nil {
+			return v
+		}
+		out := make([]Value, len(a))
+		for i, e := range a {
+			out[i] = expandStructs(e)
+		}
+		return Value{K: Array, V: out}
+	case Map:
+		m := v.mapping()
+		if m == nil {
+			return v
+		}
+		out := make(map[string]Value, len(m))
+		for k, e := range m {
+			out[k] = expandStructs(e)
+		}
+		return Value{K: Map, V: out}
+	}
+	return v
+}
+
+/* =============================================================================
+   BINDING
+   The reverse of ToMap: decoding a Value into Go data, matching Map keys
+   to struct fields by the same kit tags.
+   ============================================================================= */
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Bind stores v into the Go value dst points to. Maps fill structs, whose
+// fields are matched by their kit or json names, and string-keyed Go maps; Arrays
+// fill slices and arrays. Scalars convert as ToBool, ToTime and
+// ToDuration do, and Strings holding numbers fill numeric fields. Map keys
+// without a field and fields without a key are left alone. Fields of type
+// Value and any receive the Value and its Export form, and json.RawMessage
+// fields its JSON encoding.
+func (v Value) Bind(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("kit: Bind needs a non-nil pointer, got %T", dst)
+	}
+	return bind(rv.Elem(), v, "")
+}
+
+func bind(rv reflect.Value, v Value, path string) error {
+	v = v.Force()
+	t := rv.Type()
+	fail := func() error {
+		if path == "" {
+			return fmt.Errorf("kit: cannot bind %s to %s", v.K, t)
+		}
+		return fmt.Errorf("kit: cannot bind %s to %s at %q", v.K, t, path)
+	}
+	switch {
+	case t == valueType:
+		rv.Set(reflect.ValueOf(v))
+		return nil
+	case v.K == Nil || v.K == Invalid:
+		rv.SetZero()
+		return nil
+	case t == timeType:
+		x := v.ToTime()
+		if x.K != Time {
+			return fail()
+		}
+		rv.Set(reflect.ValueOf(x.goTime()))
+		return nil
+	case t == durationType:
+		x := v.ToDuration()
+		if x.K != Duration {
+			return fail()
+		}
+		rv.SetInt(int64(x.N))
+		return nil
+	case t == rawMessageType:
+		data, err := v.AppendJSON(nil)
+		if err != nil {
+			return err
+		}
+		rv.SetBytes(data)
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			rv.Set(reflect.New(t.Elem()))
+		}
+		return bind(rv.Elem(), v, path)
+	case reflect.Interface:
+		x := reflect.ValueOf(v.Export())
+		if t.NumMethod() > 0 && !x.Type().Implements(t) {
+			return fail()
+		}
+		rv.Set(x)
+	case reflect.Bool:
+		x := v.ToBool()
+		if x.K != Bool {
+			return fail()
+		}
+		rv.SetBool(x.IsTrue())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := v.number()
+		// Range first: converting a float64 outside int64 is undefined.
+		if !ok || n != math.Trunc(n) || n < -1<<63 || n >= 1<<63 || rv.OverflowInt(int64(n)) {
+			return fail()
+		}
+		rv.SetInt(int64(n))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, ok := v.number()
+		if !ok || n < 0 || n != math.Trunc(n) || n >= 1<<64 || rv.OverflowUint(uint64(n)) {
+			return fail()
+		}
+		rv.SetUint(uint64(n))
+	case reflect.Float32, reflect.Float64:
+		n, ok := v.number()
+		if !ok || rv.OverflowFloat(n) {
+			return fail()
+		}
+		rv.SetFloat(n)
+	case reflect.String:
+		switch v.K {
+		case String, Bytes, Number:
+			rv.SetString(v.Text())
+		default:
+			return fail()
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 && (v.K == Bytes || v.K == String) {
+			rv.SetBytes(slices.Clone(v.ByteSlice()))
+			return nil
+		}
+		if v.K != Array {
+			return fail()
+		}
+		a := v.V.([]Value)
+		out := reflect.MakeSlice(t, len(a), len(a))
+		for i, e := range a {
+			if err := bind(out.Index(i), e, joinPath(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+	case reflect.Array:
+		if v.K != Array || v.Len() > rv.Len() {
+			return fail()
+		}
+		rv.SetZero()
+		for i, e := range v.V.([]Value) {
+			if err := bind(rv.Index(i), e, joinPath(path, fmt.Sprint(i))); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.K != Map || t.Key().Kind() != reflect.String {
+			return fail()
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(t))
+		}
+		for k, e := range v.mapping() {
+			x := reflect.New(t.Elem()).Elem()
+			if err := bind(x, e, joinPath(path, k)); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(t.Key()), x)
+		}
+	case reflect.Struct:
+		if v.K == Struct {
+			if x := reflect.Indirect(reflect.ValueOf(v.V)); x.Type() == t {
+				rv.Set(x)
+				return nil
+			}
+		}
+		if v.K != Map {
+			return fail()
+		}
+		info := fieldsOf(t)
+		for k, e := range v.mapping() {
+			i, ok := info.byName[k]
+			if !ok {
+				continue
+			}
+			f, ok := allocField(rv, info.fields[i].index)
+			if !ok {
+				continue
+			}
+			if err := bind(f, e, joinPath(path, k)); err != nil {
+				return err
+			}
+		}
+	default:
+		return fail()
+	}
+	return nil
+}
+
+// allocField is fieldAt allocating nil embedded pointers on the way; it
+// fails on those it cannot set, being unexported.
+func allocField(rv reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && rv.Kind() == reflect.Pointer {
+			if rv.IsNil() {
+				if !rv.CanSet() {
+					return reflect.Value{}, false
+				}
+				rv.Set(reflect.New(rv.Type().Elem()))
+			}
+			rv = rv.Elem()
+		}
+		rv = rv.Field(x)
+	}
+	return rv, true
+}
@@ -0,0 +1,295 @@
+package kit
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type audit struct {
+	CreatedBy string `kit:"created_by"`
+	Updated   time.Time
+}
+
+type account struct {
+	ID      int      `kit:"id"`
+	Name    string   `kit:"name"`
+	Note    string   `kit:"note,omitempty"`
+	Tags    []string `kit:"tags,omitempty"`
+	Token   string   `kit:"-"`
+	Audit   audit    `kit:",inline"`
+	Owner   *account `kit:"owner,omitempty"`
+	private int
+}
+
+func TestValue_ToMap(t *testing.T) {
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	acc := account{ID: 1, Name: "ops", Token: "secret", private: 7,
+		Audit: audit{CreatedBy: "an", Updated: at},
+		Owner: &account{ID: 2, Name: "root", Tags: []string{"admin"}}}
+
+	got := New(&acc).ToMap()
+	want := New(map[string]any{
+		"id": 1, "name": "ops", "created_by": "an", "Updated": at,
+		"owner": map[string]any{"id": 2, "name": "root", "tags": []any{"admin"}, "created_by": "", "Updated": time.Time{}},
+	})
+	if !got.Equal(want) {
+		t.Errorf("ToMap = %s\nwant  %s", got, want)
+	}
+	if got := New([]any{acc}).ToMap(); got.K != Invalid {
+		t.Errorf("ToMap(Array) = %s, want Invalid", got)
+	}
+
+	// Inlined names lose to direct ones; equal-depth ties hide both.
+	type a struct{ X, Y int }
+	type b struct {
+		Y int
+		Z int `kit:"X"`
+	}
+	type clash struct {
+		X int
+		A a `kit:",inline"`
+		B b `kit:",inline"`
+	}
+	if got := New(clash{X: 1, A: a{2, 3}, B: b{4, 5}}).ToMap(); !got.Equal(New(map[string]any{"X": 1})) {
+		t.Errorf("ToMap(clash) = %s", got)
+	}
+}
+
+func TestValue_Bind(t *testing.T) {
+	src := New(map[string]any{
+		"id": "7", "name": "ops", "tags": []any{"a", "b"}, "Token": "ignored",
+		"created_by": "an", "Updated": "2024-05-01T00:00:00Z",
+		"owner": map[string]any{"id": 2.0},
+		"extra": true,
+	})
+	var acc account
+	if err := src.Bind(&acc); err != nil {
+		t.Fatal(err)
+	}
+	if acc.ID != 7 || acc.Name != "ops" || strings.Join(acc.Tags, ",") != "a,b" || acc.Token != "" ||
+		acc.Audit.CreatedBy != "an" || !acc.Audit.Updated.Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) ||
+		acc.Owner == nil || acc.Owner.ID != 2 {
+		t.Errorf("Bind = %+v", acc)
+	}
+
+	var cfg struct {
+		Timeout time.Duration
+		Debug   bool
+		Limits  map[string]uint8
+		Raw     Value
+		Any     any
+		Pair    [2]float64
+	}
+	err := New(map[string]any{
+		"Timeout": "1m30s", "Debug": "yes", "Limits": map[string]any{"a": 3},
+		"Raw": []any{1}, "Any": map[string]any{"k": 1}, "Pair": []any{1.5},
+	}).Bind(&cfg)
+	if err != nil || cfg.Timeout != 90*time.Second || !cfg.Debug || cfg.Limits["a"] != 3 ||
+		cfg.Raw.Len() != 1 || cfg.Any.(map[string]any)["k"] != 1.0 || cfg.Pair != [2]float64{1.5, 0} {
+		t.Errorf("Bind = %+v, %v", cfg, err)
+	}
+
+	for _, c := range []struct {
+		src  any
+		want string
+	}{
+		{map[string]any{"id": 1.5}, `kit: cannot bind Number to int at "id"`},
+		{map[string]any{"id": 1e19}, `kit: cannot bind Number to int at "id"`},
+		{map[string]any{"id": -1e19}, `kit: cannot bind Number to int at "id"`},
+		{map[string]any{"id": float64(1 << 63)}, `kit: cannot bind Number to int at "id"`},
+		{map[string]any{"tags": []any{1, map[string]any{}}}, `kit: cannot bind Map to string at "tags.1"`},
+		{map[string]any{"owner": map[string]any{"id": -1 << 62}}, ""},
+		{"x", "kit: cannot bind String to kit.account"},
+	} {
+		err := New(c.src).Bind(&account{})
+		if got := ""; err != nil {
+			got = err.Error()
+			if got != c.want {
+				t.Errorf("Bind(%v) = %q, want %q", c.src, got, c.want)
+			}
+		} else if c.want != "" {
+			t.Errorf("Bind(%v) succeeded, want %q", c.src, c.want)
+		}
+	}
+	if err := New(1).Bind(account{}); err == nil {
+		t.Error("Bind into a non-pointer succeeded")
+	}
+	var big struct{ N uint64 }
+	for _, n := range []float64{1 << 64, 1e20} {
+		if err := New(map[string]any{"N": n}).Bind(&big); err == nil {
+			t.Errorf("Bind(%g) into uint64 = %d, want an error", n, big.N)
+		}
+	}
+}
+
+type Entity struct {
+	ID      int `kit:"id"`
+	Created time.Time
+}
+
+type named struct{ Name string }
+
+type Owner struct{ Name, Email string }
+
+type project struct {
+	Entity
+	*named
+	Owner  `kit:"owner"`
+	Title  string `kit:"title"`
+	Secret string `kit:"-"`
+}
+
+func TestValue_GetStruct(t *testing.T) {
+	p := New(&project{
+		Entity: Entity{ID: 3},
+		named:  &named{Name: "kit"},
+		Owner:  Owner{Name: "An", Email: "an@example.com"},
+		Title:  "Roadmap",
+		Secret: "x",
+	})
+	cases := []struct {
+		path string
+		want Value
+	}{
+		{"id", New(3)},                         // promoted, by tag
+		{"ID", New(3)},                         // promoted, by Go name
+		{"Created", New(time.Time{})},          // promoted, untagged
+		{"Name", New("kit")},                   // through an unexported embedded pointer
+		{"owner.Email", New("an@example.com")}, // named embedded struct
+		{"Email", Value{K: Nil}},               // not promoted
+		{"title", New("Roadmap")},
+		{"Title", New("Roadmap")},
+		{"Secret", Value{K: Nil}},
+		{"named", Value{K: Nil}},
+	}
+	for _, c := range cases {
+		if got := p.Path(c.path); got.K != c.want.K || !got.Equal(c.want) {
+			t.Errorf("Path(%q) = %s, want %s", c.path, got, c.want)
+		}
+	}
+	if !p.Has("id") || !p.Exists("owner.Name") || p.Has("Secret") {
+		t.Error("Has and Exists disagree with Get")
+	}
+	if got := New(project{}).Get("Name"); got.K != Nil {
+		t.Errorf("Get through a nil embedded pointer = %s, want Nil", got)
+	}
+
+	// Equal-depth promotions conflict and hide each other, as in Go.
+	type A struct{ X int }
+	type B struct{ X int }
+	type both struct {
+		A
+		B
+	}
+	if got := New(both{A{1}, B{2}}).Get("X"); got.K != Nil {
+		t.Errorf("ambiguous Get = %s, want Nil", got)
+	}
+
+	m := p.ToMap()
+	if m.Get("id").Int() != 3 || m.Get("Name").String() != "kit" || m.Path("owner.Email").String() != "an@example.com" || m.Len() != 5 {
+		t.Errorf("ToMap = %s", m)
+	}
+}
+
+func TestValue_GetJSONTag(t *testing.T) {
+	type meta struct {
+		CreatedAt time.Time `json:"created_at"`
+		Internal  string    `json:"-"`
+	}
+	type order struct {
+		meta
+		ID     int     `json:"id,string"`
+		Total  float64 `json:"total,omitempty"`
+		Status string  `json:"status" kit:"state"`
+		Note   string  `json:",omitempty"`
+	}
+	at := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	o := New(order{meta: meta{CreatedAt: at, Internal: "x"}, ID: 9, Status: "paid"})
+
+	for path, want := range map[string]Value{
+		"created_at": New(at),
+		"CreatedAt":  New(at),
+		"id":         New(9),
+		"state":      New("paid"), // kit tags win
+		"status":     {K: Nil},
+		"Internal":   {K: Nil},
+	} {
+		if got := o.Path(path); got.K != want.K || !got.Equal(want) {
+			t.Errorf("Path(%q) = %s, want %s", path, got, want)
+		}
+	}
+	want := New(map[string]any{"created_at": at, "id": 9, "state": "paid"})
+	if got := o.ToMap(); !got.Equal(want) {
+		t.Errorf("ToMap = %s, want %s", got, want)
+	}
+
+	var back order
+	if err := want.Set("total", 2.5).Bind(&back); err != nil || back.Total != 2.5 || !back.CreatedAt.Equal(at) {
+		t.Errorf("Bind = %+v, %v", back, err)
+	}
+}
+
+func TestValue_SetStruct(t *testing.T) {
+	type limits struct{ Max int }
+	type server struct {
+		Entity
+		Host    string            `kit:"host"`
+		Timeout time.Duration     `json:"timeout"`
+		Limits  *limits           `kit:"limits"`
+		Labels  map[string]string `kit:"labels"`
+		Ports   []int
+	}
+	srv := &server{Host: "a", Ports: []int{80}, Limits: &limits{Max: 1}, Labels: map[string]string{"env": "dev"}}
+	v := New(srv)
+
+	if got := v.Set("host", "b").Set("timeout", "1m").Set("ID", "4"); got.V != any(srv) {
+		t.Errorf("Set returned %s, want the same Struct", got)
+	}
+	if srv.Host != "b" || srv.Timeout != time.Minute || srv.ID != 4 {
+		t.Errorf("after Set: %+v", srv)
+	}
+
+	_, err := v.Update(func(tx *Tx) error {
+		tx.Set("limits.Max", 10)
+		tx.Set("labels.env", "prod")
+		return tx.Set("Ports.0", 8080)
+	})
+	if err != nil || srv.Limits.Max != 10 || srv.Labels["env"] != "prod" || srv.Ports[0] != 8080 {
+		t.Errorf("Update = %v: %+v", err, srv)
+	}
+	fresh := &server{}
+	if New(fresh).Set("limits", map[string]any{"Max": 2}); fresh.Limits == nil || fresh.Limits.Max != 2 {
+		t.Errorf("Set on a nil pointer field: %+v", fresh.Limits)
+	}
+
+	// A Struct held by value is copied on write, along with what it shares.
+	orig := *srv
+	byVal := New(orig).Set("host", "c")
+	if up, err := byVal.Update(func(tx *Tx) error { return tx.Set("limits.Max", 20) }); err != nil ||
+		up.V.(server).Host != "c" || up.V.(server).Limits.Max != 20 {
+		t.Errorf("Set on a Struct held by value = %s, %v", up, err)
+	}
+	if orig.Host != "b" || srv.Limits.Max != 10 {
+		t.Errorf("Set on a Struct held by value wrote through: %+v %+v", orig, srv.Limits)
+	}
+
+	for _, c := range []struct {
+		v   Value
+		key string
+		x   any
+	}{
+		{v, "host", []any{1}}, // unconvertible
+		{v, "missing", 1},     // no such field
+	} {
+		if got := c.v.Set(c.key, c.x); got.K != Invalid {
+			t.Errorf("Set(%q, %v) = %s, want Invalid", c.key, c.x, got)
+		}
+	}
+	if _, err := v.Update(func(tx *Tx) error { return tx.Set("Ports.5", 1) }); err == nil {
+		t.Error("Set past the end of a slice field succeeded")
+	}
+	if srv.Host != "b" {
+		t.Errorf("failed Sets changed Host to %q", srv.Host)
+	}
+}
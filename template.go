@@ -0,0 +1,68 @@
+package kit
+
+import "strings"
+
+// FuncMap returns helpers for text/template and html/template that accept
+// Values or plain Go data:
+//
+//	get    v "a.b.0"       dotted path lookup
+//	at     v "items" 0     At with mixed keys and indexes
+//	default "n/a" v        v unless it is blank or falsy-empty
+//	len    v               Len
+//	json   v               compact JSON text
+//	text   v               Text form
+//	upper, lower v         case conversion of the Text form
+//	kind   v               Kind name
+//	abs floor ceil sqrt v  Number math of the same name
+//	round  2 v             Round to 2 places, halves away from zero
+//	pow v x, mod v x       Pow and Mod
+//	clamp  0 100 v         Clamp to a range
+//	random lo hi           Number in [lo, hi)
+//	randint lo hi          integral Number in [lo, hi]
+//	shuffle v, pick v      shuffled Array, random element
+//	message tag pattern v  MessageLocale with v as the arguments
+//
+// As with default, round and clamp take v last so they chain in pipelines:
+// {{.price | round 2}}. The random helpers draw from a source seeded at
+// start-up; use a Rand in Go code for reproducible draws.
+//
+// The result is assignable to both template.FuncMap types.
+func FuncMap() map[string]any {
+	return map[string]any{
+		"get": func(v any, path string) Value { return New(v).Path(path) },
+		"at":  func(v any, path ...any) Value { return New(v).At(path...) },
+		"default": func(def, v any) any {
+			if x := New(v); x.IsBlank() || x.K == String && x.String() == "" {
+				return def
+			}
+			return v
+		},
+		"len": func(v any) int { return New(v).Len() },
+		"json": func(v any) (string, error) {
+			b, err := New(v).AppendJSON(nil)
+			return string(b), err
+		},
+		"text":  func(v any) string { return New(v).Text() },
+		"upper": func(v any) string { return strings.ToUpper(New(v).Text()) },
+		"lower": func(v any) string { return strings.ToLower(New(v).Text()) },
+		"kind":  func(v any) string { return New(v).Force().K.String() },
+		"abs":   func(v any) Value { return New(v).Abs() },
+		"floor": func(v any) Value { return New(v).Floor() },
+		"ceil":  func(v any) Value { return New(v).Ceil() },
+		"sqrt":  func(v any) Value { return New(v).Sqrt() },
+		"round": func(places int, v any) Value { return New(v).Round(places, RoundHalfUp) },
+		"pow":   func(v, x any) Value { return New(v).Pow(New(x)) },
+		"mod":   func(v, x any) Value { return New(v).Mod(New(x)) },
+		"clamp": func(lo, hi, v any) Value { return New(v).Clamp(New(lo), New(hi)) },
+
+		"random":  func(lo, hi float64) Value { return defaultRand.Number(lo, hi) },
+		"randint": func(lo, hi int64) Value { return defaultRand.Int(lo, hi) },
+		"shuffle": func(v any) Value { return defaultRand.Shuffle(New(v)) },
+		"pick":    func(v any) Value { return defaultRand.Pick(New(v)) },
+
+		"message": func(tag, pattern string, env any) (string, error) {
+			out := MessageLocale(tag, pattern, New(env))
+			return out.Text(), out.Err()
+		},
+	}
+}
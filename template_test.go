@@ -0,0 +1,52 @@
+package kit
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	"text/template"
+)
+
+func TestFuncMap(t *testing.T) {
+	data := New(map[string]any{
+		"user":  map[string]any{"name": "an", "tags": []string{"x", "y"}},
+		"empty": "",
+	})
+
+	tmpl := template.Must(template.New("t").Funcs(FuncMap()).Parse(
+		`{{get . "user.name" | upper}} {{len (get . "user.tags")}} {{at . "user" "tags" 1}} ` +
+			`{{get . "empty" | default "n/a"}} {{get . "user.tags" | json}} {{kind .}}`))
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		t.Fatal(err)
+	}
+	if want := `AN 2 y n/a ["x","y"] Map`; sb.String() != want {
+		t.Errorf("Execute = %q, want %q", sb.String(), want)
+	}
+
+	tmpl = template.Must(template.New("m").Funcs(FuncMap()).Parse(
+		`{{.price | round 2}} {{.qty | clamp 1 10}} {{mod .qty 5}} {{pow 2 10}} {{sqrt -1}} {{randint 3 3}}`))
+	sb.Reset()
+	if err := tmpl.Execute(&sb, map[string]any{"price": 2.675, "qty": 12}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `2.68 10 2 1024 null 3`; sb.String() != want {
+		t.Errorf("Execute = %q, want %q", sb.String(), want)
+	}
+
+	tmpl = template.Must(template.New("msg").Funcs(FuncMap()).Parse(
+		`{{message "vi" "{n, plural, other{# tin nhắn}}" .}}`))
+	sb.Reset()
+	if err := tmpl.Execute(&sb, map[string]any{"n": 1200}); err != nil {
+		t.Fatal(err)
+	}
+	if want := `1.200 tin nhắn`; sb.String() != want {
+		t.Errorf("Execute = %q, want %q", sb.String(), want)
+	}
+	if err := tmpl.Execute(&sb, map[string]any{}); err == nil {
+		t.Error("message without its argument rendered")
+	}
+
+	// The same map must plug into html/template.
+	htmltemplate.New("h").Funcs(FuncMap())
+}
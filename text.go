@@ -0,0 +1,53 @@
+package kit
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FromText infers a scalar Value from its textual form, the inverse of Text
+// for scalar kinds: "null", "true"/"false", numbers, RFC 3339 timestamps and
+// Go durations ("1h30m") are recognised; anything else is a String.
+func FromText(s string) Value {
+	switch s {
+	case "":
+		return Value{K: String, V: s}
+	case "null":
+		return Value{K: Nil}
+	case "true":
+		return Value{K: Bool, N: 1}
+	case "false":
+		return Value{K: Bool}
+	}
+	if c := s[0]; c == '-' || c == '+' || c == '.' || c >= '0' && c <= '9' {
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return Value{K: Number, N: n}
+		}
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return New(t)
+		}
+		if d, err := time.ParseDuration(s); err == nil {
+			return Value{K: Duration, N: float64(d)}
+		}
+	}
+	return Value{K: String, V: s}
+}
+
+// MarshalText implements encoding.TextMarshaler for scalar, String and Bytes
+// kinds using the Text form. Containers have no text form and fail.
+func (v Value) MarshalText() ([]byte, error) {
+	v = v.Force()
+	switch v.K {
+	case Nil, Number, Bool, Time, Duration, String, Bytes:
+		return v.Append(nil), nil
+	default:
+		return nil, fmt.Errorf("kit: cannot marshal %s as text", v.K)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using FromText.
+func (v *Value) UnmarshalText(text []byte) error {
+	*v = FromText(string(text))
+	return nil
+}
@@ -0,0 +1,49 @@
+package kit
+
+import (
+	"flag"
+	"testing"
+	"time"
+)
+
+func TestFromText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Value
+	}{
+		{"42", New(42)},
+		{"-1.5", New(-1.5)},
+		{"true", New(true)},
+		{"null", New(nil)},
+		{"1h30m", New(90 * time.Minute)},
+		{"2024-01-02T03:04:05Z", New(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))},
+		{"hello", New("hello")},
+		{"", New("")},
+		{"NaN", New("NaN")},
+	}
+	for _, tt := range tests {
+		if got := FromText(tt.in); !got.Equal(tt.want) {
+			t.Errorf("FromText(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValue_TextMarshaling(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	var v Value
+	fs.TextVar(&v, "limit", New(10), "limit")
+	if err := fs.Parse([]string{"-limit=25"}); err != nil {
+		t.Fatal(err)
+	}
+	if v.K != Number || v.Int() != 25 {
+		t.Errorf("flag value = %+v, want Number(25)", v)
+	}
+
+	if _, err := New([]int{1}).MarshalText(); err == nil {
+		t.Error("MarshalText on Array should fail")
+	}
+	b, err := New(time.Second).MarshalText()
+	if err != nil || string(b) != "1s" {
+		t.Errorf("MarshalText(1s) = %q, %v", b, err)
+	}
+}
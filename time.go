@@ -0,0 +1,419 @@
+package kit
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* =============================================================================
+   TIME
+   ============================================================================= */
+
+// timeLayouts are tried in order by ToTime when no layouts are given.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	"20060102T150405Z0700",
+	"20060102",
+	"2006",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+}
+
+// ToTime converts v to the Time kind. Strings are parsed with the given
+// layouts, or when none are given with RFC 3339, "2006-01-02" and its
+// variants, the compact ISO forms "20060102" and "2006", and the RFC
+// 1123/850/822 and ANSIC forms; times without a zone are taken as UTC.
+// Numbers are Unix timestamps whose unit is inferred from magnitude:
+// seconds, then milliseconds from 1e12, microseconds from 1e15 and
+// nanoseconds from 1e18. Without layouts, Strings of 9 or more digits are
+// Unix timestamps too; shorter ones are too likely a year or a date.
+// Time Values are returned as is; anything unparseable, or a timestamp
+// outside the years 1677 to 2262 that int64 nanoseconds span, is Invalid.
+func (v Value) ToTime(layouts ...string) Value {
+	v = v.Force()
+	switch v.K {
+	case Time:
+		return v
+	case Number:
+		return unixTime(v.N)
+	case String, Bytes:
+		s := strings.TrimSpace(v.Text())
+		if len(layouts) == 0 {
+			if len(s) >= 9 && strings.Trim(s, "0123456789") == "" {
+				n, _ := strconv.ParseFloat(s, 64)
+				return unixTime(n)
+			}
+			layouts = timeLayouts
+		}
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return New(t)
+			}
+		}
+	}
+	return Value{K: Invalid}
+}
+
+func unixTime(n float64) Value {
+	switch abs := math.Abs(n); {
+	case abs >= 1e18:
+	case abs >= 1e15:
+		n *= 1e3
+	case abs >= 1e12:
+		n *= 1e6
+	default:
+		n *= 1e9
+	}
+	// NaN and ±Inf fail this too; out of range, int64(n) is undefined.
+	if !(math.Abs(n) < 1<<63) {
+		return Value{K: Invalid}
+	}
+	return Value{K: Time, N: math.Round(n)}
+}
+
+// goTime returns the time.Time held by a Time Value, in its location.
+func (v Value) goTime() time.Time {
+	t := time.Unix(0, int64(v.N))
+	if loc, ok := v.V.(*time.Location); ok && loc != nil {
+		return t.In(loc)
+	}
+	return t
+}
+
+// In returns a Time representing the same instant in loc, which then
+// governs Text, FormatTime, JSON and Export. Other kinds are converted with
+// ToTime first; failures give Invalid.
+func (v Value) In(loc *time.Location) Value {
+	if v = v.ToTime(); v.K != Time || loc == nil {
+		return Value{K: Invalid}
+	}
+	return Value{K: Time, N: v.N, V: loc}
+}
+
+// UTC is shorthand for In(time.UTC).
+func (v Value) UTC() Value { return v.In(time.UTC) }
+
+// Location returns the location of a Time, or nil for other kinds. Times
+// built from Unix timestamps are in time.Local.
+func (v Value) Location() *time.Location {
+	if v = v.Force(); v.K != Time {
+		return nil
+	}
+	return v.goTime().Location()
+}
+
+// FormatTime formats a Time with a time package layout such as
+// "02/01/2006 15:04". A Duration is formatted as that much time after
+// midnight, so clock layouts like "15:04:05" render durations under a day.
+// Other kinds are converted with ToTime first; failures give Invalid.
+func (v Value) FormatTime(layout string) Value {
+	return v.FormatLocale(layout, "en")
+}
+
+// FormatLocale is FormatTime with month and weekday names ("January",
+// "Jan", "Monday", "Mon" in the layout) taken from the Locale registered
+// for tag.
+func (v Value) FormatLocale(layout, tag string) Value {
+	var t time.Time
+	switch v = v.Force(); v.K {
+	case Duration:
+		t = time.Time{}.Add(time.Duration(int64(v.N)))
+	default:
+		if v = v.ToTime(); v.K != Time {
+			return Value{K: Invalid}
+		}
+		t = v.goTime()
+	}
+	loc := lookupLocale(tag)
+
+	// Names are substituted between separately formatted chunks, so digits in
+	// a translated name are never mistaken for layout elements.
+	var b []byte
+	for layout != "" {
+		i, name := nextNameToken(layout)
+		b = t.AppendFormat(b, layout[:i])
+		if name == "" {
+			break
+		}
+		switch name {
+		case "January":
+			b = append(b, loc.Months[t.Month()-1]...)
+		case "Jan":
+			b = append(b, loc.ShortMonths[t.Month()-1]...)
+		case "Monday":
+			b = append(b, loc.Days[t.Weekday()]...)
+		case "Mon":
+			b = append(b, loc.ShortDays[t.Weekday()]...)
+		}
+		layout = layout[i+len(name):]
+	}
+	return Value{K: String, V: string(b)}
+}
+
+// nextNameToken finds the first month or weekday name element in layout,
+// returning its offset and text, or len(layout) and "" when there is none.
+func nextNameToken(layout string) (int, string) {
+	for i := 0; i < len(layout); i++ {
+		for _, name := range [...]string{"January", "Jan", "Monday", "Mon"} {
+			if strings.HasPrefix(layout[i:], name) {
+				return i, name
+			}
+		}
+	}
+	return len(layout), ""
+}
+
+// Unit is a calendar unit for TruncateTime and RoundTime.
+type Unit uint8
+
+const (
+	Second Unit = iota
+	Minute
+	Hour
+	Day
+	Week // starts on Monday, as in ISO 8601
+	Month
+	Year
+)
+
+// TruncateTime rounds a Time down to the start of its unit in the Time's
+// own location, so Day buckets begin at local midnight and Month buckets on
+// the 1st. Other kinds are converted with ToTime first; failures give
+// Invalid.
+func (v Value) TruncateTime(unit Unit) Value {
+	if v = v.ToTime(); v.K != Time {
+		return Value{K: Invalid}
+	}
+	return New(truncateTime(v.goTime(), unit))
+}
+
+// RoundTime rounds a Time to the nearest start of unit, halfway rounding up.
+func (v Value) RoundTime(unit Unit) Value {
+	if v = v.ToTime(); v.K != Time {
+		return Value{K: Invalid}
+	}
+	t := v.goTime()
+	lo := truncateTime(t, unit)
+	hi := nextUnit(lo, unit)
+	if t.Sub(lo) < hi.Sub(t) {
+		return New(lo)
+	}
+	return New(hi)
+}
+
+func truncateTime(t time.Time, unit Unit) time.Time {
+	y, m, d := t.Date()
+	switch unit {
+	case Second, Minute, Hour:
+		// Sub-day units cut the instant rather than rebuild the wall clock,
+		// which would land on the wrong side of a DST overlap.
+		size := time.Second
+		switch unit {
+		case Minute:
+			size = time.Minute
+		case Hour:
+			size = time.Hour
+		}
+		_, offset := t.Zone()
+		r := time.Duration(t.UnixNano()+int64(offset)*int64(time.Second)) % size
+		if r < 0 {
+			r += size
+		}
+		return t.Add(-r)
+	case Day:
+		return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	case Week:
+		back := (int(t.Weekday()) + 6) % 7
+		return time.Date(y, m, d-back, 0, 0, 0, 0, t.Location())
+	case Month:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextUnit returns the start of the unit following the truncated time t.
+func nextUnit(t time.Time, unit Unit) time.Time {
+	switch unit {
+	case Second:
+		return t.Add(time.Second)
+	case Minute:
+		return t.Add(time.Minute)
+	case Hour:
+		return t.Add(time.Hour)
+	case Day:
+		return t.AddDate(0, 0, 1)
+	case Week:
+		return t.AddDate(0, 0, 7)
+	case Month:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(1, 0, 0)
+	}
+}
+
+// AddDate adds years, months and days to a Time using calendar arithmetic
+// in the Time's location, keeping the wall clock across DST changes. Unlike
+// time.Time.AddDate, a day that does not exist in the target month is
+// clamped to its last day, so Jan 31 plus one month is the end of February
+// rather than early March. Days are added after the clamp. Other kinds are
+// converted with ToTime first; failures give Invalid.
+func (v Value) AddDate(years, months, days int) Value {
+	if v = v.ToTime(); v.K != Time {
+		return Value{K: Invalid}
+	}
+	t := v.goTime()
+	y, m, d := t.Date()
+	hour, minute, sec := t.Clock()
+	first := time.Date(y+years, m+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	last := first.AddDate(0, 1, -1).Day()
+	t = time.Date(first.Year(), first.Month(), min(d, last)+days, hour, minute, sec, t.Nanosecond(), t.Location())
+	return New(t)
+}
+
+// ToDuration converts v to the Duration kind. Strings may use Go syntax
+// ("1h30m") or ISO 8601 ("P1DT2H30M", "PT0.5S", "P2W", "-P1D"), where a day
+// is 24 hours and each designator may appear once, in Y M W D H M S order;
+// ISO years and months have no fixed length and are rejected unless zero,
+// use AddDate for those. Numbers are taken as seconds. Duration Values are
+// returned as is; anything else is Invalid.
+func (v Value) ToDuration() Value {
+	v = v.Force()
+	switch v.K {
+	case Duration:
+		return v
+	case Number:
+		return Value{K: Duration, N: math.Round(v.N * 1e9)}
+	case String, Bytes:
+		s := strings.TrimSpace(v.Text())
+		if d, err := time.ParseDuration(s); err == nil {
+			return New(d)
+		}
+		if d, ok := parseISODuration(s); ok {
+			return New(d)
+		}
+	}
+	return Value{K: Invalid}
+}
+
+func parseISODuration(s string) (time.Duration, bool) {
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "-"), "+")
+	if len(s) < 3 || s[0] != 'P' && s[0] != 'p' {
+		return 0, false
+	}
+	s = strings.ToUpper(s[1:])
+	var total float64
+	inTime := false
+	last := -1 // rank of the previous designator, which must increase
+	for s != "" {
+		if s[0] == 'T' {
+			if inTime || len(s) == 1 {
+				return 0, false
+			}
+			inTime = true
+			s = s[1:]
+			continue
+		}
+		i := strings.IndexAny(s, "YMWDHS")
+		if i <= 0 {
+			return 0, false
+		}
+		n, err := strconv.ParseFloat(strings.Replace(s[:i], ",", ".", 1), 64)
+		if err != nil || !(n >= 0) {
+			return 0, false
+		}
+		rank := strings.IndexByte("YMWD", s[i])
+		if inTime {
+			rank = 4 + strings.IndexByte("HMS", s[i])
+		}
+		if rank <= last {
+			return 0, false // repeated or out of order
+		}
+		last = rank
+		var unit time.Duration
+		switch c := s[i]; {
+		case c == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case c == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case c == 'H' && inTime:
+			unit = time.Hour
+		case c == 'M' && inTime:
+			unit = time.Minute
+		case c == 'S' && inTime:
+			unit = time.Second
+		case (c == 'Y' || c == 'M') && n == 0:
+		default:
+			return 0, false
+		}
+		total += n * float64(unit)
+		s = s[i+1:]
+	}
+	if total > math.MaxInt64 {
+		return 0, false
+	}
+	if neg {
+		total = -total
+	}
+	return time.Duration(math.Round(total)), true
+}
+
+// ISODuration formats a Duration in ISO 8601 form, such as "P1DT2H30M" or
+// "PT0.25S", with whole days split off from the time part. Other kinds are
+// converted with ToDuration first; failures give Invalid.
+func (v Value) ISODuration() Value {
+	if v = v.ToDuration(); v.K != Duration {
+		return Value{K: Invalid}
+	}
+	d := time.Duration(int64(v.N))
+	if d == 0 {
+		return Value{K: String, V: "PT0S"}
+	}
+	b := make([]byte, 0, 16)
+	u := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		u = -u
+	}
+	b = append(b, 'P')
+	const day = uint64(24 * time.Hour)
+	if days := u / day; days > 0 {
+		b = append(strconv.AppendUint(b, days, 10), 'D')
+		u %= day
+	}
+	if u == 0 {
+		return Value{K: String, V: string(b)}
+	}
+	b = append(b, 'T')
+	if h := u / uint64(time.Hour); h > 0 {
+		b = append(strconv.AppendUint(b, h, 10), 'H')
+		u %= uint64(time.Hour)
+	}
+	if m := u / uint64(time.Minute); m > 0 {
+		b = append(strconv.AppendUint(b, m, 10), 'M')
+		u %= uint64(time.Minute)
+	}
+	if u > 0 {
+		b = strconv.AppendUint(b, u/uint64(time.Second), 10)
+		if frac := u % uint64(time.Second); frac > 0 {
+			f := strconv.FormatUint(frac+uint64(time.Second), 10)[1:]
+			b = append(append(b, '.'), strings.TrimRight(f, "0")...)
+		}
+		b = append(b, 'S')
+	}
+	return Value{K: String, V: string(b)}
+}
@@ -0,0 +1,237 @@
+package kit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValue_ToTime(t *testing.T) {
+	want := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		in      Value
+		layouts []string
+	}{
+		{New("2024-03-05T14:30:00Z"), nil},
+		{New("2024-03-05T21:30:00+07:00"), nil},
+		{New("2024-03-05 14:30:00"), nil},
+		{New("Tue, 05 Mar 2024 14:30:00 GMT"), nil},
+		{New(want.Unix()), nil},
+		{New(want.UnixMilli()), nil},
+		{New("20240305T143000Z"), nil},
+		{New("05.03.2024 14:30"), []string{"02.01.2006 15:04"}},
+		{New(want), nil},
+	}
+	for _, tt := range tests {
+		got := tt.in.ToTime(tt.layouts...)
+		if got.K != Time || !got.Export().(time.Time).Equal(want) {
+			t.Errorf("ToTime(%v) = %v", tt.in, got)
+		}
+	}
+
+	if got := New("2024-03-05").ToTime(); !got.Export().(time.Time).Equal(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date only = %v", got)
+	}
+	for in, want := range map[string]time.Time{
+		"2024":     time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		"20240102": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+	} {
+		if got := New(in).ToTime(); got.K != Time || !got.Export().(time.Time).Equal(want) {
+			t.Errorf("ToTime(%q) = %v, want %v", in, got, want)
+		}
+	}
+	for _, in := range []any{"1709649000", "1709649000123", " 1709649000000000 ", 1709649000} {
+		if got := New(in).ToTime(); got.K != Time || got.Export().(time.Time).Unix() != 1709649000 {
+			t.Errorf("ToTime(%q) = %v, want Unix 1709649000", in, got)
+		}
+	}
+	for _, in := range []any{1e300, -1e300, math.NaN(), math.Inf(1), "99999999999999999999"} {
+		if got := New(in).ToTime(); !got.IsInvalid() {
+			t.Errorf("ToTime(%v) = %v, want Invalid", in, got)
+		}
+	}
+	if !New("170964900").ToTime("2006").IsInvalid() || !New("yesterday").ToTime().IsInvalid() || !New("2024-03-05").ToTime(time.Kitchen).IsInvalid() {
+		t.Error("unparseable input should be Invalid")
+	}
+}
+
+func TestValue_FormatTime(t *testing.T) {
+	tm := time.Date(2024, 3, 5, 14, 30, 0, 0, time.Local)
+	v := New(tm)
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{v.FormatTime("02/01/2006 15:04"), "05/03/2024 14:30"},
+		{v.FormatTime("Monday, January 2"), "Tuesday, March 5"},
+		{v.FormatLocale("Monday, 2 January 2006", "vi"), "Thứ Ba, 5 tháng 3 2024"},
+		{v.FormatLocale("Mon 2 Jan", "fr-CA"), "mar. 5 mars"},
+		{v.FormatLocale("2 January", "xx"), "5 March"},
+		{New(90 * time.Minute).FormatTime("15:04:05"), "01:30:00"},
+		{New(tm.Unix()).FormatTime("2006-01-02"), "2024-03-05"},
+	}
+	for i, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%d: got %q, want %q", i, tt.got.String(), tt.want)
+		}
+	}
+	if !New("soon").FormatTime("2006").IsInvalid() {
+		t.Error("FormatTime of unparseable String should be Invalid")
+	}
+
+	RegisterLocale("x-test", &Locale{Months: [12]string{2: "M3"}})
+	if got := v.FormatLocale("January", "X-Test").String(); got != "M3" {
+		t.Errorf("registered locale = %q", got)
+	}
+}
+
+func TestValue_Location(t *testing.T) {
+	hcm := time.FixedZone("ICT", 7*3600)
+	v := New(time.Date(2024, 3, 5, 21, 30, 0, 0, hcm))
+	if v.Location() != hcm || v.Text() != "2024-03-05T21:30:00+07:00" {
+		t.Errorf("New lost the location: %s", v.Text())
+	}
+	if got := v.Add(New(time.Hour)).FormatTime("15:04 MST").String(); got != "22:30 ICT" {
+		t.Errorf("Add = %q", got)
+	}
+	u := v.UTC()
+	if !u.Equal(v) || u.Text() != "2024-03-05T14:30:00Z" {
+		t.Errorf("UTC = %s", u.Text())
+	}
+	if data, _ := v.MarshalJSON(); string(data) != `"2024-03-05T21:30:00+07:00"` {
+		t.Errorf("JSON = %s", data)
+	}
+	if got := New("2024-03-05T21:30:00+07:00").ToTime().Text(); got != "2024-03-05T21:30:00+07:00" {
+		t.Errorf("parsed offset lost: %s", got)
+	}
+	if got := New("2024-03-05T14:30:00Z").In(hcm).FormatTime("2006-01-02 15:04").String(); got != "2024-03-05 21:30" {
+		t.Errorf("In = %q", got)
+	}
+	if New(1).Location() != nil || !New("x").In(hcm).IsInvalid() {
+		t.Error("Location/In on non-Time")
+	}
+}
+
+func TestValue_TruncateRoundTime(t *testing.T) {
+	hcm := time.FixedZone("ICT", 7*3600)
+	v := New(time.Date(2024, 3, 7, 14, 35, 40, 0, hcm)) // a Thursday
+	tests := []struct {
+		got  Value
+		want string
+	}{
+		{v.TruncateTime(Minute), "2024-03-07T14:35:00+07:00"},
+		{v.TruncateTime(Hour), "2024-03-07T14:00:00+07:00"},
+		{v.TruncateTime(Day), "2024-03-07T00:00:00+07:00"},
+		{v.TruncateTime(Week), "2024-03-04T00:00:00+07:00"},
+		{v.TruncateTime(Month), "2024-03-01T00:00:00+07:00"},
+		{v.TruncateTime(Year), "2024-01-01T00:00:00+07:00"},
+		{v.RoundTime(Minute), "2024-03-07T14:36:00+07:00"},
+		{v.RoundTime(Hour), "2024-03-07T15:00:00+07:00"},
+		{v.RoundTime(Day), "2024-03-08T00:00:00+07:00"},
+		{v.RoundTime(Week), "2024-03-11T00:00:00+07:00"},
+		{v.RoundTime(Month), "2024-03-01T00:00:00+07:00"},
+	}
+	for i, tt := range tests {
+		if tt.got.Text() != tt.want {
+			t.Errorf("%d: got %s, want %s", i, tt.got.Text(), tt.want)
+		}
+	}
+	if !New("x").TruncateTime(Day).IsInvalid() {
+		t.Error("TruncateTime of non-time should be Invalid")
+	}
+
+	// 01:00-02:00 happens twice on 2024-11-03 in New York; the second, in
+	// EST, must not be bucketed into the first.
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	est := New(time.Date(2024, 11, 3, 6, 30, 15, 0, time.UTC)).In(ny)
+	for unit, want := range map[Unit]string{
+		Second: "2024-11-03T01:30:15-05:00",
+		Minute: "2024-11-03T01:30:00-05:00",
+		Hour:   "2024-11-03T01:00:00-05:00",
+		Day:    "2024-11-03T00:00:00-04:00",
+	} {
+		if got := est.TruncateTime(unit).Text(); got != want {
+			t.Errorf("TruncateTime(%d) in the DST overlap = %s, want %s", unit, got, want)
+		}
+	}
+	if got := est.RoundTime(Hour).Text(); got != "2024-11-03T02:00:00-05:00" {
+		t.Errorf("RoundTime(Hour) in the DST overlap = %s", got)
+	}
+}
+
+func TestValue_AddDate(t *testing.T) {
+	date := func(y int, m time.Month, d int) Value { return New(time.Date(y, m, d, 9, 0, 0, 0, time.UTC)) }
+	tests := []struct {
+		got, want Value
+	}{
+		{date(2024, 1, 31).AddDate(0, 1, 0), date(2024, 2, 29)},
+		{date(2023, 1, 31).AddDate(0, 1, 0), date(2023, 2, 28)},
+		{date(2024, 2, 29).AddDate(1, 0, 0), date(2025, 2, 28)},
+		{date(2024, 3, 31).AddDate(0, -1, 0), date(2024, 2, 29)},
+		{date(2024, 11, 30).AddDate(0, 3, 0), date(2025, 2, 28)},
+		{date(2024, 1, 31).AddDate(0, 1, 1), date(2024, 3, 1)},
+		{date(2024, 12, 31).AddDate(0, 0, 1), date(2025, 1, 1)},
+	}
+	for i, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Errorf("%d: got %s, want %s", i, tt.got.Text(), tt.want.Text())
+		}
+	}
+	if !New(true).AddDate(0, 1, 0).IsInvalid() {
+		t.Error("AddDate on Bool should be Invalid")
+	}
+}
+
+func TestValue_ToDuration(t *testing.T) {
+	tests := []struct {
+		in   any
+		want time.Duration
+	}{
+		{"P1DT2H30M", 26*time.Hour + 30*time.Minute},
+		{"PT0.5S", 500 * time.Millisecond},
+		{"P2W", 14 * 24 * time.Hour},
+		{"-PT1M", -time.Minute},
+		{"pt1,5h", 90 * time.Minute},
+		{"P0Y0M1D", 24 * time.Hour},
+		{"P0Y0M1W1DT1H1M1S", 8*24*time.Hour + time.Hour + time.Minute + time.Second},
+		{"1h30m", 90 * time.Minute},
+		{1.5, 1500 * time.Millisecond},
+		{time.Second, time.Second},
+	}
+	for _, tt := range tests {
+		got := New(tt.in).ToDuration()
+		if got.K != Duration || time.Duration(got.N) != tt.want {
+			t.Errorf("ToDuration(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	for _, in := range []string{"P", "PT", "P1M", "P1Y", "PT1D", "P1H", "P1DT", "P-1D", "PNAND", "PINFD", "soon",
+		"P1D1D", "PT5S3H", "PT1M1M", "P1DT1H1H", "P1D0Y", "P1DT1S1M"} {
+		if got := New(in).ToDuration(); !got.IsInvalid() {
+			t.Errorf("ToDuration(%q) = %v, want Invalid", in, got)
+		}
+	}
+}
+
+func TestValue_ISODuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "PT0S"},
+		{26*time.Hour + 30*time.Minute, "P1DT2H30M"},
+		{48 * time.Hour, "P2D"},
+		{250 * time.Millisecond, "PT0.25S"},
+		{-(time.Hour + time.Second), "-PT1H1S"},
+	}
+	for _, tt := range tests {
+		if got := New(tt.in).ISODuration().String(); got != tt.want {
+			t.Errorf("ISODuration(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+		if back := New(tt.want).ToDuration(); time.Duration(back.N) != tt.in {
+			t.Errorf("round trip %q = %v", tt.want, back)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package kit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// TokenReader is the token-level interface of *json.Decoder.
+type TokenReader interface {
+	Token() (json.Token, error)
+}
+
+// DecodeTokens reads exactly one JSON value from r, leaving the reader
+// positioned after it, so kit can consume a section of a larger stream.
+// Numbers may arrive as float64 or json.Number (Decoder.UseNumber).
+func DecodeTokens(r TokenReader) (Value, error) {
+	tok, err := r.Token()
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	return decodeToken(r, tok)
+}
+
+func decodeToken(r TokenReader, tok json.Token) (Value, error) {
+	switch t := tok.(type) {
+	case nil:
+		return Value{K: Nil}, nil
+	case bool:
+		return New(t), nil
+	case float64:
+		return Value{K: Number, N: t}, nil
+	case json.Number:
+		n, err := t.Float64()
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		return Value{K: Number, N: n}, nil
+	case string:
+		return Value{K: String, V: t}, nil
+	case json.Delim:
+		switch t {
+		case '[':
+			var out []Value
+			for {
+				tok, err := r.Token()
+				if err != nil {
+					return Value{K: Invalid}, unexpectedEOF(err)
+				}
+				if tok == json.Delim(']') {
+					if out == nil {
+						out = []Value{}
+					}
+					return Value{K: Array, V: out}, nil
+				}
+				e, err := decodeToken(r, tok)
+				if err != nil {
+					return Value{K: Invalid}, err
+				}
+				out = append(out, e)
+			}
+		case '{':
+			out := make(map[string]Value)
+			for {
+				tok, err := r.Token()
+				if err != nil {
+					return Value{K: Invalid}, unexpectedEOF(err)
+				}
+				if tok == json.Delim('}') {
+					return Value{K: Map, V: out}, nil
+				}
+				key, ok := tok.(string)
+				if !ok {
+					return Value{K: Invalid}, fmt.Errorf("kit: expected object key, got %v", tok)
+				}
+				e, err := DecodeTokens(r)
+				if err != nil {
+					return Value{K: Invalid}, unexpectedEOF(err)
+				}
+				out[key] = e
+			}
+		}
+	}
+	return Value{K: Invalid}, fmt.Errorf("kit: unexpected JSON token %v", tok)
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// EncodeTokens streams v as the token sequence Decoder.Token would produce
+// for its JSON encoding: json.Delim, string keys, float64, bool and nil.
+// It stops at the first error returned by fn.
+func (v Value) EncodeTokens(fn func(json.Token) error) error {
+	v = v.Force()
+	if v.IsNilCollection() {
+		return fn(nil)
+	}
+	switch v.K {
+	case Nil:
+		return fn(nil)
+	case Number:
+		if n, ok := v.literal(); ok {
+			return fn(n)
+		}
+		if math.IsNaN(v.N) || math.IsInf(v.N, 0) {
+			return fmt.Errorf("kit: cannot encode %v as JSON", v.N)
+		}
+		return fn(v.N)
+	case Bool:
+		return fn(v.N > 0)
+	case String:
+		return fn(v.String())
+	case Time:
+		return fn(v.goTime().Format(time.RFC3339Nano))
+	case Duration:
+		return fn(time.Duration(int64(v.N)).String())
+	case Bytes:
+		return fn(base64.StdEncoding.EncodeToString(v.Bytes()))
+	case Array:
+		if err := fn(json.Delim('[')); err != nil {
+			return err
+		}
+		for _, e := range v.V.([]Value) {
+			if err := e.EncodeTokens(fn); err != nil {
+				return err
+			}
+		}
+		return fn(json.Delim(']'))
+	case Map:
+		m := v.mapping()
+		if err := fn(json.Delim('{')); err != nil {
+			return err
+		}
+		for _, k := range sortedKeys(m) {
+			if err := fn(k); err != nil {
+				return err
+			}
+			if err := m[k].EncodeTokens(fn); err != nil {
+				return err
+			}
+		}
+		return fn(json.Delim('}'))
+	case Struct, Any:
+		data, err := json.Marshal(v.V)
+		if err != nil {
+			return err
+		}
+		x, err := FromJSON(data)
+		if err != nil {
+			return err
+		}
+		return x.EncodeTokens(fn)
+	default:
+		return fmt.Errorf("kit: cannot encode %s as JSON tokens", v.K)
+	}
+}
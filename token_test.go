@@ -0,0 +1,62 @@
+package kit
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDecodeTokens_Stream(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"items": [{"id": 1}, {"id": 2, "tags": []}], "next": null}`))
+	dec.UseNumber()
+
+	// Walk into the "items" array by hand and decode each element with kit.
+	for _, want := range []json.Token{json.Delim('{'), "items", json.Delim('[')} {
+		if tok, _ := dec.Token(); tok != want {
+			t.Fatalf("token = %v, want %v", tok, want)
+		}
+	}
+	var ids []int64
+	for dec.More() {
+		v, err := DecodeTokens(dec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, v.Get("id").Int())
+	}
+	if len(ids) != 2 || ids[1] != 2 {
+		t.Errorf("ids = %v", ids)
+	}
+}
+
+func TestValue_EncodeTokens(t *testing.T) {
+	v := New(map[string]any{"b": []any{1, "x"}, "a": nil})
+	var toks []json.Token
+	if err := v.EncodeTokens(func(tok json.Token) error {
+		toks = append(toks, tok)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []json.Token{json.Delim('{'), "a", nil, "b", json.Delim('['), 1.0, "x", json.Delim(']'), json.Delim('}')}
+	if len(toks) != len(want) {
+		t.Fatalf("tokens = %v, want %v", toks, want)
+	}
+	for i := range want {
+		if toks[i] != want[i] {
+			t.Errorf("token %d = %v, want %v", i, toks[i], want[i])
+		}
+	}
+
+	// Tokens decode back into the same Value.
+	i := 0
+	back, err := DecodeTokens(tokenSlice(func() (json.Token, error) { i++; return toks[i-1], nil }))
+	if err != nil || !back.Equal(v) {
+		t.Errorf("round trip = %v, %v", back, err)
+	}
+}
+
+type tokenSlice func() (json.Token, error)
+
+func (f tokenSlice) Token() (json.Token, error) { return f() }
@@ -0,0 +1,476 @@
+package kit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/* =============================================================================
+   TOML INPUT
+   A decoder for TOML 1.0 documents. Offset date-times become Times, local
+   date-times and dates are read in the local zone, and local times, which
+   have no date to anchor them, are kept as Strings.
+   ============================================================================= */
+
+// FromTOML decodes a TOML document into a Map.
+func FromTOML(data []byte) (Value, error) {
+	p := &tomlParser{s: string(data), line: 1}
+	root := map[string]any{}
+	cur := root
+	for {
+		p.skipSpace(true)
+		if p.i == len(p.s) {
+			break
+		}
+		var err error
+		if p.s[p.i] == '[' {
+			cur, err = p.header(root)
+		} else {
+			err = p.keyValue(cur)
+		}
+		if err == nil {
+			err = p.endOfLine()
+		}
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+	}
+	return tomlValue(root), nil
+}
+
+// Tables are built from mutable nodes and converted to Values at the end:
+// map[string]any for tables, *tomlArray for arrays of tables and Value for
+// everything that is complete once parsed, inline tables included.
+type tomlArray struct{ tables []map[string]any }
+
+func tomlValue(n any) Value {
+	switch n := n.(type) {
+	case map[string]any:
+		out := make(map[string]Value, len(n))
+		for k, e := range n {
+			out[k] = tomlValue(e)
+		}
+		return Value{K: Map, V: out}
+	case *tomlArray:
+		out := make([]Value, len(n.tables))
+		for i, t := range n.tables {
+			out[i] = tomlValue(t)
+		}
+		return Value{K: Array, V: out}
+	}
+	return n.(Value)
+}
+
+type tomlParser struct {
+	s     string
+	i     int
+	line  int
+	depth int
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("kit: toml line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips blanks and comments, and also newlines when multiline.
+func (p *tomlParser) skipSpace(multiline bool) {
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.i++
+		case c == '\n' && multiline:
+			p.i++
+			p.line++
+		case c == '#':
+			for p.i < len(p.s) && p.s[p.i] != '\n' {
+				p.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) endOfLine() error {
+	p.skipSpace(false)
+	if p.i < len(p.s) && p.s[p.i] != '\n' {
+		return p.errorf("unexpected %q at end of line", p.rest())
+	}
+	return nil
+}
+
+func (p *tomlParser) rest() string {
+	end := strings.IndexByte(p.s[p.i:], '\n')
+	if end < 0 {
+		return p.s[p.i:]
+	}
+	return p.s[p.i : p.i+end]
+}
+
+func (p *tomlParser) consume(prefix string) bool {
+	if strings.HasPrefix(p.s[p.i:], prefix) {
+		p.i += len(prefix)
+		return true
+	}
+	return false
+}
+
+// header parses [table] or [[array]] and returns the table that following
+// keys go into.
+func (p *tomlParser) header(root map[string]any) (map[string]any, error) {
+	array := p.consume("[[")
+	if !array {
+		p.i++
+	}
+	path, err := p.key()
+	if err != nil {
+		return nil, err
+	}
+	closing := "]"
+	if array {
+		closing = "]]"
+	}
+	if p.skipSpace(false); !p.consume(closing) {
+		return nil, p.errorf("expected %q after table name", closing)
+	}
+
+	parent, err := p.descend(root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+	last := path[len(path)-1]
+	switch n := parent[last].(type) {
+	case nil:
+		t := map[string]any{}
+		if array {
+			parent[last] = &tomlArray{tables: []map[string]any{t}}
+		} else {
+			parent[last] = t
+		}
+		return t, nil
+	case *tomlArray:
+		if !array {
+			return nil, p.errorf("table %s is an array of tables", strings.Join(path, "."))
+		}
+		t := map[string]any{}
+		n.tables = append(n.tables, t)
+		return t, nil
+	case map[string]any:
+		if !array {
+			return n, nil
+		}
+	}
+	return nil, p.errorf("key %s is already defined", strings.Join(path, "."))
+}
+
+// descend walks path from t, creating tables as needed; arrays of tables
+// resolve to their last element.
+func (p *tomlParser) descend(t map[string]any, path []string) (map[string]any, error) {
+	for i, k := range path {
+		switch n := t[k].(type) {
+		case nil:
+			next := map[string]any{}
+			t[k] = next
+			t = next
+		case map[string]any:
+			t = n
+		case *tomlArray:
+			t = n.tables[len(n.tables)-1]
+		default:
+			return nil, p.errorf("key %s is already defined", strings.Join(path[:i+1], "."))
+		}
+	}
+	return t, nil
+}
+
+func (p *tomlParser) keyValue(t map[string]any) error {
+	path, err := p.key()
+	if err != nil {
+		return err
+	}
+	if p.skipSpace(false); !p.consume("=") {
+		return p.errorf("expected '=' after key")
+	}
+	v, err := p.value()
+	if err != nil {
+		return err
+	}
+	t, err = p.descend(t, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	if _, dup := t[last]; dup {
+		return p.errorf("key %s is already defined", strings.Join(path, "."))
+	}
+	t[last] = v
+	return nil
+}
+
+// key parses a dotted key of bare and quoted segments.
+func (p *tomlParser) key() ([]string, error) {
+	var path []string
+	for {
+		p.skipSpace(false)
+		if p.i == len(p.s) {
+			return nil, p.errorf("expected key")
+		}
+		switch p.s[p.i] {
+		case '"', '\'':
+			s, err := p.str()
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, s)
+		default:
+			start := p.i
+			for p.i < len(p.s) && isTOMLBare(p.s[p.i]) {
+				p.i++
+			}
+			if p.i == start {
+				return nil, p.errorf("expected key, got %q", p.rest())
+			}
+			path = append(path, p.s[start:p.i])
+		}
+		if p.skipSpace(false); !p.consume(".") {
+			return path, nil
+		}
+	}
+}
+
+func isTOMLBare(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) value() (Value, error) {
+	p.skipSpace(false)
+	if p.i == len(p.s) {
+		return Value{K: Invalid}, p.errorf("expected value")
+	}
+	switch p.s[p.i] {
+	case '"', '\'':
+		s, err := p.str()
+		return Value{K: String, V: s}, err
+	case '[', '{':
+		if p.depth++; p.depth > maxNesting {
+			return Value{K: Invalid}, p.errorf("nesting exceeds %d levels", maxNesting)
+		}
+		defer func() { p.depth-- }()
+		if p.s[p.i] == '[' {
+			return p.array()
+		}
+		return p.inlineTable()
+	}
+
+	start := p.i
+	for p.i < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.i]) < 0 {
+		p.i++
+	}
+	// A date and a time may be separated by a single space.
+	if p.i-start == len("2006-01-02") && p.i+2 < len(p.s) && p.s[p.i] == ' ' && isDigit(p.s[p.i+1]) {
+		p.i++
+		for p.i < len(p.s) && strings.IndexByte(" \t\r\n,]}#", p.s[p.i]) < 0 {
+			p.i++
+		}
+	}
+	tok := p.s[start:p.i]
+	if v, ok := parseTOMLScalar(tok); ok {
+		return v, nil
+	}
+	return Value{K: Invalid}, p.errorf("invalid value %q", tok)
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func parseTOMLScalar(tok string) (Value, bool) {
+	switch tok {
+	case "true":
+		return Value{K: Bool, N: 1}, true
+	case "false":
+		return Value{K: Bool}, true
+	case "inf", "+inf":
+		return Value{K: Number, N: math.Inf(1)}, true
+	case "-inf":
+		return Value{K: Number, N: math.Inf(-1)}, true
+	case "nan", "+nan", "-nan":
+		return Value{K: Number, N: math.NaN()}, true
+	}
+	if len(tok) > 2 && tok[0] == '0' && strings.IndexByte("xob", tok[1]) >= 0 {
+		n, err := strconv.ParseInt(tok, 0, 64)
+		return Value{K: Number, N: float64(n)}, err == nil
+	}
+	if strings.Contains(tok, "__") || strings.HasPrefix(tok, "_") || strings.HasSuffix(tok, "_") {
+		return Value{}, false
+	}
+	if num := strings.ReplaceAll(tok, "_", ""); num != "" {
+		digits := strings.TrimLeft(num, "+-")
+		leadingZero := len(digits) > 1 && digits[0] == '0' && isDigit(digits[1])
+		if n, err := strconv.ParseFloat(num, 64); err == nil && !leadingZero && !math.IsInf(n, 0) && !math.IsNaN(n) {
+			return Value{K: Number, N: n}, true
+		}
+	}
+	ts := strings.Replace(tok, " ", "T", 1)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999", "2006-01-02t15:04:05.999999999Z07:00"} {
+		if t, err := time.ParseInLocation(layout, ts, time.Local); err == nil {
+			return New(t), true
+		}
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, tok, time.Local); err == nil {
+		return New(t), true
+	}
+	if _, err := time.Parse("15:04:05.999999999", tok); err == nil {
+		return Value{K: String, V: tok}, true
+	}
+	return Value{}, false
+}
+
+func (p *tomlParser) array() (Value, error) {
+	p.i++
+	out := []Value{}
+	for {
+		p.skipSpace(true)
+		if p.consume("]") {
+			return Value{K: Array, V: out}, nil
+		}
+		v, err := p.value()
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		out = append(out, v)
+		p.skipSpace(true)
+		if !p.consume(",") && !strings.HasPrefix(p.s[p.i:], "]") {
+			return Value{K: Invalid}, p.errorf("expected ',' or ']' in array")
+		}
+	}
+}
+
+func (p *tomlParser) inlineTable() (Value, error) {
+	p.i++
+	t := map[string]any{}
+	if p.skipSpace(false); p.consume("}") {
+		return tomlValue(t), nil
+	}
+	for {
+		if err := p.keyValue(t); err != nil {
+			return Value{K: Invalid}, err
+		}
+		p.skipSpace(false)
+		if p.consume("}") {
+			return tomlValue(t), nil
+		}
+		if !p.consume(",") {
+			return Value{K: Invalid}, p.errorf("expected ',' or '}' in inline table")
+		}
+	}
+}
+
+// str parses a basic, literal or multi-line string.
+func (p *tomlParser) str() (string, error) {
+	q := p.s[p.i : p.i+1]
+	if p.consume(q + q + q) {
+		// A newline right after the opening delimiter is trimmed.
+		if p.consume("\r\n") || p.consume("\n") {
+			p.line++
+		}
+		end := strings.Index(p.s[p.i:], q+q+q)
+		if end < 0 {
+			return "", p.errorf("unterminated multi-line string")
+		}
+		// Up to two quotes may directly precede the closing delimiter.
+		for n := 0; n < 2 && p.i+end+3 < len(p.s) && p.s[p.i+end+3] == q[0]; n++ {
+			end++
+		}
+		raw := p.s[p.i : p.i+end]
+		p.line += strings.Count(raw, "\n")
+		p.i += end + 3
+		if q == "'" {
+			return raw, nil
+		}
+		return p.unescape(raw, true)
+	}
+	p.i++
+	end := strings.IndexAny(p.s[p.i:], q+"\n")
+	for q == `"` && end > 0 && p.s[p.i+end] == '"' && escaped(p.s[p.i:p.i+end]) {
+		next := strings.IndexAny(p.s[p.i+end+1:], q+"\n")
+		if next < 0 {
+			end = -1
+			break
+		}
+		end += next + 1
+	}
+	if end < 0 || p.s[p.i+end] == '\n' {
+		return "", p.errorf("unterminated string")
+	}
+	raw := p.s[p.i : p.i+end]
+	p.i += end + 1
+	if q == "'" {
+		return raw, nil
+	}
+	return p.unescape(raw, false)
+}
+
+// escaped reports whether s ends with an odd number of backslashes.
+func escaped(s string) bool {
+	n := 0
+	for n < len(s) && s[len(s)-1-n] == '\\' {
+		n++
+	}
+	return n%2 == 1
+}
+
+func (p *tomlParser) unescape(s string, multiline bool) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", p.errorf("invalid escape at end of string")
+		}
+		switch c := s[i]; c {
+		case 'b':
+			b.WriteByte('\b')
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'f':
+			b.WriteByte('\f')
+		case 'r':
+			b.WriteByte('\r')
+		case '"', '\\':
+			b.WriteByte(c)
+		case 'u', 'U':
+			n := 4
+			if c == 'U' {
+				n = 8
+			}
+			if i+n >= len(s) {
+				return "", p.errorf("short \\%c escape", c)
+			}
+			r, err := strconv.ParseUint(s[i+1:i+1+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", p.errorf("invalid \\%c escape", c)
+			}
+			b.WriteRune(rune(r))
+			i += n
+		default:
+			// A line-ending backslash trims the newline and following blanks.
+			rest := strings.TrimLeft(s[i:], " \t\r")
+			if !multiline || !strings.HasPrefix(rest, "\n") {
+				return "", p.errorf("invalid escape \\%c", c)
+			}
+			i = len(s) - len(strings.TrimLeft(rest, " \t\r\n")) - 1
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,114 @@
+package kit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestFromTOML(t *testing.T) {
+	src := `# service config
+title = "kit \u00e9\t\"x\""
+literal = 'C:\path'
+"quoted key" = 1
+site."google.com" = true
+port = 8_080
+hex = 0xff
+ratio = -inf
+pi = 3.14e0
+multi = """
+one
+two \
+    three"""
+raw = '''
+a\b'''
+when = 1979-05-27T07:32:00Z
+local = 1979-05-27 07:32:00
+day = 1979-05-27
+clock = 07:32:00
+ports = [
+  8001,
+  8002, # trailing comma allowed
+]
+point = { x = 1, y.z = 2 }
+
+[db]
+host = "localhost"
+
+[db.pool]
+size = 4
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+[servers.meta]
+dc = "eu"
+`
+	v, err := FromTOML([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]any{
+		"title":             "kit é\t\"x\"",
+		"literal":           `C:\path`,
+		"quoted key":        1,
+		"port":              8080,
+		"hex":               255,
+		"pi":                3.14,
+		"multi":             "one\ntwo three",
+		"raw":               `a\b`,
+		"clock":             "07:32:00",
+		"ports.1":           8002,
+		"point.y.z":         2,
+		"db.host":           "localhost",
+		"db.pool.size":      4,
+		"servers.1.name":    "beta",
+		"servers.1.meta.dc": "eu",
+	}
+	for path, want := range cases {
+		got := v.Path(path)
+		if path == "quoted key" {
+			got = v.Get(path)
+		}
+		if !got.Equal(New(want)) {
+			t.Errorf("%s = %v, want %v", path, got, want)
+		}
+	}
+	if !v.Get("site").Get("google.com").Truthy() || v.Get("servers").Len() != 2 {
+		t.Errorf("site = %v, servers = %v", v.Get("site"), v.Get("servers"))
+	}
+	if !math.IsInf(v.Get("ratio").N, -1) {
+		t.Errorf("ratio = %v", v.Get("ratio"))
+	}
+	when := time.Date(1979, 5, 27, 7, 32, 0, 0, time.UTC)
+	if got := v.Get("when").MustTime(); !got.Equal(when) {
+		t.Errorf("when = %v", got)
+	}
+	if got := v.Get("local").MustTime(); got.Location() != time.Local || got.Hour() != 7 {
+		t.Errorf("local = %v", got)
+	}
+	if got := v.Get("day").MustTime(); got.Day() != 27 {
+		t.Errorf("day = %v", got)
+	}
+}
+
+func TestFromTOML_Errors(t *testing.T) {
+	for _, src := range []string{
+		"a = 1\na = 2",
+		"a = 1\n[a]",
+		"a = ",
+		"a = \"open",
+		"a = 1 b = 2",
+		"a = 012",
+		"a = 1__0",
+		"[t]\n[[t]]",
+		"a = [1 2]",
+		`a = "\q"`,
+	} {
+		if _, err := FromTOML([]byte(src)); err == nil {
+			t.Errorf("FromTOML(%q) succeeded", src)
+		}
+	}
+}
@@ -0,0 +1,697 @@
+package kit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+/* =============================================================================
+   YAML
+   Block-style YAML 1.2 output. Strings are emitted plain when that is
+   unambiguous and double-quoted (JSON style) otherwise.
+   ============================================================================= */
+
+// AppendYAML appends the YAML encoding of v to b, ending with a newline.
+func (v Value) AppendYAML(b []byte) ([]byte, error) {
+	v = v.Force()
+	if (v.K == Array || v.K == Map) && v.Len() > 0 {
+		return v.appendYAMLBlock(b, 0)
+	}
+	b, err := v.appendYAMLScalar(b)
+	return append(b, '\n'), err
+}
+
+func appendIndent(b []byte, n int) []byte {
+	for i := 0; i < n; i++ {
+		b = append(b, ' ')
+	}
+	return b
+}
+
+// appendYAMLBlock writes a non-empty Map or Array starting at the current
+// line position; every line after the first is indented by indent.
+func (v Value) appendYAMLBlock(b []byte, indent int) ([]byte, error) {
+	var err error
+	first := true
+	writeEntry := func(prefix func([]byte) []byte, e Value) {
+		if err != nil {
+			return
+		}
+		if !first {
+			b = appendIndent(b, indent)
+		}
+		first = false
+		b = prefix(b)
+		e = e.Force()
+		switch {
+		case e.K == Map && e.Len() > 0 && v.K == Array:
+			b = append(b, ' ')
+			b, err = e.appendYAMLBlock(b, indent+2)
+		case (e.K == Map || e.K == Array) && e.Len() > 0:
+			b = append(b, '\n')
+			b = appendIndent(b, indent+2)
+			b, err = e.appendYAMLBlock(b, indent+2)
+		default:
+			b = append(b, ' ')
+			b, err = e.appendYAMLScalar(b)
+			b = append(b, '\n')
+		}
+	}
+
+	if v.K == Array {
+		for _, e := range v.V.([]Value) {
+			writeEntry(func(b []byte) []byte { return append(b, '-') }, e)
+		}
+		return b, err
+	}
+	m := v.mapping()
+	for _, k := range sortedKeys(m) {
+		writeEntry(func(b []byte) []byte {
+			return append(appendYAMLString(b, k), ':')
+		}, m[k])
+	}
+	return b, err
+}
+
+func (v Value) appendYAMLScalar(b []byte) ([]byte, error) {
+	if v.IsNilCollection() {
+		return append(b, "null"...), nil
+	}
+	switch v.K {
+	case Nil:
+		return append(b, "null"...), nil
+	case Number:
+		if n, ok := v.literal(); ok {
+			return append(b, n...), nil
+		}
+		switch {
+		case math.IsNaN(v.N):
+			return append(b, ".nan"...), nil
+		case math.IsInf(v.N, 1):
+			return append(b, ".inf"...), nil
+		case math.IsInf(v.N, -1):
+			return append(b, "-.inf"...), nil
+		}
+		return v.Append(b), nil
+	case Bool:
+		return v.Append(b), nil
+	case Time:
+		return v.goTime().AppendFormat(b, time.RFC3339Nano), nil
+	case Duration:
+		return append(b, v.Text()...), nil
+	case String:
+		return appendYAMLString(b, v.String()), nil
+	case Bytes:
+		b = append(b, "!!binary "...)
+		return base64.StdEncoding.AppendEncode(b, v.Bytes()), nil
+	case Array:
+		if v.Len() == 0 {
+			return append(b, "[]"...), nil
+		}
+	case Map:
+		if v.Len() == 0 {
+			return append(b, "{}"...), nil
+		}
+	case Invalid, Func, Error:
+		return b, fmt.Errorf("kit: cannot encode %s as YAML", v.K)
+	default:
+		// Go values are encoded through their JSON form, which is valid YAML.
+		data, err := json.Marshal(v.V)
+		return append(b, data...), err
+	}
+	return b, fmt.Errorf("kit: cannot encode %s as a YAML scalar", v.K)
+}
+
+func appendYAMLString(b []byte, s string) []byte {
+	if yamlNeedsQuote(s) {
+		return appendJSONString(b, s)
+	}
+	return append(b, s...)
+}
+
+func yamlNeedsQuote(s string) bool {
+	if s == "" || s != strings.TrimSpace(s) {
+		return true
+	}
+	switch strings.ToLower(s) {
+	case "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n", ".nan", ".inf", "-.inf":
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	// Anything FromYAML would type, such as 1.5, 0x1F, 1h or a date.
+	if v, err := parseYAMLScalar(s); err != nil || v.K != String {
+		return true
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
+
+/* =============================================================================
+   YAML INPUT
+   FromYAML reads the subset of YAML that configuration files use: block
+   Maps and Arrays, single-line flow collections, plain, quoted and block
+   (| and >) scalars, and comments. Anchors, aliases, tags other than !!str
+   and !!binary, and streams of several documents are rejected.
+   ============================================================================= */
+
+// FromYAML decodes a YAML document into a Value, the inverse of AppendYAML.
+// Plain scalars are typed by the YAML 1.2 core schema (null, ~, true, .inf,
+// 0x1F…) and otherwise as with FromText, so RFC 3339 timestamps and Go
+// durations are recognised too. An empty document is Nil.
+func FromYAML(data []byte) (Value, error) {
+	p := &yamlParser{}
+	started := false
+	for i, raw := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(trimmed, "\t") && strings.TrimSpace(trimmed) != "" {
+			return Value{K: Invalid}, fmt.Errorf("kit: yaml line %d: tab in indentation", i+1)
+		}
+		l := yamlLine{num: i + 1, indent: len(raw) - len(trimmed), text: stripYAMLComment(trimmed), raw: raw}
+		if l.indent == 0 {
+			switch {
+			case l.text == "..." || strings.HasPrefix(l.text, "... "):
+				goto parse
+			case l.text == "---" || strings.HasPrefix(l.text, "--- "):
+				if started {
+					return Value{K: Invalid}, fmt.Errorf("kit: yaml line %d: multiple documents are not supported", i+1)
+				}
+				l.text = strings.TrimSpace(l.text[3:])
+			case strings.HasPrefix(l.text, "%") && !started:
+				l.text = ""
+			}
+		}
+		started = started || l.text != ""
+		p.lines = append(p.lines, l)
+	}
+parse:
+	p.skipBlank()
+	if p.pos == len(p.lines) {
+		return Value{K: Nil}, nil
+	}
+	v, err := p.parseBlock(p.lines[p.pos].indent)
+	if err != nil {
+		return Value{K: Invalid}, err
+	}
+	if p.skipBlank(); p.pos < len(p.lines) {
+		return Value{K: Invalid}, p.errorf("unexpected content")
+	}
+	return v, nil
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	text   string // without indentation and comment
+	raw    string // the full line, for block scalars
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+	depth int
+}
+
+func (p *yamlParser) errorf(format string, args ...any) error {
+	num := len(p.lines)
+	if p.pos < len(p.lines) {
+		num = p.lines[p.pos].num
+	}
+	return fmt.Errorf("kit: yaml line %d: %s", num, fmt.Sprintf(format, args...))
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && p.lines[p.pos].text == "" {
+		p.pos++
+	}
+}
+
+func isYAMLSeqItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// parseBlock parses the node whose first line is the current one, which is
+// indented by indent.
+func (p *yamlParser) parseBlock(indent int) (Value, error) {
+	if p.depth++; p.depth > maxNesting {
+		return Value{K: Invalid}, p.errorf("nesting exceeds %d levels", maxNesting)
+	}
+	defer func() { p.depth-- }()
+	l := p.lines[p.pos]
+	if isYAMLSeqItem(l.text) {
+		return p.parseSeq(indent)
+	}
+	if _, _, ok, err := splitYAMLKey(l.text); err != nil {
+		return Value{K: Invalid}, p.errorf("%v", err)
+	} else if ok {
+		return p.parseMap(indent)
+	}
+	p.pos++
+	v, err := parseYAMLScalar(l.text)
+	if err != nil {
+		p.pos--
+		return v, p.errorf("%v", err)
+	}
+	return v, nil
+}
+
+// parseChild parses the node nested under a "key:" or "-" with nothing
+// after it. A key's value may also be a sequence at the key's own indent.
+func (p *yamlParser) parseChild(parent int, seqSameIndent bool) (Value, error) {
+	p.skipBlank()
+	if p.pos == len(p.lines) {
+		return Value{K: Nil}, nil
+	}
+	l := p.lines[p.pos]
+	if l.indent > parent || seqSameIndent && l.indent == parent && isYAMLSeqItem(l.text) {
+		return p.parseBlock(l.indent)
+	}
+	return Value{K: Nil}, nil
+}
+
+func (p *yamlParser) parseSeq(indent int) (Value, error) {
+	out := []Value{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		l := p.lines[p.pos]
+		if l.indent < indent || l.indent == indent && !isYAMLSeqItem(l.text) {
+			break
+		}
+		if l.indent > indent {
+			return Value{K: Invalid}, p.errorf("unexpected indentation")
+		}
+		rest := strings.TrimLeft(l.text[1:], " ")
+		var v Value
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			v, err = p.parseChild(indent, false)
+		case rest[0] == '|' || rest[0] == '>':
+			p.pos++
+			v, err = p.blockScalar(rest, indent)
+		default:
+			// Re-read the remainder as a line of its own at its column, so
+			// "- key: v" starts a Map whose other keys align with "key".
+			col := indent + len(l.text) - len(rest)
+			p.lines[p.pos].indent, p.lines[p.pos].text = col, rest
+			v, err = p.parseBlock(col)
+		}
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		out = append(out, v)
+	}
+	return Value{K: Array, V: out}, nil
+}
+
+func (p *yamlParser) parseMap(indent int) (Value, error) {
+	out := map[string]Value{}
+	for p.skipBlank(); p.pos < len(p.lines); p.skipBlank() {
+		l := p.lines[p.pos]
+		if l.indent < indent {
+			break
+		}
+		if l.indent > indent {
+			return Value{K: Invalid}, p.errorf("unexpected indentation")
+		}
+		key, rest, ok, err := splitYAMLKey(l.text)
+		switch {
+		case err != nil:
+			return Value{K: Invalid}, p.errorf("%v", err)
+		case !ok:
+			return Value{K: Invalid}, p.errorf("expected \"key: value\"")
+		}
+		if _, dup := out[key]; dup {
+			return Value{K: Invalid}, p.errorf("duplicate key %q", key)
+		}
+		p.pos++
+
+		var v Value
+		switch {
+		case rest == "":
+			v, err = p.parseChild(indent, true)
+		case rest[0] == '|' || rest[0] == '>':
+			v, err = p.blockScalar(rest, indent)
+		default:
+			if !strings.ContainsRune("\"'[{", rune(rest[0])) {
+				rest = p.continuePlain(rest, indent)
+			}
+			if v, err = parseYAMLScalar(rest); err != nil {
+				p.pos--
+				err = p.errorf("%v", err)
+			}
+		}
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		out[key] = v
+	}
+	return Value{K: Map, V: out}, nil
+}
+
+// continuePlain folds the more-indented lines that continue a multi-line
+// plain scalar into s. A line that looks like a Map entry is left for the
+// caller to reject.
+func (p *yamlParser) continuePlain(s string, indent int) string {
+	parts := []string{s}
+	for p.skipBlank(); p.pos < len(p.lines) && p.lines[p.pos].indent > indent; p.skipBlank() {
+		l := p.lines[p.pos]
+		if _, _, ok, _ := splitYAMLKey(l.text); ok || isYAMLSeqItem(l.text) {
+			break
+		}
+		parts = append(parts, l.text)
+		p.pos++
+	}
+	return strings.Join(parts, " ")
+}
+
+// blockScalar reads a literal (|) or folded (>) scalar with the given
+// header from the lines indented deeper than parent.
+func (p *yamlParser) blockScalar(header string, parent int) (Value, error) {
+	chomp, explicit := byte(0), 0
+	for _, c := range []byte(header[1:]) {
+		switch {
+		case c == '-' || c == '+':
+			chomp = c
+		case c >= '1' && c <= '9':
+			explicit = int(c - '0')
+		default:
+			p.pos--
+			return Value{K: Invalid}, p.errorf("invalid block scalar header %q", header)
+		}
+	}
+
+	indent := -1
+	if explicit > 0 {
+		indent = parent + explicit
+	}
+	var body []string
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+		if strings.TrimSpace(raw) == "" {
+			body = append(body, "")
+			continue
+		}
+		n := len(raw) - len(strings.TrimLeft(raw, " "))
+		if indent < 0 {
+			indent = n
+		}
+		if n <= parent || n < indent {
+			break
+		}
+		body = append(body, raw[indent:])
+	}
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+
+	var b strings.Builder
+	for i, ln := range body {
+		switch {
+		case i == 0:
+		case header[0] == '|':
+			b.WriteByte('\n')
+		case ln != "" && body[i-1] == "":
+			// the empty lines before ln already stand for the line breaks
+		case ln == "" || strings.HasPrefix(ln, " ") || strings.HasPrefix(body[i-1], " "):
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(ln)
+	}
+	if len(body) > 0 {
+		switch chomp {
+		case 0:
+			b.WriteByte('\n')
+		case '+':
+			b.WriteString(strings.Repeat("\n", trailing+1))
+		}
+	}
+	return Value{K: String, V: b.String()}, nil
+}
+
+// stripYAMLComment removes a trailing "# comment" outside quoted scalars,
+// then trailing spaces.
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimRight(s[:i], " \t")
+		case (c == '"' || c == '\'') && (i == 0 || strings.IndexByte(" \t[{,", s[i-1]) >= 0):
+			quote = c
+		}
+	}
+	return strings.TrimRight(s, " \t")
+}
+
+// splitYAMLKey splits "key: rest" and reports whether text is a Map entry.
+func splitYAMLKey(text string) (key, rest string, ok bool, err error) {
+	if text == "" || text[0] == '[' || text[0] == '{' {
+		return "", "", false, nil
+	}
+	i := 0
+	if c := text[0]; c == '"' || c == '\'' {
+		end := quotedEnd(text)
+		if end < 0 {
+			return "", "", false, nil
+		}
+		after := strings.TrimLeft(text[end:], " ")
+		if after != ":" && !strings.HasPrefix(after, ": ") {
+			return "", "", false, nil
+		}
+		k, err := parseYAMLScalar(text[:end])
+		if err != nil {
+			return "", "", false, err
+		}
+		return k.Text(), strings.TrimSpace(after[1:]), true, nil
+	}
+	for ; i < len(text); i++ {
+		if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+			return strings.TrimRight(text[:i], " "), strings.TrimSpace(text[i+1:]), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// quotedEnd returns the index just past the quoted scalar at the start of
+// s, or -1 when it is unterminated.
+func quotedEnd(s string) int {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q:
+			if q == '\'' && i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return -1
+}
+
+func parseYAMLScalar(s string) (Value, error) {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return Value{K: Nil}, nil
+	case "true", "True", "TRUE":
+		return Value{K: Bool, N: 1}, nil
+	case "false", "False", "FALSE":
+		return Value{K: Bool}, nil
+	case ".nan", ".NaN", ".NAN":
+		return Value{K: Number, N: math.NaN()}, nil
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return Value{K: Number, N: math.Inf(1)}, nil
+	case "-.inf", "-.Inf", "-.INF":
+		return Value{K: Number, N: math.Inf(-1)}, nil
+	}
+	switch s[0] {
+	case '"', '\'':
+		if quotedEnd(s) != len(s) {
+			return Value{K: Invalid}, fmt.Errorf("malformed quoted scalar %s", s)
+		}
+		if s[0] == '\'' {
+			return Value{K: String, V: strings.ReplaceAll(s[1:len(s)-1], "''", "'")}, nil
+		}
+		str, err := strconv.Unquote(s)
+		if err != nil {
+			var js string
+			if json.Unmarshal([]byte(s), &js) != nil {
+				return Value{K: Invalid}, fmt.Errorf("malformed quoted scalar %s", s)
+			}
+			str = js
+		}
+		return Value{K: String, V: str}, nil
+	case '[', '{':
+		f := yamlFlow{s: s}
+		v, err := f.value(false)
+		if f.skipSpace(); err == nil && f.i < len(s) {
+			err = fmt.Errorf("unexpected %q after flow collection", s[f.i:])
+		}
+		return v, err
+	case '!':
+		tag, rest, _ := strings.Cut(s, " ")
+		rest = strings.TrimSpace(rest)
+		switch tag {
+		case "!!str":
+			if rest != "" && (rest[0] == '"' || rest[0] == '\'') {
+				return parseYAMLScalar(rest)
+			}
+			return Value{K: String, V: rest}, nil
+		case "!!binary":
+			b, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(rest, " ", ""))
+			if err != nil {
+				return Value{K: Invalid}, fmt.Errorf("invalid !!binary: %v", err)
+			}
+			return Value{K: Bytes, V: b}, nil
+		}
+		return Value{K: Invalid}, fmt.Errorf("unsupported tag %s", tag)
+	case '&', '*':
+		return Value{K: Invalid}, fmt.Errorf("anchors and aliases are not supported")
+	}
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'o') {
+		if n, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return Value{K: Number, N: float64(n)}, nil
+		}
+	}
+	if len(s) == len("2006-01-02") {
+		if t, err := time.Parse(time.DateOnly, s); err == nil {
+			return New(t), nil
+		}
+	}
+	return FromText(s), nil
+}
+
+// yamlFlow parses a flow collection such as [a, {b: 1}].
+type yamlFlow struct {
+	s     string
+	i     int
+	depth int
+}
+
+func (f *yamlFlow) skipSpace() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+// value parses one flow node; key reports whether it is a Map key, which
+// ends at the first ':'.
+func (f *yamlFlow) value(key bool) (Value, error) {
+	f.skipSpace()
+	if f.i == len(f.s) {
+		return Value{K: Invalid}, fmt.Errorf("unterminated flow collection")
+	}
+	switch c := f.s[f.i]; c {
+	case '[', '{':
+		if f.depth++; f.depth > maxNesting {
+			return Value{K: Invalid}, fmt.Errorf("nesting exceeds %d levels", maxNesting)
+		}
+		defer func() { f.depth-- }()
+		f.i++
+		if c == '[' {
+			return f.seq()
+		}
+		return f.mapping()
+	case '"', '\'':
+		end := quotedEnd(f.s[f.i:])
+		if end < 0 {
+			return Value{K: Invalid}, fmt.Errorf("unterminated quoted scalar")
+		}
+		start := f.i
+		f.i += end
+		return parseYAMLScalar(f.s[start:f.i])
+	}
+	start := f.i
+	for f.i < len(f.s) && !strings.ContainsRune(",]}", rune(f.s[f.i])) && !(key && f.s[f.i] == ':') {
+		f.i++
+	}
+	return parseYAMLScalar(strings.TrimRight(f.s[start:f.i], " "))
+}
+
+func (f *yamlFlow) seq() (Value, error) {
+	out := []Value{}
+	for {
+		if f.skipSpace(); f.i < len(f.s) && f.s[f.i] == ']' {
+			f.i++
+			return Value{K: Array, V: out}, nil
+		}
+		v, err := f.value(false)
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		out = append(out, v)
+		if err := f.sep(']'); err != nil {
+			return Value{K: Invalid}, err
+		}
+	}
+}
+
+func (f *yamlFlow) mapping() (Value, error) {
+	out := map[string]Value{}
+	for {
+		if f.skipSpace(); f.i < len(f.s) && f.s[f.i] == '}' {
+			f.i++
+			return Value{K: Map, V: out}, nil
+		}
+		k, err := f.value(true)
+		if err != nil {
+			return Value{K: Invalid}, err
+		}
+		v := Value{K: Nil}
+		if f.skipSpace(); f.i < len(f.s) && f.s[f.i] == ':' {
+			f.i++
+			if v, err = f.value(false); err != nil {
+				return Value{K: Invalid}, err
+			}
+		}
+		out[k.Text()] = v
+		if err := f.sep('}'); err != nil {
+			return Value{K: Invalid}, err
+		}
+	}
+}
+
+// sep consumes the ',' between entries, leaving a closing bracket for the
+// caller.
+func (f *yamlFlow) sep(closing byte) error {
+	f.skipSpace()
+	switch {
+	case f.i == len(f.s):
+		return fmt.Errorf("unterminated flow collection")
+	case f.s[f.i] == ',':
+		f.i++
+		return nil
+	case f.s[f.i] == closing:
+		return nil
+	}
+	return fmt.Errorf("unexpected %q in flow collection", f.s[f.i])
+}
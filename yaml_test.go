@@ -0,0 +1,165 @@
+package kit
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValue_AppendYAML(t *testing.T) {
+	v := New(map[string]any{
+		"name":  "kit",
+		"port":  "8080",
+		"empty": map[string]any{},
+		"users": []any{
+			map[string]any{"id": 1, "roles": []string{"admin"}},
+			"yes",
+		},
+	})
+	got, err := v.AppendYAML(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `empty: {}
+name: kit
+port: "8080"
+users:
+  - id: 1
+    roles:
+      - admin
+  - "yes"
+`
+	if string(got) != want {
+		t.Errorf("AppendYAML =\n%s\nwant\n%s", got, want)
+	}
+
+	for _, c := range []struct {
+		v    Value
+		want string
+	}{
+		{New(map[string]any{}), "{}\n"},
+		{New([]any{}), "[]\n"},
+		{New(90 * time.Minute), "1h30m0s\n"},
+		{New("1h"), "\"1h\"\n"},
+		{New("0x1F"), "\"0x1F\"\n"},
+	} {
+		if got, _ := c.v.AppendYAML(nil); string(got) != c.want {
+			t.Errorf("AppendYAML(%s) = %q, want %q", c.v, got, c.want)
+		}
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	src := `%YAML 1.2
+---
+# service config
+name: kit   # trailing comment
+port: 8080
+quoted: "8080"
+single: 'it''s # not a comment'
+url: http://example.com:80/x
+timeout: 1h30m
+started: 2024-01-02T03:04:05Z
+ratio: .inf
+hex: 0x1F
+empty:
+tags: [a, "b c", {k: v}]
+flow: {x: 1, y: [true, ~]}
+db:
+  host: localhost
+  replicas:
+  - host: r1
+    port: 1
+  - host: r2
+users:
+  - id: 1
+    roles:
+      - admin
+  - "yes"
+note: |
+  line one
+    indented
+
+  last
+folded: >-
+  one
+  two
+
+  three
+long: this plain
+  scalar continues
+raw: !!binary aGk=
+`
+	v, err := FromYAML([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]any{
+		"name":               "kit",
+		"port":               8080.0,
+		"quoted":             "8080",
+		"single":             "it's # not a comment",
+		"url":                "http://example.com:80/x",
+		"hex":                31.0,
+		"tags.1":             "b c",
+		"tags.2.k":           "v",
+		"flow.x":             1.0,
+		"flow.y.0":           true,
+		"db.host":            "localhost",
+		"db.replicas.0.port": 1.0,
+		"db.replicas.1.host": "r2",
+		"users.0.roles.0":    "admin",
+		"users.1":            "yes",
+		"note":               "line one\n  indented\n\nlast\n",
+		"folded":             "one two\nthree",
+		"long":               "this plain scalar continues",
+		"raw":                []byte("hi"),
+	}
+	for path, want := range cases {
+		if got := v.Path(path); !got.Equal(New(want)) {
+			t.Errorf("%s = %v, want %v", path, got, want)
+		}
+	}
+	if v.Get("timeout").K != Duration || v.Get("started").K != Time {
+		t.Errorf("timeout = %s, started = %s", v.Get("timeout").K, v.Get("started").K)
+	}
+	if !v.Exists("empty") || !v.Get("empty").IsNil() || !v.Path("flow.y.1").IsNil() {
+		t.Error("null entries")
+	}
+	if !math.IsInf(v.Get("ratio").N, 1) {
+		t.Errorf("ratio = %v", v.Get("ratio"))
+	}
+
+	out, err := v.AppendYAML(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	back, err := FromYAML(out)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if !back.Equal(v) {
+		t.Errorf("round trip changed the value:\n%s", out)
+	}
+}
+
+func TestFromYAML_Errors(t *testing.T) {
+	for _, src := range []string{
+		"a: 1\n  b: 2",
+		"a: 1\na: 2",
+		"a: [1, 2",
+		"a: \"open",
+		"a: &anchor 1",
+		"a: !!custom x",
+		"\ta: 1",
+		"a: 1\n---\nb: 2",
+		"- a\nb: 1",
+	} {
+		if _, err := FromYAML([]byte(src)); err == nil {
+			t.Errorf("FromYAML(%q) succeeded", src)
+		}
+	}
+	if v, err := FromYAML([]byte("# nothing\n")); err != nil || !v.IsNil() {
+		t.Errorf("empty document = %v, %v", v, err)
+	}
+}